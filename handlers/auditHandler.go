@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+type ListAuditLogsRequest struct {
+	EntityType string     `form:"entity_type" binding:"omitempty,oneof=hub sku inventory"`
+	EntityID   string     `form:"entity_id"`
+	ActorID    string     `form:"actor_id"`
+	Action     string     `form:"action" binding:"omitempty,oneof=create update delete"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	LogPageRequest
+}
+
+func ListAuditLogsHandler(c *gin.Context) {
+	var req ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+
+	tenantID, err := inventory.TenantIDFromContext(c)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	entries, meta, err := audit.Query(c, tenantID, audit.Filter{
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		ActorID:    req.ActorID,
+		Action:     audit.Action(req.Action),
+		From:       req.From,
+		To:         req.To,
+	}, req.page())
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithMeta(c, http.StatusOK, entries, meta)
+}
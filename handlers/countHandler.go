@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/jwt/public"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/internal/permission"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+// allowed checks a permission the route middleware could not decide on its own, writing
+// the 403 response and returning false when the caller's role lacks it.
+func allowed(c *gin.Context, action permission.Action) bool {
+	ok, err := permission.Allowed(c, action)
+	if err != nil {
+		log.WithError(err).Error("permission check failed")
+	}
+	if !ok {
+		permission.AbortForbidden(c)
+		return false
+	}
+	return true
+}
+
+type SubmitCountRequest struct {
+	HubID      int64  `json:"hub_id" binding:"required"`
+	SKUID      int64  `json:"sku_id" binding:"required"`
+	CountedQty *int64 `json:"counted_qty" binding:"required,min=0"`
+}
+
+func SubmitCountHandler(c *gin.Context) {
+	var req SubmitCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	userID, _ := public.GetUserID(c)
+	count, err := inventory.SubmitCount(c, req.HubID, req.SKUID, *req.CountedQty, userID)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusCreated, count)
+}
+
+type ListCountsRequest struct {
+	HubID  *int64 `form:"hub_id"`
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	LogPageRequest
+}
+
+func ListCountsHandler(c *gin.Context) {
+	var req ListCountsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	counts, meta, err := inventory.ListCounts(c, req.HubID, inventory.CountStatus(req.Status), req.page())
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithMeta(c, http.StatusOK, counts, meta)
+}
+
+// ResolveCountResponse is the resolved count and any warnings from applying its variance.
+type ResolveCountResponse struct {
+	Count    *inventory.InventoryCount `json:"count"`
+	Warnings []string                  `json:"warnings"`
+}
+
+func (h *Handler) ApproveCountHandler(c *gin.Context) {
+	h.resolveCount(c, true)
+}
+
+func (h *Handler) RejectCountHandler(c *gin.Context) {
+	h.resolveCount(c, false)
+}
+
+func (h *Handler) resolveCount(c *gin.Context, approve bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithError(c, oerror.RequestInvalid, "invalid count id")
+		return
+	}
+	existing, err := inventory.GetCount(c, id)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	if existing == nil {
+		respondWithInventoryError(c, inventory.ErrCountNotFound)
+		return
+	}
+	if !h.responses.Authorize(c, existing) {
+		return
+	}
+
+	userID, _ := public.GetUserID(c)
+	count, result, err := inventory.ResolveCount(c, id, approve, userID)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, ResolveCountResponse{Count: count, Warnings: result.Warnings})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/internal/consumer"
+)
+
+type ListDeadLettersRequest struct {
+	Handler string `form:"handler"`
+	Topic   string `form:"topic"`
+	Status  string `form:"status" binding:"omitempty,oneof=pending replay_requested replaying replayed"`
+	LogPageRequest
+}
+
+func ListDeadLettersHandler(c *gin.Context) {
+	var req ListDeadLettersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	letters, meta, err := consumer.ListDeadLetters(c, consumer.DeadLetterFilter{
+		Handler: req.Handler,
+		Topic:   req.Topic,
+		Status:  consumer.DeadLetterStatus(req.Status),
+	}, req.page())
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithMeta(c, http.StatusOK, letters, meta)
+}
+
+func ReplayDeadLetterHandler(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithError(c, oerror.RequestInvalid, "invalid dead letter id")
+		return
+	}
+	letter, err := consumer.RequestReplay(c, id)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusAccepted, letter)
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/ims_rohit/internal/apierror"
+	"github.com/omniful/ims_rohit/pkg/response"
+)
+
+// respondWithInventoryError writes the error envelope for an error of the inventory,
+// webhook or consumer packages, see apierror for the codes and messages.
+func respondWithInventoryError(c *gin.Context, err error) {
+	response.NewErrorResponse(c, apierror.New(err), nil)
+}
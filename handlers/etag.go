@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+)
+
+// setETag exposes a hub's or SKU's version so clients can send it back in If-Match.
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatchVersion reads the version a write is conditional on from the If-Match header:
+// "N" or W/"N" as sent in ETag. A missing header or * means unconditional and gives 0.
+// On a malformed header it writes a 400 response and returns false.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err == nil {
+		var version int64
+		if version, err = strconv.ParseInt(tag, 10, 64); err == nil && version > 0 {
+			return version, true
+		}
+	}
+	respondWithError(c, oerror.RequestInvalid, "If-Match must be a single version ETag such as \"3\"")
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		header  string
+		want    int64
+		wantOK  bool
+		comment string
+	}{
+		{"", 0, true, "no header"},
+		{"*", 0, true, "any version"},
+		{`"3"`, 3, true, "strong tag"},
+		{` W/"12" `, 12, true, "weak tag with spaces"},
+		{"3", 0, false, "unquoted"},
+		{`"0"`, 0, false, "no stored version is 0"},
+		{`"-1"`, 0, false, "negative"},
+		{`"3", "4"`, 0, false, "several tags"},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodPut, "/hubs/1", nil)
+		if c.header != "" {
+			ctx.Request.Header.Set("If-Match", c.header)
+		}
+		got, ok := ifMatchVersion(ctx)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("%s: got %d, %v, want %d, %v", c.comment, got, ok, c.want, c.wantOK)
+		}
+		if !ok && w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want 400", c.comment, w.Code)
+		}
+	}
+}
+
+func TestSetETagRoundTrips(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	setETag(ctx, 7)
+
+	ctx.Request = httptest.NewRequest(http.MethodPut, "/hubs/1", nil)
+	ctx.Request.Header.Set("If-Match", w.Header().Get("ETag"))
+	if version, ok := ifMatchVersion(ctx); !ok || version != 7 {
+		t.Fatalf("got %d, %v for ETag %s, want version 7", version, ok, w.Header().Get("ETag"))
+	}
+}
+
+func TestHubUpdatesHonourIfMatch(t *testing.T) {
+	r, _ := testServer(t)
+	var created HubResponse
+	if w := call(t, r, http.MethodPost, "/hubs", `{"name":"Riyadh DC"}`, &created); w.Code != http.StatusCreated {
+		t.Fatalf("create: got %d: %s", w.Code, w.Body)
+	}
+	path := "/hubs/" + jsonInt(created.ID)
+
+	update := func(ifMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"name":"Riyadh North DC"}`))
+		req.Header.Set("If-Match", ifMatch)
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := update(`"2"`); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("stale If-Match: got %d, want 412", w.Code)
+	}
+	w := update(`"1"`)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("current If-Match: got %d, ETag %q, want 200 and version 2", w.Code, w.Header().Get("ETag"))
+	}
+	if w := update(`"1"`); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("replayed If-Match: got %d, want 412", w.Code)
+	}
+}
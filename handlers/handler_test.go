@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/ims_rohit/inventory"
+	"github.com/omniful/ims_rohit/pkg/response"
+)
+
+// allowAll lets the caller access every hub and seller.
+type allowAll struct{}
+
+func (allowAll) ValidateHubIDs(context.Context, string, []string) (bool, error)    { return true, nil }
+func (allowAll) ValidateSellerIDs(context.Context, string, []string) (bool, error) { return true, nil }
+
+// testServer routes requests of tenant 1 to a Handler on a MemoryRepository.
+func testServer(t *testing.T) (*gin.Engine, *inventory.MemoryRepository) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	repo := inventory.NewMemoryRepository()
+	h := New(repo, repo, repo, response.NewResponseHandler(allowAll{}))
+
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(inventory.WithTenantID(c.Request.Context(), 1))
+	})
+	r.POST("/hubs", h.CreateHubHandler)
+	r.PUT("/hubs/:id", h.UpdateHubHandler)
+	r.POST("/inventory/set", h.SetInventoryHandler)
+	r.POST("/inventory/upsert", h.UpsertInventoryHandler)
+	r.POST("/inventory/view", h.ViewInventoryHandler)
+	return r, repo
+}
+
+// call sends body to the route and decodes the data of the response into data.
+func call(t *testing.T, r *gin.Engine, method, path, body string, data interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if data != nil && w.Code < http.StatusBadRequest {
+		envelope := struct {
+			Data json.RawMessage `json:"data"`
+		}{}
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return w
+}
+
+func TestHubWritesRespondWithTheStoredHub(t *testing.T) {
+	r, _ := testServer(t)
+
+	var created HubResponse
+	w := call(t, r, http.MethodPost, "/hubs", `{"name":"Riyadh DC"}`, &created)
+	if w.Code != http.StatusCreated || w.Header().Get("ETag") != `"1"` {
+		t.Fatalf("create: got %d, ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+	if created.ID == 0 || created.Status != inventory.HubStatusActive || created.Version != 1 || created.CreatedAt.IsZero() {
+		t.Fatalf("create: got %+v, want the stored hub", created.Hub)
+	}
+
+	path := "/hubs/" + jsonInt(created.ID)
+	if w := call(t, r, http.MethodPut, path, `{"name":"Riyadh DC","status":"paused"}`, nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("update with status: got %d, want 400", w.Code)
+	}
+	var updated HubResponse
+	if w := call(t, r, http.MethodPut, path, `{"name":"Riyadh North DC"}`, &updated); w.Code != http.StatusOK {
+		t.Fatalf("update: got %d: %s", w.Code, w.Body)
+	}
+	if updated.Name != "Riyadh North DC" || updated.Version != 2 || updated.Status != inventory.HubStatusActive ||
+		!updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("update: got %+v, want the stored hub at version 2", updated.Hub)
+	}
+}
+
+func TestInventoryHandlersWriteAndReadStock(t *testing.T) {
+	r, repo := testServer(t)
+	ctx := inventory.WithTenantID(context.Background(), 1)
+	hubID, err := repo.CreateHub(ctx, &inventory.Hub{Name: "Riyadh DC"})
+	if err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+	skuID, err := repo.CreateSKU(ctx, &inventory.SKU{SellerID: 7, SKUCode: "MUG-01", Name: "Mug"})
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+	hub, sku := jsonInt(hubID), jsonInt(skuID)
+
+	if w := call(t, r, http.MethodPost, "/inventory/set", `{"hub_id":`+hub+`,"sku_id":`+sku+`,"qty":10}`, nil); w.Code != http.StatusOK {
+		t.Fatalf("set: got %d: %s", w.Code, w.Body)
+	}
+	if w := call(t, r, http.MethodPost, "/inventory/upsert", `{"hub_id":`+hub+`,"sku_id":`+sku+`,"qty":-3}`, nil); w.Code != http.StatusOK {
+		t.Fatalf("upsert: got %d: %s", w.Code, w.Body)
+	}
+	if w := call(t, r, http.MethodPost, "/inventory/upsert", `{"hub_id":`+hub+`,"sku_id":999,"qty":-1}`, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("upsert of a missing SKU: got %d, want 404", w.Code)
+	}
+
+	var invs []*inventory.Inventory
+	if w := call(t, r, http.MethodPost, "/inventory/view", `{"hub_id":`+hub+`,"sku_ids":[`+sku+`]}`, &invs); w.Code != http.StatusOK {
+		t.Fatalf("view: got %d: %s", w.Code, w.Body)
+	}
+	if len(invs) != 1 || invs[0].Qty != 7 || invs[0].Version != 2 {
+		t.Fatalf("view: got %+v, want quantity 7 at version 2", invs)
+	}
+}
+
+func jsonInt(n int64) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
@@ -1,97 +1,304 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
 	"github.com/omniful/ims_rohit/inventory"
+	"github.com/omniful/ims_rohit/pkg/datetime"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
 )
 
 // Hub Handlers
 
-func CreateHubHandler(c *gin.Context) {
+// HubResponse adds the hub's local-time view of its timestamps and whether it is open right now.
+type HubResponse struct {
+	*inventory.Hub
+	CreatedAtLocal string `json:"created_at_local"`
+	UpdatedAtLocal string `json:"updated_at_local"`
+	IsOpen         bool   `json:"is_open"`
+}
+
+func newHubResponse(c *gin.Context, h *inventory.Hub) *HubResponse {
+	res := &HubResponse{Hub: h, IsOpen: h.IsOpenAt(time.Now())}
+	ctx, err := datetime.WithTimeZone(c, h.Timezone)
+	if err != nil {
+		return res
+	}
+	res.CreatedAtLocal = datetime.FormatTime(ctx, h.CreatedAt.Format(time.RFC3339Nano))
+	res.UpdatedAtLocal = datetime.FormatTime(ctx, h.UpdatedAt.Format(time.RFC3339Nano))
+	return res
+}
+
+// validateHubAttributes checks the fields binding tags cannot: timezone, coordinates,
+// service area and operating hours.
+func validateHubAttributes(h *inventory.Hub) error {
+	if h.Timezone != "" {
+		if _, err := time.LoadLocation(h.Timezone); err != nil {
+			return errors.New("invalid timezone")
+		}
+	}
+	if (h.Latitude == nil) != (h.Longitude == nil) {
+		return errors.New("latitude and longitude must be set together")
+	}
+	if len(h.ServiceArea) > 0 && len(h.ServiceArea) < 3 {
+		return errors.New("service area needs at least 3 points")
+	}
+	return h.OperatingHours.Validate()
+}
+
+func (h *Handler) CreateHubHandler(c *gin.Context) {
 	var req inventory.Hub
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
 		return
 	}
-	id, err := inventory.CreateHub(c.Request.Context(), &req)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := validateHubAttributes(&req); err != nil {
+		respondWithError(c, oerror.RequestInvalid, err.Error())
 		return
 	}
-	req.ID = id
-	c.JSON(http.StatusCreated, req)
+	if _, err := h.hubs.CreateHub(c, &req); err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	setETag(c, req.Version)
+	respond(c, http.StatusCreated, newHubResponse(c, &req))
 }
 
 // Standardized GetHubHandler
-func GetHubHandler(c *gin.Context) {
+func (h *Handler) GetHubHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+		respondWithError(c, oerror.RequestInvalid, "invalid hub id")
 		return
 	}
 
 	// Get hub data
-	hubData, err := inventory.GetHub(c.Request.Context(), id)
+	hubData, err := h.hubs.GetHub(c, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithInventoryError(c, err)
 		return
 	}
 	if hubData == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+		respondWithError(c, pkgerror.NotFound, "hub not found")
 		return
 	}
 
 	// Return standardized response
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    hubData,
-	})
+	setETag(c, hubData.Version)
+	h.responses.NewAccessControlSuccessResponse(c, newHubResponse(c, hubData))
 }
-func UpdateHubHandler(c *gin.Context) {
+
+// UpdateHubHandler replaces the operating attributes of a hub. The status is changed with
+// UpdateHubStatusHandler, which checks the transition, so the body must not carry one.
+func (h *Handler) UpdateHubHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+		respondWithError(c, oerror.RequestInvalid, "invalid hub id")
 		return
 	}
 	var req inventory.Hub
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	if req.Status != "" {
+		respondWithError(c, oerror.RequestInvalid, "status cannot be updated here, use PUT /api/v1/hubs/:id/status")
+		return
+	}
+	if err := validateHubAttributes(&req); err != nil {
+		respondWithError(c, oerror.RequestInvalid, err.Error())
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 	req.ID = id
-	if err := inventory.UpdateHub(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.hubs.UpdateHub(c, &req, expectedVersion); err != nil {
+		respondWithInventoryError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, req)
+	setETag(c, req.Version)
+	respond(c, http.StatusOK, newHubResponse(c, &req))
 }
 
-func DeleteHubHandler(c *gin.Context) {
+func (h *Handler) DeleteHubHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+		respondWithError(c, oerror.RequestInvalid, "invalid hub id")
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
-	if err := inventory.DeleteHub(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.hubs.DeleteHub(c, id, expectedVersion); err != nil {
+		respondWithInventoryError(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
-func ListHubsHandler(c *gin.Context) {
-	hubs, err := inventory.ListHubs(c.Request.Context())
+type UpdateHubStatusRequest struct {
+	Status inventory.HubStatus `json:"status" binding:"required,oneof=active paused decommissioned"`
+}
+
+type HubStatusResponse struct {
+	ID     int64               `json:"id"`
+	Status inventory.HubStatus `json:"status"`
+}
+
+func (h *Handler) UpdateHubStatusHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, oerror.RequestInvalid, "invalid hub id")
+		return
+	}
+	var req UpdateHubStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	err = h.hubs.UpdateHubStatus(c, id, req.Status, expectedVersion)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, HubStatusResponse{ID: id, Status: req.Status})
+}
+
+// ListHubsRequest filters ListHubsHandler. status is comma separated; decommissioned hubs
+// are hidden unless asked for. Hubs sort by id, name, created_at or updated_at.
+type ListHubsRequest struct {
+	PageRequest
+	DateRangeRequest
+	Status     string `form:"status"`
+	NamePrefix string `form:"name_prefix"`
+}
+
+func (h *Handler) ListHubsHandler(c *gin.Context) {
+	var req ListHubsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	filter := inventory.HubFilter{NamePrefix: req.NamePrefix}
+	for _, status := range splitAndTrim(req.Status) {
+		filter.Statuses = append(filter.Statuses, inventory.HubStatus(status))
+	}
+	var err error
+	if filter.Created, filter.Updated, err = req.ranges(c); err != nil {
+		respondWithError(c, oerror.RequestInvalid, err.Error())
+		return
+	}
+
+	hubs, meta, err := h.hubs.ListHubs(c, filter, req.page())
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	if hubs == nil {
+		hubs = []*inventory.Hub{}
+	}
+	respondWithMeta(c, http.StatusOK, hubs, meta)
+}
+
+// HubUtilisationHandler reports how full each of the tenant's hubs is.
+func HubUtilisationHandler(c *gin.Context) {
+	report, err := inventory.HubUtilisationReport(c)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, report)
+}
+
+type NearestHubsRequest struct {
+	Latitude  *float64 `form:"lat" binding:"omitempty,latitude"`
+	Longitude *float64 `form:"lng" binding:"omitempty,longitude"`
+	Postcode  string   `form:"postcode"`
+	RadiusKm  float64  `form:"radius_km" binding:"omitempty,gt=0"`
+	SKUID     int64    `form:"sku_id" binding:"omitempty,gt=0"`
+	Qty       int64    `form:"qty" binding:"omitempty,gt=0"`
+	Limit     int      `form:"limit" binding:"omitempty,gt=0,lte=100"`
+}
+
+// NearestHubsHandler answers "which hubs are closest to this point", optionally only those
+// with a SKU available, not reserved for orders, in the requested quantity. The point is given as lat/lng or a postcode.
+func NearestHubsHandler(c *gin.Context) {
+	var req NearestHubsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+
+	var origin inventory.GeoPoint
+	switch {
+	case req.Latitude != nil && req.Longitude != nil:
+		origin = inventory.GeoPoint{Latitude: *req.Latitude, Longitude: *req.Longitude}
+	case req.Postcode != "":
+		pt, err := inventory.ResolvePostcode(c, req.Postcode)
+		if err != nil {
+			respondWithInventoryError(c, err)
+			return
+		}
+		origin = *pt
+	default:
+		respondWithError(c, oerror.RequestInvalid, "lat and lng, or postcode, are required")
+		return
+	}
+
+	query := inventory.NearbyHubQuery{
+		Origin:   origin,
+		RadiusKm: req.RadiusKm,
+		SKUID:    req.SKUID,
+		MinQty:   req.Qty,
+		Limit:    req.Limit,
+	}
+	if query.SKUID > 0 && query.MinQty == 0 {
+		query.MinQty = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+
+	hubs, err := inventory.NearestHubs(c, query)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, hubs)
+}
+
+type UpsertPostcodesRequest struct {
+	Postcodes []inventory.Postcode `json:"postcodes" binding:"required,min=1,dive"`
+}
+
+// UpsertPostcodesHandler loads the tenant's postcode centroids, used by NearestHubsHandler.
+func UpsertPostcodesHandler(c *gin.Context) {
+	var req UpsertPostcodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	if err := inventory.UpsertPostcodes(c, req.Postcodes); err != nil {
+		respondWithInventoryError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, hubs)
+	respond(c, http.StatusOK, nil)
 }
 
 // SKU Handlers
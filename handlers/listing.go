@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/api-gateway/pkg/utils"
+	"github.com/omniful/ims_rohit/inventory"
+	"github.com/omniful/ims_rohit/pkg/datetime"
+	"github.com/omniful/ims_rohit/pkg/keyset"
+	"gopkg.in/guregu/null.v4"
+)
+
+// PageRequest is the paging part of list requests: up to limit rows (capped at
+// inventory.MaxPageLimit) after cursor, the next_cursor of the previous page, ordered by
+// sort in the given order.
+type PageRequest struct {
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor" json:"cursor"`
+	Sort   string `form:"sort" json:"sort"`
+	Order  string `form:"order" json:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+func (r PageRequest) page() inventory.Page {
+	return inventory.Page{Limit: r.Limit, Cursor: r.Cursor, SortBy: r.Sort, Desc: r.Order == "desc"}
+}
+
+// LogPageRequest is the paging part of requests listing logs and queues, which are always
+// ordered newest first: up to limit rows (capped at keyset.MaxLimit) after cursor, the
+// next_cursor of the previous page.
+type LogPageRequest struct {
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor" json:"cursor"`
+}
+
+func (r LogPageRequest) page() keyset.Page {
+	return keyset.Page{Limit: r.Limit, Cursor: r.Cursor}
+}
+
+// DateRangeRequest filters on created and updated dates, given as DD-MM-YYYY days in the
+// timezone tz, UTC by default. Both ends are inclusive.
+type DateRangeRequest struct {
+	CreatedFrom string `form:"created_from" json:"created_from"`
+	CreatedTo   string `form:"created_to" json:"created_to"`
+	UpdatedFrom string `form:"updated_from" json:"updated_from"`
+	UpdatedTo   string `form:"updated_to" json:"updated_to"`
+	Timezone    string `form:"tz" json:"tz"`
+}
+
+// ranges resolves the requested dates to the created and updated time ranges.
+func (r DateRangeRequest) ranges(c *gin.Context) (created, updated inventory.TimeRange, err error) {
+	tz := r.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	ctx, err := datetime.WithTimeZone(c, tz)
+	if err != nil {
+		return created, updated, err
+	}
+	if created.From, err = dateBound(ctx, r.CreatedFrom, utils.FromDate); err != nil {
+		return created, updated, err
+	}
+	if created.To, err = dateBound(ctx, r.CreatedTo, utils.ToDate); err != nil {
+		return created, updated, err
+	}
+	if updated.From, err = dateBound(ctx, r.UpdatedFrom, utils.FromDate); err != nil {
+		return created, updated, err
+	}
+	updated.To, err = dateBound(ctx, r.UpdatedTo, utils.ToDate)
+	return created, updated, err
+}
+
+// dateBound turns a day into the first or last instant of it; an empty day is no bound.
+func dateBound(ctx context.Context, date string, dateType utils.DateType) (*time.Time, error) {
+	if date == "" {
+		return nil, nil
+	}
+	unix, err := datetime.GetTimeForDateRange(ctx, null.StringFrom(date), dateType)
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := strconv.ParseInt(unix.String, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	t := time.Unix(seconds, 0).UTC()
+	if dateType == utils.ToDate {
+		// The helper works in whole seconds; include the rest of the day's last second.
+		t = t.Add(time.Second - time.Nanosecond)
+	}
+	return &t, nil
+}
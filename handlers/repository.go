@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/omniful/ims_rohit/inventory"
+	"github.com/omniful/ims_rohit/pkg/response"
+)
+
+// Handler serves the hub, SKU, stock and count routes through the repositories it is
+// built with. Entities returned or modified by id are checked with responses, so they
+// belong to hubs and sellers the caller may access.
+type Handler struct {
+	hubs      inventory.HubRepository
+	skus      inventory.SKURepository
+	stock     inventory.InventoryRepository
+	responses *response.Handler
+}
+
+func New(hubs inventory.HubRepository, skus inventory.SKURepository, stock inventory.InventoryRepository,
+	responses *response.Handler) *Handler {
+	return &Handler{hubs: hubs, skus: skus, stock: stock, responses: responses}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/pkg/response"
+	validator "github.com/omniful/ims_rohit/pkg/validate"
+)
+
+// respond writes data in the standard success envelope.
+func respond(c *gin.Context, statusCode int, data interface{}) {
+	response.NewSuccessResponse(c, statusCode, data)
+}
+
+func respondWithMeta(c *gin.Context, statusCode int, data interface{}, meta interface{}) {
+	response.NewSuccessResponseWithMeta(c, statusCode, data, meta)
+}
+
+// respondWithError writes the standard error envelope for an error code of pkg/error.
+func respondWithError(c *gin.Context, code oerror.Code, message string) {
+	response.NewErrorResponse(c, oerror.NewCustomError(code, message), nil)
+}
+
+// respondWithBindingError answers a request that could not be bound into req, listing
+// the failing fields when the error is tied to them.
+func respondWithBindingError(c *gin.Context, err error, req interface{}) {
+	fields := validator.FieldErrors(err, req)
+	if fields == nil {
+		respondWithError(c, oerror.RequestInvalid, "invalid request: "+err.Error())
+		return
+	}
+	response.NewErrorResponse(c, oerror.NewCustomError(oerror.RequestInvalid, "request validation failed"), fields)
+}
@@ -1,20 +1,22 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/internal/permission"
 	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
 	"github.com/omniful/ims_rohit/pkg/sku"
 )
 
-func CreateSKUHandler(c *gin.Context) {
+func (h *Handler) CreateSKUHandler(c *gin.Context) {
 	var req inventory.SKU
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
 		return
 	}
 	if req.SKUCode == "" {
@@ -22,94 +24,129 @@ func CreateSKUHandler(c *gin.Context) {
 		req.SKUCode = skuCode
 	}
 
-	id, err := inventory.CreateSKU(c.Request.Context(), &req)
+	id, err := h.skus.CreateSKU(c, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithInventoryError(c, err)
 		return
 	}
 	req.ID = id
-	c.JSON(http.StatusCreated, req)
+	respond(c, http.StatusCreated, req)
 }
 
-func GetSKUHandler(c *gin.Context) {
+func (h *Handler) GetSKUHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku id"})
+		respondWithError(c, oerror.RequestInvalid, "invalid sku id")
 		return
 	}
-	sku, err := inventory.GetSKU(c.Request.Context(), id)
+	sku, err := h.skus.GetSKU(c, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithInventoryError(c, err)
 		return
 	}
 	if sku == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "sku not found"})
+		respondWithError(c, pkgerror.NotFound, "sku not found")
 		return
 	}
-	c.JSON(http.StatusOK, sku)
+	setETag(c, sku.Version)
+	h.responses.NewAccessControlSuccessResponse(c, sku)
 }
 
-func UpdateSKUHandler(c *gin.Context) {
+// authorizeSKU loads the SKU and checks the caller may access its seller, writing the
+// error response and returning false otherwise.
+func (h *Handler) authorizeSKU(c *gin.Context, id int64) bool {
+	existing, err := h.skus.GetSKU(c, id)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return false
+	}
+	if existing == nil {
+		respondWithError(c, pkgerror.NotFound, "sku not found")
+		return false
+	}
+	return h.responses.Authorize(c, existing)
+}
+
+func (h *Handler) UpdateSKUHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku id"})
+		respondWithError(c, oerror.RequestInvalid, "invalid sku id")
 		return
 	}
 	var req inventory.SKU
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok || !h.authorizeSKU(c, id) {
 		return
 	}
 	req.ID = id
-	if err := inventory.UpdateSKU(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.skus.UpdateSKU(c, &req, expectedVersion); err != nil {
+		respondWithInventoryError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, req)
+	setETag(c, req.Version)
+	respond(c, http.StatusOK, req)
 }
 
-func DeleteSKUHandler(c *gin.Context) {
+func (h *Handler) DeleteSKUHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku id"})
+		respondWithError(c, oerror.RequestInvalid, "invalid sku id")
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok || !h.authorizeSKU(c, id) {
 		return
 	}
-	if err := inventory.DeleteSKU(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.skus.DeleteSKU(c, id, expectedVersion); err != nil {
+		respondWithInventoryError(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
-func ListSKUsHandler(c *gin.Context) {
-	// Optional query params: tenant_id, seller_id, sku_code (comma separated)
-	var (
-		tenantID *int64
-		sellerID *int64
-		skuCodes []string
-	)
-	if tid := c.Query("tenant_id"); tid != "" {
-		if v, err := strconv.ParseInt(tid, 10, 64); err == nil {
-			tenantID = &v
-		}
+// ListSKUsRequest filters ListSKUsHandler. sku_code is comma separated. SKUs sort by id,
+// name, sku_code, created_at or updated_at.
+type ListSKUsRequest struct {
+	PageRequest
+	DateRangeRequest
+	SellerID   *int64 `form:"seller_id"`
+	SKUCode    string `form:"sku_code"`
+	NamePrefix string `form:"name_prefix"`
+}
+
+func (h *Handler) ListSKUsHandler(c *gin.Context) {
+	var req ListSKUsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
 	}
-	if sid := c.Query("seller_id"); sid != "" {
-		if v, err := strconv.ParseInt(sid, 10, 64); err == nil {
-			sellerID = &v
-		}
+	filter := inventory.SKUFilter{
+		SellerID:   req.SellerID,
+		SKUCodes:   splitAndTrim(req.SKUCode),
+		NamePrefix: req.NamePrefix,
 	}
-	if codes := c.Query("sku_code"); codes != "" {
-		skuCodes = splitAndTrim(codes)
+	var err error
+	if filter.Created, filter.Updated, err = req.ranges(c); err != nil {
+		respondWithError(c, oerror.RequestInvalid, err.Error())
+		return
 	}
-	skus, err := inventory.ListSKUs(c.Request.Context(), tenantID, sellerID, skuCodes)
+
+	skus, meta, err := h.skus.ListSKUs(c, filter, req.page())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithInventoryError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, skus)
+	if skus == nil {
+		skus = []*inventory.SKU{}
+	}
+	respondWithMeta(c, http.StatusOK, skus, meta)
 }
 
 func splitAndTrim(s string) []string {
@@ -125,43 +162,109 @@ func splitAndTrim(s string) []string {
 
 // Inventory Handlers
 
+// UpsertInventoryRequest and SetInventoryRequest take an optional expected_version: the
+// version of the stock as last read, 0 if the SKU was never stocked at the hub. The write
+// is rejected with 412 if the stock changed since.
 type UpsertInventoryRequest struct {
-	HubID int64 `json:"hub_id" binding:"required"`
-	SKUID int64 `json:"sku_id" binding:"required"`
-	Qty   int64 `json:"qty" binding:"required"`
+	HubID           int64  `json:"hub_id" binding:"required"`
+	SKUID           int64  `json:"sku_id" binding:"required"`
+	Qty             int64  `json:"qty" binding:"required"`
+	ExpectedVersion *int64 `json:"expected_version" binding:"omitempty,min=0"`
 }
 
-func UpsertInventoryHandler(c *gin.Context) {
+func (h *Handler) UpsertInventoryHandler(c *gin.Context) {
 	var req UpsertInventoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
 		return
 	}
-	if err := inventory.UpsertInventory(c.Request.Context(), req.HubID, req.SKUID, req.Qty); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// The route lets decrement-only roles through; increments need the full adjust action.
+	if req.Qty > 0 && !allowed(c, permission.InventoryAdjust) {
 		return
 	}
-	c.Status(http.StatusOK)
+	result, err := h.stock.UpsertInventory(c, req.HubID, req.SKUID, req.Qty, req.ExpectedVersion)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithUpsertResult(c, result)
+}
+
+func respondWithUpsertResult(c *gin.Context, result *inventory.UpsertResult) {
+	respond(c, http.StatusOK, result)
 }
 
+type SetInventoryRequest struct {
+	HubID           int64  `json:"hub_id" binding:"required"`
+	SKUID           int64  `json:"sku_id" binding:"required"`
+	Qty             *int64 `json:"qty" binding:"required,min=0"`
+	ExpectedVersion *int64 `json:"expected_version" binding:"omitempty,min=0"`
+}
+
+func (h *Handler) SetInventoryHandler(c *gin.Context) {
+	var req SetInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	result, err := h.stock.SetInventory(c, req.HubID, req.SKUID, *req.Qty, req.ExpectedVersion)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithUpsertResult(c, result)
+}
+
+// ViewInventoryRequest asks for the stock of sku_ids at a hub, or, without sku_ids, for a
+// page of all the hub's stock: the paging fields and min_qty, max_qty, updated_from and
+// updated_to then apply. Stock sorts by sku_id, quantity or updated_at.
 type ViewInventoryRequest struct {
+	PageRequest
+	DateRangeRequest
 	HubID  int64   `json:"hub_id" binding:"required"`
 	SKUIDs []int64 `json:"sku_ids"`
+	MinQty *int64  `json:"min_qty"`
+	MaxQty *int64  `json:"max_qty"`
 }
 
-func ViewInventoryHandler(c *gin.Context) {
+func (h *Handler) ViewInventoryHandler(c *gin.Context) {
 	var req ViewInventoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
 		return
 	}
-	invs, err := inventory.ViewInventory(c.Request.Context(), req.HubID, req.SKUIDs)
-	fmt.Println("invs", invs)
+	if len(req.SKUIDs) == 0 {
+		h.listInventory(c, &req)
+		return
+	}
+	invs, err := h.stock.ViewInventory(c, req.HubID, req.SKUIDs)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	if invs == nil {
+		invs = []*inventory.Inventory{}
+	}
+	total := int64(len(invs))
+	respondWithMeta(c, http.StatusOK, invs, &inventory.PageMeta{Limit: len(invs), Total: &total})
+}
+
+func (h *Handler) listInventory(c *gin.Context, req *ViewInventoryRequest) {
+	filter := inventory.InventoryFilter{MinQty: req.MinQty, MaxQty: req.MaxQty}
+	var err error
+	if _, filter.Updated, err = req.ranges(c); err != nil {
+		respondWithError(c, oerror.RequestInvalid, err.Error())
+		return
+	}
+	invs, meta, err := h.stock.ListInventory(c, req.HubID, filter, req.page())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithInventoryError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, invs)
+	if invs == nil {
+		invs = []*inventory.Inventory{}
+	}
+	respondWithMeta(c, http.StatusOK, invs, meta)
 }
 
 type CheckSKUsExistenceRequest struct {
@@ -173,20 +276,20 @@ type CheckSKUsExistenceResponse struct {
 	Invalid   []int64        `json:"invalid"`
 }
 
-func CheckSKUsExistenceHandler(c *gin.Context) {
+func (h *Handler) CheckSKUsExistenceHandler(c *gin.Context) {
 	var req CheckSKUsExistenceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindingError(c, err, &req)
 		return
 	}
-	existence, invalid, err := inventory.CheckSKUsExistence(c.Request.Context(), req.SKUIDs)
+	existence, invalid, err := h.skus.CheckSKUsExistence(c, req.SKUIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithInventoryError(c, err)
 		return
 	}
 	resp := CheckSKUsExistenceResponse{
 		Existence: existence,
 		Invalid:   invalid,
 	}
-	c.JSON(http.StatusOK, resp)
+	respond(c, http.StatusOK, resp)
 }
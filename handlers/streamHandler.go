@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/go_commons/config"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/internal/stockstream"
+	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+// Server-sent events of the stock stream. A stock event carries a stockstream.Change; a
+// reset event tells the client that changes it missed are gone, so it has to read the
+// current stock again with /inventory/view.
+const (
+	stockEvent = "stock"
+	resetEvent = "reset"
+)
+
+// defaultHeartbeat is the heartbeat interval when stock_stream.heartbeat is not set.
+const defaultHeartbeat = 15 * time.Second
+
+// StreamInventoryRequest follows the stock changes of a hub, only those of the comma
+// separated sku_ids when given. last_event_id resumes after an event, like the
+// Last-Event-ID header EventSource sends when it reconnects; the header wins.
+type StreamInventoryRequest struct {
+	HubID       int64  `form:"hub_id" binding:"required"`
+	SKUIDs      string `form:"sku_ids"`
+	LastEventID string `form:"last_event_id"`
+}
+
+// StreamInventoryHandler streams stock changes as server-sent events until the client
+// goes away. The stream also ends when the client falls too far behind or the server shuts
+// down; the client then reconnects and resumes from the last event it received.
+func StreamInventoryHandler(c *gin.Context) {
+	var req StreamInventoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	var skuIDs []int64
+	for _, s := range splitAndTrim(req.SKUIDs) {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			respondWithError(c, oerror.RequestInvalid, "invalid sku_ids")
+			return
+		}
+		skuIDs = append(skuIDs, id)
+	}
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = req.LastEventID
+	}
+	tenantID, err := inventory.TenantIDFromContext(c)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	// A missing hub, or one of another tenant, has no stream to follow.
+	hub, err := inventory.GetHub(c, req.HubID)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	if hub == nil {
+		respondWithError(c, pkgerror.NotFound, "hub not found")
+		return
+	}
+	// SKUs of sellers outside the caller's scope are missing to them, like on /inventory/view.
+	scope, err := newSKUScope(c, skuIDs)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+
+	// Subscribe before reading the history, so no change falls between the two.
+	sub, err := stockstream.GetStream(c).Subscribe(tenantID, req.HubID, skuIDs)
+	if err != nil {
+		respondWithError(c, pkgerror.InternalError, "stock stream is unavailable")
+		return
+	}
+	defer sub.Close()
+
+	var (
+		missed   []*stockstream.Event
+		complete = true
+	)
+	if lastID != "" {
+		if missed, complete, err = sub.Since(c, lastID); err != nil {
+			log.WithError(err).Error("failed to read stock change history")
+			respondWithError(c, pkgerror.InternalError, "failed to read stock changes")
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	// The server's write timeout is meant for ordinary responses, not this one.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.WithError(err).Error("failed to lift the write timeout of the stock stream")
+	}
+
+	if !complete {
+		c.Render(-1, sse.Event{Event: resetEvent, Data: ""})
+	}
+	for _, event := range missed {
+		if scope.permits(c, event) {
+			writeStockEvent(c, event)
+		}
+		lastID = event.ID
+	}
+	c.Writer.Flush()
+
+	interval := config.GetDuration(c, "stock_stream.heartbeat")
+	if interval <= 0 {
+		interval = defaultHeartbeat
+	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if lastID != "" && !stockstream.After(event.ID, lastID) {
+				continue
+			}
+			if scope.permits(c, event) {
+				writeStockEvent(c, event)
+			}
+			lastID = event.ID
+		case <-heartbeat.C:
+			// A comment line, ignored by clients, keeps proxies from closing an idle stream.
+			c.Writer.WriteString(": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// skuScope remembers which SKUs of a stream the caller may see.
+type skuScope map[int64]bool
+
+// newSKUScope fails with inventory.ErrSKUNotFound when the caller may not see one of the
+// requested SKUs.
+func newSKUScope(c *gin.Context, skuIDs []int64) (skuScope, error) {
+	permitted, err := inventory.PermittedSKUs(c, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(permitted) != len(skuIDs) {
+		return nil, inventory.ErrSKUNotFound
+	}
+	scope := make(skuScope, len(skuIDs))
+	for _, id := range skuIDs {
+		scope[id] = true
+	}
+	return scope, nil
+}
+
+// permits looks up the SKUs of the hub's stream the first time they change. A failed
+// lookup drops the event and is retried on the SKU's next change.
+func (s skuScope) permits(c *gin.Context, event *stockstream.Event) bool {
+	skuID := event.Change.SKUID
+	if ok, seen := s[skuID]; seen {
+		return ok
+	}
+	permitted, err := inventory.PermittedSKUs(c, []int64{skuID})
+	if err != nil {
+		log.WithError(err).Error("failed to check the seller scope of a stock change")
+		return false
+	}
+	s[skuID] = len(permitted) == 1
+	return s[skuID]
+}
+
+func writeStockEvent(c *gin.Context, event *stockstream.Event) {
+	c.Render(-1, sse.Event{Id: event.ID, Event: stockEvent, Data: event.Change})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/internal/idempotency"
+	"github.com/omniful/ims_rohit/internal/webhook"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+// webhookRequestIDs parses the tenant and the :id path parameter shared by the webhook
+// routes, writing the error response and returning false on failure.
+func webhookRequestIDs(c *gin.Context) (tenantID, id int64, ok bool) {
+	tenantID, err := inventory.TenantIDFromContext(c)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return 0, 0, false
+	}
+	if idStr := c.Param("id"); idStr != "" {
+		if id, err = strconv.ParseInt(idStr, 10, 64); err != nil {
+			respondWithError(c, oerror.RequestInvalid, "invalid id")
+			return 0, 0, false
+		}
+	}
+	return tenantID, id, true
+}
+
+func CreateWebhookHandler(c *gin.Context) {
+	var req webhook.Subscription
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	tenantID, _, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	if err := webhook.CreateSubscription(c, tenantID, &req); err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	// The secret is shown once; a replay of the request does not show it again.
+	idempotency.Redact(c, req.Secret)
+	respond(c, http.StatusCreated, req)
+}
+
+type ListWebhooksRequest struct {
+	LogPageRequest
+}
+
+func ListWebhooksHandler(c *gin.Context) {
+	var req ListWebhooksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	tenantID, _, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	subs, meta, err := webhook.ListSubscriptions(c, tenantID, req.page())
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithMeta(c, http.StatusOK, subs, meta)
+}
+
+func GetWebhookHandler(c *gin.Context) {
+	tenantID, id, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	sub, err := webhook.GetSubscription(c, tenantID, id)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, sub)
+}
+
+// UpdateWebhookHandler applies the request body on top of the stored subscription, so
+// fields left out, such as active, keep their current values.
+func UpdateWebhookHandler(c *gin.Context) {
+	tenantID, id, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	sub, err := webhook.GetSubscription(c, tenantID, id)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(sub); err != nil {
+		respondWithBindingError(c, err, sub)
+		return
+	}
+	sub.ID = id
+	if err := webhook.UpdateSubscription(c, tenantID, sub); err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, sub)
+}
+
+func DeleteWebhookHandler(c *gin.Context) {
+	tenantID, id, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	if err := webhook.DeleteSubscription(c, tenantID, id); err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	LogPageRequest
+}
+
+func ListWebhookDeliveriesHandler(c *gin.Context) {
+	var req ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondWithBindingError(c, err, &req)
+		return
+	}
+	tenantID, id, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	deliveries, meta, err := webhook.ListDeliveries(c, tenantID, id, webhook.DeliveryStatus(req.Status), req.page())
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respondWithMeta(c, http.StatusOK, deliveries, meta)
+}
+
+func ReplayWebhookDeliveryHandler(c *gin.Context) {
+	tenantID, id, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+	delivery, err := webhook.ReplayDelivery(c, tenantID, id)
+	if err != nil {
+		respondWithInventoryError(c, err)
+		return
+	}
+	respond(c, http.StatusAccepted, delivery)
+}
@@ -67,7 +67,11 @@ func InitializePostgres(ctx context.Context) {
 	if err != nil {
 		log.WithError(err).Panic("unable to initialise postgres")
 	}
-	pg.SetClient(db)
+	replicas, err := pg.ConnectReplicas(ctx)
+	if err != nil {
+		log.WithError(err).Panic("unable to initialise postgres read replicas")
+	}
+	pg.SetClient(db, replicas...)
 	fmt.Println("Initialized Postgres Client")
 	log.InfofWithContext(ctx, "Initialized Postgres Client")
 }
@@ -158,8 +158,10 @@ func setUserSellersInQueryParam(c *gin.Context) error {
 		return err
 	}
 
+	// Callers with access to all sellers are not restricted, so the key stays unset; a
+	// rule without values restricts to no seller.
 	if isAllSellers {
-		userSellers = make([]string, 0)
+		return nil
 	}
 
 	c.Set(constants2.SellerIDs, userSellers)
@@ -226,8 +228,10 @@ func setUserHubsInQueryParam(c *gin.Context) error {
 		return err
 	}
 
+	// Callers with access to all hubs are not restricted, so the key stays unset; a rule
+	// without values restricts to no hub.
 	if isAllHubs {
-		userHubs = make([]string, 0)
+		return nil
 	}
 
 	c.Set(constants2.HubIDs, userHubs)
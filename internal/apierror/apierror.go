@@ -0,0 +1,65 @@
+// Package apierror classifies errors of the inventory, webhook and consumer packages into
+// the error codes of pkg/error, for the HTTP and gRPC APIs alike.
+package apierror
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/internal/consumer"
+	"github.com/omniful/ims_rohit/internal/webhook"
+	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+// uniqueViolation is the Postgres error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// Code maps err to an error code of pkg/error.
+func Code(err error) oerror.Code {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, inventory.ErrInvalidCursor), errors.Is(err, inventory.ErrInvalidSort),
+		errors.Is(err, webhook.ErrForbiddenURL):
+		return oerror.RequestInvalid
+	case errors.Is(err, inventory.ErrTenantMissing):
+		return pkgerror.Unauthenticated
+	case errors.Is(err, inventory.ErrHubNotFound), errors.Is(err, inventory.ErrSKUNotFound),
+		errors.Is(err, inventory.ErrPostcodeNotFound), errors.Is(err, inventory.ErrCountNotFound),
+		errors.Is(err, webhook.ErrSubscriptionNotFound), errors.Is(err, webhook.ErrDeliveryNotFound),
+		errors.Is(err, consumer.ErrDeadLetterNotFound):
+		return pkgerror.NotFound
+	case errors.Is(err, inventory.ErrInvalidHubTransition), errors.Is(err, inventory.ErrCountAlreadyResolved),
+		errors.Is(err, consumer.ErrDeadLetterReplayed):
+		return pkgerror.Conflict
+	case errors.Is(err, inventory.ErrDuplicateSKUCode),
+		errors.As(err, &pqErr) && pqErr.Code == uniqueViolation:
+		return pkgerror.UniqueViolation
+	case errors.Is(err, inventory.ErrHubNotActive), errors.Is(err, inventory.ErrHubCapacityExceeded),
+		errors.Is(err, inventory.ErrSellerInactive), errors.Is(err, inventory.ErrBelowReserved):
+		return pkgerror.ValidationFailed
+	case errors.Is(err, inventory.ErrVersionMismatch):
+		return pkgerror.PreconditionFailed
+	default:
+		return pkgerror.InternalError
+	}
+}
+
+// New classifies err into a CustomError fit for clients. Unexpected errors can carry SQL
+// and connection details, so they are logged and given a generic message.
+func New(err error) oerror.CustomError {
+	code := Code(err)
+	message := err.Error()
+	switch code {
+	case pkgerror.InternalError:
+		log.WithError(err).Error("request failed")
+		message = "internal server error"
+	case pkgerror.UniqueViolation:
+		if !errors.Is(err, inventory.ErrDuplicateSKUCode) {
+			message = "a record with the same unique fields already exists"
+		}
+	}
+	return oerror.NewCustomError(code, message)
+}
@@ -0,0 +1,52 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/internal/consumer"
+	"github.com/omniful/ims_rohit/internal/webhook"
+	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+func TestCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want oerror.Code
+	}{
+		{inventory.ErrInvalidCursor, oerror.RequestInvalid},
+		{webhook.ErrForbiddenURL, oerror.RequestInvalid},
+		{inventory.ErrTenantMissing, pkgerror.Unauthenticated},
+		{fmt.Errorf("%w: hub 3", inventory.ErrHubNotFound), pkgerror.NotFound},
+		{consumer.ErrDeadLetterNotFound, pkgerror.NotFound},
+		{inventory.ErrInvalidHubTransition, pkgerror.Conflict},
+		{inventory.ErrDuplicateSKUCode, pkgerror.UniqueViolation},
+		{&pq.Error{Code: uniqueViolation}, pkgerror.UniqueViolation},
+		{&pq.Error{Code: "23503"}, pkgerror.InternalError},
+		{fmt.Errorf("%w: seller 7", inventory.ErrSellerInactive), pkgerror.ValidationFailed},
+		{inventory.ErrBelowReserved, pkgerror.ValidationFailed},
+		{inventory.ErrVersionMismatch, pkgerror.PreconditionFailed},
+		{errors.New("connection refused"), pkgerror.InternalError},
+	}
+	for _, c := range cases {
+		if got := Code(c.err); got != c.want {
+			t.Errorf("Code(%v) = %s, want %s", c.err, got, c.want)
+		}
+	}
+}
+
+func TestNewHidesUnexpectedErrors(t *testing.T) {
+	if e := New(errors.New(`pq: relation "hubs" does not exist`)); e.ErrorMessage() != "internal server error" {
+		t.Errorf("internal error: got message %q", e.ErrorMessage())
+	}
+	if e := New(&pq.Error{Code: uniqueViolation, Message: `duplicate key value violates unique constraint "skus_pkey"`}); e.ErrorMessage() != "a record with the same unique fields already exists" {
+		t.Errorf("unique violation: got message %q", e.ErrorMessage())
+	}
+	if e := New(inventory.ErrDuplicateSKUCode); e.ErrorMessage() != inventory.ErrDuplicateSKUCode.Error() {
+		t.Errorf("duplicate SKU code: got message %q", e.ErrorMessage())
+	}
+}
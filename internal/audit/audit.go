@@ -0,0 +1,257 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/go_commons/env"
+	"github.com/omniful/go_commons/jwt/public"
+	"github.com/omniful/ims_rohit/http"
+	"github.com/omniful/ims_rohit/pkg/keyset"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+const (
+	EntityHub       = "hub"
+	EntitySKU       = "sku"
+	EntityInventory = "inventory"
+)
+
+// metadataKey is the context key WithMetadata stores Metadata under.
+type metadataKey struct{}
+
+// Metadata identifies who made a change and from which request.
+type Metadata struct {
+	ActorID   string
+	RequestID string
+	IP        string
+}
+
+// WithMetadata returns a context whose audit entries are attributed to m. Requests get it
+// from Middleware; background work such as Kafka consumers sets a System actor.
+func WithMetadata(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, m)
+}
+
+// System is the metadata of changes made by the service itself rather than a user, such as
+// those applied from Kafka events. component names the part of the service making them.
+func System(component string) Metadata {
+	return Metadata{ActorID: "system:" + component}
+}
+
+// Middleware captures the actor, request ID and client IP of authenticated requests so
+// writes further down can attribute their audit entries. It must run after the JWT
+// middleware and LoggerContextMiddleware, on an engine with ContextWithFallback set so the
+// gin context finds the metadata in the request context.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, _ := public.GetUserID(c)
+		c.Request = c.Request.WithContext(WithMetadata(c.Request.Context(), Metadata{
+			ActorID:   actorID,
+			RequestID: env.GetRequestID(c),
+			IP:        http.GetUserIPAddress(c),
+		}))
+		c.Next()
+	}
+}
+
+func metadataFromContext(ctx context.Context) Metadata {
+	m, _ := ctx.Value(metadataKey{}).(Metadata)
+	return m
+}
+
+// FieldChange is the before and after value of one changed field.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type Entry struct {
+	ID         int64                  `json:"id"`
+	TenantID   int64                  `json:"tenant_id"`
+	ActorID    string                 `json:"actor_id"`
+	RequestID  string                 `json:"request_id"`
+	IP         string                 `json:"ip"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Action     Action                 `json:"action"`
+	Before     json.RawMessage        `json:"before,omitempty"`
+	After      json.RawMessage        `json:"after,omitempty"`
+	Diff       map[string]FieldChange `json:"diff"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// ignoredFields change on every write and would only add noise to the diff.
+var ignoredFields = map[string]bool{"updated_at": true, "version": true}
+
+// diff compares the JSON forms of before and after field by field. Either side may be
+// nil for creates and deletes.
+func diff(before, after map[string]interface{}) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+	for field, from := range before {
+		if ignoredFields[field] {
+			continue
+		}
+		if to, ok := after[field]; !ok || !reflect.DeepEqual(from, to) {
+			changes[field] = FieldChange{From: from, To: after[field]}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok && !ignoredFields[field] {
+			changes[field] = FieldChange{To: to}
+		}
+	}
+	return changes
+}
+
+// toJSON returns the JSON of v and its decoded field map; nil stays nil.
+func toJSON(v interface{}) ([]byte, map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, err
+	}
+	return raw, fields, nil
+}
+
+// Record writes an audit entry inside tx, so the entry is only kept if the change it
+// describes commits. before is nil for creates and after is nil for deletes.
+func Record(ctx context.Context, tx *sql.Tx, tenantID int64, entityType, entityID string, action Action, before, after interface{}) error {
+	beforeJSON, beforeFields, err := toJSON(before)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit before state: %w", err)
+	}
+	afterJSON, afterFields, err := toJSON(after)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit after state: %w", err)
+	}
+	diffJSON, err := json.Marshal(diff(beforeFields, afterFields))
+	if err != nil {
+		return fmt.Errorf("failed to encode audit diff: %w", err)
+	}
+
+	m := metadataFromContext(ctx)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_logs (tenant_id, actor_id, request_id, ip, entity_type, entity_id, action, before, after, diff)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		tenantID, m.ActorID, m.RequestID, m.IP, entityType, entityID, action,
+		nullableJSON(beforeJSON), nullableJSON(afterJSON), diffJSON)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func nullableJSON(raw []byte) interface{} {
+	if raw == nil {
+		return nil
+	}
+	return raw
+}
+
+type Filter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	Action     Action
+	From       *time.Time
+	To         *time.Time
+}
+
+// Query returns one page of a tenant's audit entries matching the filter, newest first.
+func Query(ctx context.Context, tenantID int64, f Filter, page keyset.Page) ([]*Entry, *keyset.Meta, error) {
+	db := pg.GetClient().Reader()
+	beforeID, err := page.Before()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where := ` WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	addCond := func(cond string, v interface{}) {
+		args = append(args, v)
+		where += fmt.Sprintf(` AND `+cond, len(args))
+	}
+	if f.EntityType != "" {
+		addCond(`entity_type = $%d`, f.EntityType)
+	}
+	if f.EntityID != "" {
+		addCond(`entity_id = $%d`, f.EntityID)
+	}
+	if f.ActorID != "" {
+		addCond(`actor_id = $%d`, f.ActorID)
+	}
+	if f.Action != "" {
+		addCond(`action = $%d`, f.Action)
+	}
+	if f.From != nil {
+		addCond(`created_at >= $%d`, *f.From)
+	}
+	if f.To != nil {
+		addCond(`created_at < $%d`, *f.To)
+	}
+
+	var total *int64
+	if beforeID == 0 {
+		total = new(int64)
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_logs`+where, args...).Scan(total); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		addCond(`id < $%d`, beforeID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, actor_id, request_id, ip, entity_type, entity_id, action, before, after, diff, created_at
+		FROM audit_logs%s
+		ORDER BY id DESC
+		LIMIT $%d`, where, len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	entries := []*Entry{}
+	for rows.Next() {
+		var (
+			e                   = &Entry{}
+			before, after, diff []byte
+		)
+		err := rows.Scan(&e.ID, &e.TenantID, &e.ActorID, &e.RequestID, &e.IP, &e.EntityType, &e.EntityID, &e.Action,
+			&before, &after, &diff, &e.CreatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		e.Before, e.After = before, after
+		if err := json.Unmarshal(diff, &e.Diff); err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	entries, meta := keyset.Trim(page, entries, func(e *Entry) int64 { return e.ID })
+	meta.Total = total
+	return entries, meta, nil
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type entity struct {
+	Name      string  `json:"name"`
+	Capacity  *int    `json:"capacity"`
+	Version   int64   `json:"version"`
+	UpdatedAt string  `json:"updated_at"`
+	Tags      []int64 `json:"tags"`
+}
+
+func TestDiff(t *testing.T) {
+	capacity := 10
+	before := &entity{Name: "Riyadh DC", Version: 1, UpdatedAt: "t1", Tags: []int64{1}}
+	after := &entity{Name: "Riyadh North DC", Capacity: &capacity, Version: 2, UpdatedAt: "t2", Tags: []int64{1}}
+	_, from, err := toJSON(before)
+	if err != nil {
+		t.Fatalf("toJSON: %v", err)
+	}
+	_, to, err := toJSON(after)
+	if err != nil {
+		t.Fatalf("toJSON: %v", err)
+	}
+
+	want := map[string]FieldChange{
+		"name":     {From: "Riyadh DC", To: "Riyadh North DC"},
+		"capacity": {From: nil, To: float64(10)},
+	}
+	if got := diff(from, to); !reflect.DeepEqual(got, want) {
+		t.Fatalf("update: got %v, want %v", got, want)
+	}
+	if got := diff(nil, to); len(got) != 3 || got["name"].To != "Riyadh North DC" || got["name"].From != nil {
+		t.Fatalf("create: got %v, want every field but version and updated_at", got)
+	}
+	if got := diff(from, nil); len(got) != 3 || got["name"].From != "Riyadh DC" || got["name"].To != nil {
+		t.Fatalf("delete: got %v, want every field but version and updated_at", got)
+	}
+}
+
+func TestToJSONKeepsNil(t *testing.T) {
+	var missing *entity
+	for _, v := range []interface{}{nil, missing} {
+		raw, fields, err := toJSON(v)
+		if raw != nil || fields != nil || err != nil {
+			t.Errorf("toJSON(%#v): got %s, %v, %v, want nil", v, raw, fields, err)
+		}
+	}
+}
+
+func TestMetadataTravelsInTheContext(t *testing.T) {
+	if m := metadataFromContext(context.Background()); m != (Metadata{}) {
+		t.Fatalf("bare context: got %+v", m)
+	}
+	ctx := WithMetadata(context.Background(), System("order-consumer"))
+	if m := metadataFromContext(ctx); m.ActorID != "system:order-consumer" {
+		t.Fatalf("got %+v, want the system actor", m)
+	}
+}
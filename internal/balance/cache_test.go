@@ -0,0 +1,71 @@
+package balance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return &Cache{client: client, prefix: "ims", ttl: time.Minute}, server
+}
+
+func TestSetKeepsTheNewestBalance(t *testing.T) {
+	c, server := newTestCache(t)
+	ctx := context.Background()
+	key := Key{TenantID: 1, HubID: 2, SKUID: 3}
+
+	if err := c.Set(ctx, map[Key]Balance{key: {Qty: 10, Reserved: 2, Version: 5}}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	skipped := metrics.StaleSkipped.Load()
+	if err := c.Set(ctx, map[Key]Balance{key: {Qty: 4, Version: 4}}); err != nil {
+		t.Fatalf("Set of an older balance: %v", err)
+	}
+	if metrics.StaleSkipped.Load() != skipped+1 {
+		t.Error("the older balance was not counted as skipped")
+	}
+	found, err := c.Get(ctx, []Key{key, {TenantID: 1, HubID: 2, SKUID: 4}})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(found) != 1 || found[key] != (Balance{Qty: 10, Reserved: 2, Version: 5}) {
+		t.Fatalf("got %+v, want the balance at version 5 and a miss", found)
+	}
+	if ttl := server.TTL("ims:inventory_balance:1:2:3"); ttl != time.Minute {
+		t.Errorf("got TTL %v, want %v", ttl, time.Minute)
+	}
+
+	if err := c.Set(ctx, map[Key]Balance{key: {Qty: 6, Version: 6}}); err != nil {
+		t.Fatalf("Set of a newer balance: %v", err)
+	}
+	if found, _ := c.Get(ctx, []Key{key}); found[key].Version != 6 {
+		t.Fatalf("got %+v, want the balance at version 6", found[key])
+	}
+}
+
+func TestDeleteDropsBalances(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	kept, dropped := Key{TenantID: 1, HubID: 2, SKUID: 3}, Key{TenantID: 1, HubID: 2, SKUID: 4}
+	if err := c.Set(ctx, map[Key]Balance{kept: {Qty: 1, Version: 1}, dropped: {Qty: 2, Version: 1}}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := c.Delete(ctx, []Key{dropped}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	found, err := c.Get(ctx, []Key{kept, dropped})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, ok := found[dropped]; ok || len(found) != 1 {
+		t.Fatalf("got %+v, want only the kept balance", found)
+	}
+}
@@ -0,0 +1,44 @@
+package balance
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts balance cache lookups in this process since it started.
+type Metrics struct {
+	Hits         atomic.Int64
+	Misses       atomic.Int64
+	Errors       atomic.Int64
+	StaleSkipped atomic.Int64
+}
+
+type MetricsSnapshot struct {
+	Hits         int64   `json:"hits"`
+	Misses       int64   `json:"misses"`
+	Errors       int64   `json:"errors"`
+	StaleSkipped int64   `json:"stale_skipped"`
+	HitRatio     float64 `json:"hit_ratio"`
+}
+
+var metrics Metrics
+
+func (m *Metrics) snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{
+		Hits:         m.Hits.Load(),
+		Misses:       m.Misses.Load(),
+		Errors:       m.Errors.Load(),
+		StaleSkipped: m.StaleSkipped.Load(),
+	}
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		s.HitRatio = float64(s.Hits) / float64(lookups)
+	}
+	return s
+}
+
+// MetricsHandler serves the balance cache counters and the hit ratio.
+func MetricsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, metrics.snapshot())
+}
@@ -0,0 +1,119 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/go_commons/pubsub"
+	"github.com/omniful/ims_rohit/internal/audit"
+)
+
+// permanentError marks a failure that retrying cannot fix, such as a malformed message.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the consumer dead-letters the message without retrying it.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+type Config struct {
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// Consumer runs a message handler with bounded retries. Messages that still fail, or fail
+// permanently, are stored as dead letters and acknowledged so they stop blocking the
+// partition. They can be replayed later through the admin API.
+type Consumer struct {
+	name    string
+	handler pubsub.IPubSubMessageHandler
+	cfg     Config
+	metrics *Metrics
+}
+
+var (
+	consumersMu sync.RWMutex
+	consumers   = map[string]*Consumer{}
+)
+
+// Wrap returns a consumer for the named listener. Consumers are kept by name so dead
+// letters can be replayed through the handler that originally failed them.
+func Wrap(name string, handler pubsub.IPubSubMessageHandler, cfg Config) *Consumer {
+	c := &Consumer{
+		name:    name,
+		handler: handler,
+		cfg:     cfg,
+		metrics: metricsFor(name),
+	}
+	consumersMu.Lock()
+	consumers[name] = c
+	consumersMu.Unlock()
+	return c
+}
+
+func registeredConsumers() map[string]*Consumer {
+	consumersMu.RLock()
+	defer consumersMu.RUnlock()
+	out := make(map[string]*Consumer, len(consumers))
+	for name, c := range consumers {
+		out[name] = c
+	}
+	return out
+}
+
+// Process is the pubsub.IPubSubMessageHandler the worker registry calls. It only returns
+// an error, leaving the message to be redelivered, when the dead letter cannot be stored.
+func (c *Consumer) Process(ctx context.Context, message *pubsub.Message) error {
+	c.metrics.Received.Add(1)
+
+	attempts, err := c.attempt(ctx, message)
+	if err == nil {
+		c.metrics.Succeeded.Add(1)
+		return nil
+	}
+
+	log.Errorf("Consumer %s failed on %s after %d attempts, dead-lettering: %s", c.name, message.Topic, attempts, err.Error())
+	if dlqErr := storeDeadLetter(ctx, c.name, message, err, attempts); dlqErr != nil {
+		c.metrics.DeadLetterFailures.Add(1)
+		return fmt.Errorf("failed to dead-letter message: %w (handler error: %v)", dlqErr, err)
+	}
+	c.metrics.DeadLettered.Add(1)
+	return nil
+}
+
+// attempt runs the handler until it succeeds, fails permanently, or runs out of attempts,
+// waiting RetryBackoff times the attempt number between tries. Changes the handler makes
+// are audited as made by the consumer.
+func (c *Consumer) attempt(ctx context.Context, message *pubsub.Message) (int, error) {
+	ctx = audit.WithMetadata(ctx, audit.System("consumer:"+c.name))
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = c.handler.Process(ctx, message); err == nil {
+			return attempt, nil
+		}
+		if isPermanent(err) || attempt >= c.cfg.MaxAttempts {
+			return attempt, err
+		}
+
+		c.metrics.Retried.Add(1)
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(c.cfg.RetryBackoff * time.Duration(attempt)):
+		}
+	}
+}
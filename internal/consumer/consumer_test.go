@@ -0,0 +1,69 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/omniful/go_commons/pubsub"
+)
+
+// flakyHandler fails with err until it has been called failures times.
+type flakyHandler struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (h *flakyHandler) Process(ctx context.Context, message *pubsub.Message) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return h.err
+	}
+	return nil
+}
+
+func TestAttemptRetries(t *testing.T) {
+	errBroken := errors.New("broken")
+	cases := []struct {
+		name         string
+		handler      *flakyHandler
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"succeeds at once", &flakyHandler{}, 1, false},
+		{"succeeds on retry", &flakyHandler{failures: 2, err: errBroken}, 3, false},
+		{"runs out of attempts", &flakyHandler{failures: 5, err: errBroken}, 3, true},
+		{"fails permanently", &flakyHandler{failures: 5, err: Permanent(errBroken)}, 1, true},
+	}
+	for _, c := range cases {
+		consumer := Wrap("test-"+c.name, c.handler, Config{MaxAttempts: 3})
+		attempts, err := consumer.attempt(context.Background(), &pubsub.Message{Topic: "orders"})
+		if attempts != c.wantAttempts || (err != nil) != c.wantErr {
+			t.Errorf("%s: got %d attempts and %v, want %d attempts, error %v", c.name, attempts, err, c.wantAttempts, c.wantErr)
+		}
+		if c.wantErr && !errors.Is(err, errBroken) {
+			t.Errorf("%s: got %v, want the handler's error", c.name, err)
+		}
+	}
+}
+
+func TestAttemptStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	consumer := Wrap("test-cancelled", &flakyHandler{failures: 5, err: errors.New("broken")}, Config{MaxAttempts: 3, RetryBackoff: time.Hour})
+
+	attempts, err := consumer.attempt(ctx, &pubsub.Message{Topic: "orders"})
+	if attempts != 1 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %d attempts and %v, want 1 attempt and context.Canceled", attempts, err)
+	}
+}
+
+func TestDeadLetterMessage(t *testing.T) {
+	d := &DeadLetter{Topic: "orders", Key: "o-1", Payload: `{"order_id":"o-1"}`, Headers: map[string]string{"tenant": "1"}}
+	m := d.message()
+	if m.Topic != "orders" || m.Key != "o-1" || string(m.Value) != d.Payload || m.Headers["tenant"] != "1" {
+		t.Fatalf("got %+v, want the stored message", m)
+	}
+}
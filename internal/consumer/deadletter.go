@@ -0,0 +1,253 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/go_commons/pubsub"
+	"github.com/omniful/ims_rohit/pkg/keyset"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterPending         DeadLetterStatus = "pending"
+	DeadLetterReplayRequested DeadLetterStatus = "replay_requested"
+	DeadLetterReplaying       DeadLetterStatus = "replaying"
+	DeadLetterReplayed        DeadLetterStatus = "replayed"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrDeadLetterReplayed = errors.New("dead letter already replayed")
+)
+
+type DeadLetter struct {
+	ID         int64             `json:"id"`
+	Handler    string            `json:"handler"`
+	Topic      string            `json:"topic"`
+	Key        string            `json:"key"`
+	Headers    map[string]string `json:"headers"`
+	Payload    string            `json:"payload"`
+	Error      string            `json:"error"`
+	Attempts   int               `json:"attempts"`
+	Status     DeadLetterStatus  `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ReplayedAt *time.Time        `json:"replayed_at,omitempty"`
+}
+
+const deadLetterColumns = `id, handler, topic, message_key, headers, payload, error, attempts, status, created_at, replayed_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDeadLetter(row rowScanner) (*DeadLetter, error) {
+	d := &DeadLetter{}
+	var (
+		headers []byte
+		payload []byte
+	)
+	err := row.Scan(&d.ID, &d.Handler, &d.Topic, &d.Key, &headers, &payload, &d.Error, &d.Attempts, &d.Status,
+		&d.CreatedAt, &d.ReplayedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = string(payload)
+	if err := json.Unmarshal(headers, &d.Headers); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *DeadLetter) message() *pubsub.Message {
+	return &pubsub.Message{
+		Topic:   d.Topic,
+		Key:     d.Key,
+		Value:   []byte(d.Payload),
+		Headers: d.Headers,
+	}
+}
+
+func storeDeadLetter(ctx context.Context, handler string, message *pubsub.Message, cause error, attempts int) error {
+	db := pg.GetClient().DB
+	headers, err := json.Marshal(message.Headers)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO dead_letters (handler, topic, message_key, headers, payload, error, attempts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		handler, message.Topic, message.Key, headers, message.Value, cause.Error(), attempts)
+	return err
+}
+
+type DeadLetterFilter struct {
+	Handler string
+	Topic   string
+	Status  DeadLetterStatus
+}
+
+// ListDeadLetters returns one page of dead letters, newest first.
+func ListDeadLetters(ctx context.Context, f DeadLetterFilter, page keyset.Page) ([]*DeadLetter, *keyset.Meta, error) {
+	db := pg.GetClient().DB
+	beforeID, err := page.Before()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where := ` WHERE TRUE`
+	var args []interface{}
+	if f.Handler != "" {
+		args = append(args, f.Handler)
+		where += fmt.Sprintf(` AND handler = $%d`, len(args))
+	}
+	if f.Topic != "" {
+		args = append(args, f.Topic)
+		where += fmt.Sprintf(` AND topic = $%d`, len(args))
+	}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		where += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+
+	var total *int64
+	if beforeID == 0 {
+		total = new(int64)
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM dead_letters`+where, args...).Scan(total); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		args = append(args, beforeID)
+		where += fmt.Sprintf(` AND id < $%d`, len(args))
+	}
+
+	query := fmt.Sprintf(`SELECT `+deadLetterColumns+` FROM dead_letters%s ORDER BY id DESC LIMIT $%d`, where, len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	letters := []*DeadLetter{}
+	for rows.Next() {
+		d, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		letters = append(letters, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	letters, meta := keyset.Trim(page, letters, func(d *DeadLetter) int64 { return d.ID })
+	meta.Total = total
+	return letters, meta, nil
+}
+
+// RequestReplay queues a dead letter for the worker to run through its handler again.
+func RequestReplay(ctx context.Context, id int64) (*DeadLetter, error) {
+	db := pg.GetClient().DB
+	d, err := scanDeadLetter(db.QueryRowContext(ctx, `
+		UPDATE dead_letters SET status = $1
+		WHERE id = $2 AND status <> $3
+		RETURNING `+deadLetterColumns, DeadLetterReplayRequested, id, DeadLetterReplayed))
+	if err != sql.ErrNoRows {
+		return d, err
+	}
+
+	var exists bool
+	if err = db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM dead_letters WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrDeadLetterReplayed
+	}
+	return nil, ErrDeadLetterNotFound
+}
+
+// RunReplays replays dead letters queued through the admin API for the consumers of this
+// worker, until ctx is done.
+func RunReplays(ctx context.Context, interval time.Duration, batchSize int) {
+	for {
+		if err := replayBatch(ctx, batchSize); err != nil {
+			log.WithError(err).Error("dead letter replay failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// replayBatch runs up to batchSize queued dead letters through their handler with the
+// usual retries. A replay that fails again goes back to pending with the new error.
+func replayBatch(ctx context.Context, batchSize int) error {
+	registered := registeredConsumers()
+	if len(registered) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+
+	for i := 0; i < batchSize; i++ {
+		d, err := claimReplay(ctx, names)
+		if err != nil || d == nil {
+			return err
+		}
+		if err := replay(ctx, registered[d.Handler], d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimReplay marks the oldest queued dead letter of the handlers as replaying and returns
+// it, or nil when none is queued. The claim commits before the handler runs, as handlers
+// commit their own changes: a letter is never run twice because recording the outcome of
+// another one failed. A letter left replaying by a worker that stopped mid-replay can be
+// queued again with RequestReplay.
+func claimReplay(ctx context.Context, handlers []string) (*DeadLetter, error) {
+	db := pg.GetClient().DB
+	d, err := scanDeadLetter(db.QueryRowContext(ctx, `
+		UPDATE dead_letters SET status = $1
+		WHERE id = (
+			SELECT id FROM dead_letters
+			WHERE status = $2 AND handler = ANY($3)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deadLetterColumns, DeadLetterReplaying, DeadLetterReplayRequested, pq.Array(handlers)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+// replay runs a claimed dead letter through its handler and records the outcome.
+func replay(ctx context.Context, c *Consumer, d *DeadLetter) error {
+	db := pg.GetClient().DB
+	attempts, replayErr := c.attempt(ctx, d.message())
+	if replayErr == nil {
+		c.metrics.Replayed.Add(1)
+		_, err := db.ExecContext(ctx, `UPDATE dead_letters SET status = $1, attempts = attempts + $2, replayed_at = NOW() WHERE id = $3`,
+			DeadLetterReplayed, attempts, d.ID)
+		return err
+	}
+	c.metrics.ReplayFailures.Add(1)
+	_, err := db.ExecContext(ctx, `UPDATE dead_letters SET status = $1, attempts = attempts + $2, error = $3 WHERE id = $4`,
+		DeadLetterPending, attempts, replayErr.Error(), d.ID)
+	return err
+}
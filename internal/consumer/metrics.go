@@ -0,0 +1,59 @@
+package consumer
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts what a consumer did with the messages it received.
+type Metrics struct {
+	Received           atomic.Int64
+	Succeeded          atomic.Int64
+	Retried            atomic.Int64
+	DeadLettered       atomic.Int64
+	DeadLetterFailures atomic.Int64
+	Replayed           atomic.Int64
+	ReplayFailures     atomic.Int64
+}
+
+type MetricsSnapshot struct {
+	Received           int64 `json:"received"`
+	Succeeded          int64 `json:"succeeded"`
+	Retried            int64 `json:"retried"`
+	DeadLettered       int64 `json:"dead_lettered"`
+	DeadLetterFailures int64 `json:"dead_letter_failures"`
+	Replayed           int64 `json:"replayed"`
+	ReplayFailures     int64 `json:"replay_failures"`
+}
+
+var metrics sync.Map // handler name -> *Metrics
+
+func metricsFor(name string) *Metrics {
+	m, _ := metrics.LoadOrStore(name, &Metrics{})
+	return m.(*Metrics)
+}
+
+func (m *Metrics) snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Received:           m.Received.Load(),
+		Succeeded:          m.Succeeded.Load(),
+		Retried:            m.Retried.Load(),
+		DeadLettered:       m.DeadLettered.Load(),
+		DeadLetterFailures: m.DeadLetterFailures.Load(),
+		Replayed:           m.Replayed.Load(),
+		ReplayFailures:     m.ReplayFailures.Load(),
+	}
+}
+
+// MetricsHandler serves the counters of every consumer in this process since it started.
+func MetricsHandler(c *gin.Context) {
+	out := map[string]MetricsSnapshot{}
+	metrics.Range(func(name, m interface{}) bool {
+		out[name.(string)] = m.(*Metrics).snapshot()
+		return true
+	})
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,134 @@
+package gqlapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+// Limits bounds the queries the API runs. Depth counts nested selections; complexity
+// counts every field once per object it is resolved for, so list fields multiply the
+// cost of their selections by the number of items they may return. A limit of 0 is no
+// limit.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// listSize is the most items a list field may return given its arguments; other fields
+// return one.
+func listSize(field *ast.Field, variables map[string]interface{}) int {
+	switch field.Name.Value {
+	case "hubs", "skus", "balances":
+		limit := 0
+		if v, ok := argument(field, "limit", variables).(int); ok {
+			limit = v
+		}
+		switch {
+		case limit <= 0:
+			return inventory.DefaultPageLimit
+		case limit > inventory.MaxPageLimit:
+			return inventory.MaxPageLimit
+		}
+		return limit
+	case "inventory":
+		if ids, ok := argument(field, "sku_ids", variables).([]interface{}); ok {
+			return len(ids)
+		}
+	}
+	return 1
+}
+
+// argument returns the value of a field's argument, an int, a list or nil, reading
+// variables it refers to.
+func argument(field *ast.Field, name string, variables map[string]interface{}) interface{} {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value == name {
+			return literal(arg.Value, variables)
+		}
+	}
+	return nil
+}
+
+func literal(value ast.Value, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, _ := strconv.Atoi(v.Value)
+		return n
+	case *ast.ListValue:
+		return make([]interface{}, len(v.Values))
+	case *ast.Variable:
+		switch value := variables[v.Name.Value].(type) {
+		case float64:
+			return int(value)
+		case int:
+			return value
+		case []interface{}:
+			return value
+		}
+	}
+	return nil
+}
+
+// check rejects the operation to run when it is nested deeper or costs more than the
+// limits allow. The document must be valid.
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[f.Name.Value] = f
+		}
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || (operationName != "" && (op.Name == nil || op.Name.Value != operationName)) {
+			continue
+		}
+		m := &measure{fragments: fragments, variables: variables}
+		complexity := m.selections(op.SelectionSet, 1)
+		if l.MaxDepth > 0 && m.depth > l.MaxDepth {
+			return fmt.Errorf("query is nested %d levels deep, more than the limit of %d", m.depth, l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return fmt.Errorf("query complexity is %d, more than the limit of %d", complexity, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type measure struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	depth     int
+}
+
+func (m *measure) selections(set *ast.SelectionSet, depth int) int {
+	if set == nil {
+		return 0
+	}
+	if depth > m.depth {
+		m.depth = depth
+	}
+	cost := 0
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			// Introspection reads the schema, not the database.
+			if strings.HasPrefix(s.Name.Value, "__") {
+				cost++
+				continue
+			}
+			cost += 1 + listSize(s, m.variables)*m.selections(s.SelectionSet, depth+1)
+		case *ast.InlineFragment:
+			cost += m.selections(s.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if f, ok := m.fragments[s.Name.Value]; ok {
+				cost += m.selections(f.SelectionSet, depth)
+			}
+		}
+	}
+	return cost
+}
@@ -0,0 +1,92 @@
+package gqlapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+func TestLimitsCheck(t *testing.T) {
+	// hubs: 1 + 5 × (id: 1 + balances: 1 + 10 × qty: 1) = 61, three levels deep.
+	const nested = `{ hubs(limit: 5) { id balances(limit: 10) { qty } } }`
+	cases := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		limits    Limits
+		wantErr   bool
+	}{
+		{"within limits", nested, "", nil, Limits{MaxDepth: 3, MaxComplexity: 61}, false},
+		{"too deep", nested, "", nil, Limits{MaxDepth: 2}, true},
+		{"too complex", nested, "", nil, Limits{MaxComplexity: 60}, true},
+		{"no limits", nested, "", nil, Limits{}, false},
+		{"limit from a variable", `query($n: Int) { hubs(limit: $n) { id } }`, "", map[string]interface{}{"n": float64(50)},
+			Limits{MaxComplexity: 50}, true},
+		{"limit capped at the page maximum", `{ hubs(limit: 100000) { id } }`, "", nil, Limits{MaxComplexity: 1000}, false},
+		{"sku_ids sizes inventory", `{ inventory(hub_id: 1, sku_ids: [1, 2, 3]) { qty } }`, "", nil, Limits{MaxComplexity: 3}, true},
+		{"fragments count", `{ hubs(limit: 5) { ...f } } fragment f on Hub { id name }`, "", nil, Limits{MaxComplexity: 10}, true},
+		{"only the chosen operation", `query a { hub(id: 1) { id } } query b ` + nested, "a", nil, Limits{MaxDepth: 2}, false},
+	}
+	for _, c := range cases {
+		doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(c.query)})})
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if err := c.limits.check(doc, c.operation, c.variables); (err != nil) != c.wantErr {
+			t.Errorf("%s: got %v, want an error: %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestLoaderBatchesKeys(t *testing.T) {
+	var batches [][]int64
+	l := newLoader(func(keys []int64) (map[int64]string, error) {
+		batches = append(batches, keys)
+		values := map[int64]string{}
+		for _, k := range keys {
+			if k != 3 {
+				values[k] = "v"
+			}
+		}
+		return values, nil
+	})
+
+	thunks := []func() (interface{}, error){l.load(1), l.load(2), l.load(1), l.load(3)}
+	for i, thunk := range thunks {
+		v, err := thunk()
+		if err != nil {
+			t.Fatalf("thunk %d: %v", i, err)
+		}
+		if want := map[bool]string{true: "", false: "v"}[i == 3]; v != want {
+			t.Errorf("thunk %d: got %q, want %q", i, v, want)
+		}
+	}
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("got batches %v, want one fetch of 1, 2 and 3", batches)
+	}
+
+	l.prime(4, "primed")
+	if v, _ := l.load(4)(); v != "primed" {
+		t.Errorf("primed key: got %v", v)
+	}
+	if v, _ := l.load(2)(); v != "v" || len(batches) != 1 {
+		t.Errorf("loaded key: got %v after %d fetches, want it kept", v, len(batches))
+	}
+}
+
+func TestLoaderReportsFetchErrors(t *testing.T) {
+	errDown := errors.New("down")
+	l := newLoader(func(keys []int64) (map[int64]string, error) { return nil, errDown })
+	thunk := l.load(1)
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, errDown) {
+			t.Fatalf("got panic %v, want the fetch error", err)
+		}
+	}()
+	thunk()
+	t.Fatal("the thunk did not fail")
+}
@@ -0,0 +1,334 @@
+// Package gqlapi serves a read-only GraphQL API over hubs, SKUs and stock, for clients
+// that would otherwise stitch them together from many REST calls. It reads through the
+// same repositories and applies the same tenant and hub/seller access checks as the REST
+// handlers. Related objects are loaded in batches, one query per level of the query and
+// kind of object, and queries over the configured depth or complexity are refused.
+package gqlapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/jwt/public"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/internal/access_control"
+	"github.com/omniful/ims_rohit/internal/apierror"
+	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+// Request is a GraphQL request, as POSTed by GraphQL clients.
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Response is a GraphQL response. Errors carry the error code of the API in
+// extensions.code; the HTTP status is 200 whenever the request could be read.
+type Response = graphql.Result
+
+// API answers GraphQL requests.
+type API struct {
+	hubs   inventory.HubRepository
+	skus   inventory.SKURepository
+	stock  inventory.InventoryRepository
+	access *access_control.AccessControl
+	limits Limits
+	schema graphql.Schema
+}
+
+// New builds the API over the repositories, checking callers with access.
+func New(
+	hubs inventory.HubRepository,
+	skus inventory.SKURepository,
+	stock inventory.InventoryRepository,
+	access *access_control.AccessControl,
+	limits Limits,
+) (*API, error) {
+	a := &API{hubs: hubs, skus: skus, stock: stock, access: access, limits: limits}
+	schema, err := a.buildSchema()
+	if err != nil {
+		return nil, err
+	}
+	a.schema = schema
+	return a, nil
+}
+
+// Handler runs the query of a Request. It must run after JWT authentication.
+func (a *API) Handler(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, failed(oerror.RequestInvalid, "query is required"))
+		return
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		c.JSON(http.StatusOK, &Response{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	if validation := graphql.ValidateDocument(&a.schema, doc, nil); !validation.IsValid {
+		c.JSON(http.StatusOK, &Response{Errors: validation.Errors})
+		return
+	}
+	if err := a.limits.check(doc, req.OperationName, req.Variables); err != nil {
+		c.JSON(http.StatusOK, failed(oerror.RequestInvalid, err.Error()))
+		return
+	}
+
+	ctx := context.WithValue(c, requestKey{}, newRequest(c, a))
+	c.JSON(http.StatusOK, graphql.Execute(graphql.ExecuteParams{
+		Schema:        a.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	}))
+}
+
+// --- Root fields ---
+
+func (a *API) getHub(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFrom(p.Context)
+	id, err := idArg(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	hub, err := a.hubs.GetHub(r.c, id)
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	if hub == nil {
+		return nil, newError(pkgerror.NotFound, "hub not found")
+	}
+	if err := a.authorize(r.c, hub.GetHubIDs(), nil); err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+func (a *API) listHubs(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFrom(p.Context)
+	hubIDs, err := idsArg(p.Args, "hub_ids")
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := a.scope(r.c, a.access.ValidateAndSetHubIDs, hubIDs)
+	if err != nil {
+		return nil, err
+	}
+	filter := inventory.HubFilter{}
+	filter.NamePrefix, _ = p.Args["name_prefix"].(string)
+	statuses, _ := p.Args["statuses"].([]interface{})
+	for _, status := range statuses {
+		filter.Statuses = append(filter.Statuses, status.(inventory.HubStatus))
+	}
+	hubs, meta, err := a.hubs.ListHubs(ctx, filter, pageArg(p.Args))
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	// The hubs of the page were loaded already.
+	for _, hub := range hubs {
+		r.hubs.prime(hub.ID, hub)
+	}
+	page := &hubPage{Items: hubs, NextCursor: nextCursor(meta)}
+	if meta != nil {
+		page.Total = meta.Total
+	}
+	return page, nil
+}
+
+func (a *API) getSKU(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFrom(p.Context)
+	id, err := idArg(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	sku, err := a.skus.GetSKU(r.c, id)
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	if sku == nil {
+		return nil, newError(pkgerror.NotFound, "sku not found")
+	}
+	if err := a.authorize(r.c, nil, sku.GetSellerIDs()); err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+func (a *API) listSKUs(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFrom(p.Context)
+	sellerID, err := optionalIDArg(p.Args, "seller_id")
+	if err != nil {
+		return nil, err
+	}
+	var sellerIDs []int64
+	if sellerID != nil {
+		sellerIDs = []int64{*sellerID}
+	}
+	ctx, err := a.scope(r.c, a.access.ValidateAndSetSellerIDs, sellerIDs)
+	if err != nil {
+		return nil, err
+	}
+	filter := inventory.SKUFilter{SellerID: sellerID, SKUCodes: stringsArg(p.Args, "sku_codes")}
+	filter.NamePrefix, _ = p.Args["name_prefix"].(string)
+	skus, meta, err := a.skus.ListSKUs(ctx, filter, pageArg(p.Args))
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	page := &skuPage{Items: skus, NextCursor: nextCursor(meta)}
+	if meta != nil {
+		page.Total = meta.Total
+	}
+	return page, nil
+}
+
+func (a *API) viewInventory(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFrom(p.Context)
+	hubID, err := idArg(p.Args, "hub_id")
+	if err != nil {
+		return nil, err
+	}
+	skuIDs, err := idsArg(p.Args, "sku_ids")
+	if err != nil {
+		return nil, err
+	}
+	if len(skuIDs) == 0 {
+		return nil, newError(oerror.RequestInvalid, "sku_ids is required")
+	}
+	if err := a.authorize(r.c, []string{strconv.FormatInt(hubID, 10)}, nil); err != nil {
+		return nil, err
+	}
+	// SKUs of sellers outside the caller's scope are left out, as if they did not exist.
+	ctx, err := a.scope(r.c, a.access.ValidateAndSetSellerIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	invs, err := a.stock.ViewInventory(ctx, hubID, skuIDs)
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	if invs == nil {
+		invs = []*inventory.Inventory{}
+	}
+	return invs, nil
+}
+
+// --- Relations ---
+
+// hubBalances lists the stock of a hub. The hub was checked by the field that returned it;
+// balances of SKUs of sellers outside the caller's scope are left out.
+func (a *API) hubBalances(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFrom(p.Context)
+	hub := p.Source.(*inventory.Hub)
+	skuIDs, err := idsArg(p.Args, "sku_ids")
+	if err != nil {
+		return nil, err
+	}
+	limit, _ := p.Args["limit"].(int)
+	filter := inventory.HubStockFilter{SKUIDs: skuIDs, MinQty: intArg(p.Args, "min_qty"), MaxQty: intArg(p.Args, "max_qty")}
+	return r.stock(filter, limit).load(hub.ID), nil
+}
+
+// balanceHub is the hub of a balance, which the field returning the balance checked.
+func (a *API) balanceHub(p graphql.ResolveParams) (interface{}, error) {
+	return requestFrom(p.Context).hubs.load(p.Source.(*inventory.Inventory).HubID), nil
+}
+
+func (a *API) balanceSKU(p graphql.ResolveParams) (interface{}, error) {
+	return requestFrom(p.Context).skus.load(p.Source.(*inventory.Inventory).SKUID), nil
+}
+
+// --- Access checks ---
+
+// authorize checks the caller may access the hubs and sellers, like the response checks
+// of the REST API.
+func (a *API) authorize(c *gin.Context, hubIDs, sellerIDs []string) error {
+	tenantID, err := public.GetTenantID(c)
+	if err != nil {
+		return newError(pkgerror.Unauthenticated, err.Error())
+	}
+	validHubs, err := a.access.ValidateHubIDs(c, tenantID, hubIDs)
+	if err != nil {
+		log.WithError(err).Error("access control validation failed")
+		return newError(pkgerror.InternalError, "internal server error")
+	}
+	validSellers, err := a.access.ValidateSellerIDs(c, tenantID, sellerIDs)
+	if err != nil {
+		log.WithError(err).Error("access control validation failed")
+		return newError(pkgerror.InternalError, "internal server error")
+	}
+	if !validHubs || !validSellers {
+		return newError(pkgerror.Forbidden, "you don't have access to this resource")
+	}
+	return nil
+}
+
+// scope checks the caller may access ids with validateAndSet, one of the access control's
+// ValidateAndSet methods, and returns a context limiting listings to the permitted ones.
+// Without ids, the context holds the caller's whole scope.
+func (a *API) scope(
+	c *gin.Context,
+	validateAndSet func(c *gin.Context, tenantID string, ids []string) (bool, error),
+	ids []int64,
+) (context.Context, error) {
+	// Keys set for this listing stay with it.
+	c = c.Copy()
+	tenantID, err := public.GetTenantID(c)
+	if err != nil {
+		return nil, newError(pkgerror.Unauthenticated, err.Error())
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	valid, err := validateAndSet(c, tenantID, values)
+	if err != nil {
+		log.WithError(err).Error("access control validation failed")
+		return nil, newError(pkgerror.InternalError, "internal server error")
+	}
+	if !valid {
+		return nil, newError(pkgerror.Forbidden, "you don't have access to this resource")
+	}
+	return c, nil
+}
+
+// --- Errors ---
+
+// apiError is a field error carrying an error code of the API in its extensions.
+type apiError struct {
+	code    oerror.Code
+	message string
+}
+
+func newError(code oerror.Code, message string) error {
+	return &apiError{code: code, message: message}
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func (e *apiError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// inventoryError is the field error of an error of the inventory package.
+func inventoryError(err error) error {
+	cusErr := apierror.New(err)
+	return newError(cusErr.ErrorCode(), cusErr.ErrorMessage())
+}
+
+// failed is the response to a request that was refused before running.
+func failed(code oerror.Code, message string) *Response {
+	e := &apiError{code: code, message: message}
+	return &Response{Errors: []gqlerrors.FormattedError{{Message: e.message, Extensions: e.Extensions()}}}
+}
@@ -0,0 +1,81 @@
+package gqlapi
+
+import "sync"
+
+// loader batches the reads of one request. Keys loaded while resolving one level of a
+// query are fetched together when the first of their values is needed: the executor
+// creates all thunks of a level before resolving any of them, so each level costs one
+// fetch per loader however many objects it has. Values are kept for the rest of the
+// request.
+type loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: map[K]bool{},
+		values: map[K]V{},
+		errs:   map[K]error{},
+	}
+}
+
+// load returns a thunk resolving to the value of key, the zero value when fetch did not
+// return it.
+func (l *loader[K, V]) load(key K) func() (interface{}, error) {
+	l.mu.Lock()
+	_, loaded := l.values[key]
+	if !loaded && !l.queued[key] && l.errs[key] == nil {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		v, err := l.get(key)
+		if err != nil {
+			// Errors returned from a thunk lose their extensions; the executor reports a
+			// panic like an error returned by the resolver.
+			panic(err)
+		}
+		return v, nil
+	}
+}
+
+// prime stores a value loaded by other means.
+func (l *loader[K, V]) prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.queued[key] {
+		l.values[key] = value
+	}
+}
+
+func (l *loader[K, V]) get(key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending) > 0 {
+		keys := l.pending
+		l.pending = nil
+		values, err := l.fetch(keys)
+		for _, k := range keys {
+			delete(l.queued, k)
+			if err != nil {
+				l.errs[k] = err
+				continue
+			}
+			l.values[k] = values[k]
+		}
+	}
+	if err := l.errs[key]; err != nil {
+		var zero V
+		return zero, err
+	}
+	return l.values[key], nil
+}
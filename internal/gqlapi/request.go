@@ -0,0 +1,128 @@
+package gqlapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/go_commons/jwt/public"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+type requestKey struct{}
+
+// request holds the state of one GraphQL request: the gin context the repositories and
+// access checks read the caller from, and the loaders batching its reads.
+type request struct {
+	c    *gin.Context
+	api  *API
+	hubs *loader[int64, *inventory.Hub]
+	skus *loader[int64, *inventory.SKU]
+
+	mu       sync.Mutex
+	balances map[stockKey]*loader[int64, []*inventory.Inventory]
+}
+
+// stockKey identifies the arguments of a balances field; the balances of hubs listed with
+// the same arguments are loaded together.
+type stockKey struct {
+	skuIDs         string
+	minQty, maxQty string
+	limit          int
+}
+
+func newRequest(c *gin.Context, api *API) *request {
+	r := &request{c: c, api: api, balances: map[stockKey]*loader[int64, []*inventory.Inventory]{}}
+	r.hubs = newLoader(r.fetchHubs)
+	r.skus = newLoader(r.fetchSKUs)
+	return r
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// stock returns the loader of the balances listed with filter and limit.
+func (r *request) stock(filter inventory.HubStockFilter, limit int) *loader[int64, []*inventory.Inventory] {
+	key := stockKey{
+		skuIDs: fmt.Sprint(filter.SKUIDs),
+		minQty: optionalString(filter.MinQty),
+		maxQty: optionalString(filter.MaxQty),
+		limit:  limit,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.balances[key]
+	if !ok {
+		l = newLoader(func(hubIDs []int64) (map[int64][]*inventory.Inventory, error) {
+			ctx, err := r.api.scope(r.c, r.api.access.ValidateAndSetSellerIDs, nil)
+			if err != nil {
+				return nil, err
+			}
+			stock, err := r.api.stock.ListHubsInventory(ctx, hubIDs, filter, limit)
+			if err != nil {
+				return nil, inventoryError(err)
+			}
+			for _, hubID := range hubIDs {
+				if stock[hubID] == nil {
+					stock[hubID] = []*inventory.Inventory{}
+				}
+			}
+			return stock, nil
+		})
+		r.balances[key] = l
+	}
+	return l
+}
+
+func optionalString(n *int64) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatInt(*n, 10)
+}
+
+func (r *request) fetchHubs(ids []int64) (map[int64]*inventory.Hub, error) {
+	hubs, err := r.api.hubs.GetHubs(r.c, ids)
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	byID := make(map[int64]*inventory.Hub, len(hubs))
+	for _, hub := range hubs {
+		byID[hub.ID] = hub
+	}
+	return byID, nil
+}
+
+// fetchSKUs loads SKUs, leaving out those of sellers the caller may not access. Each
+// seller is checked once.
+func (r *request) fetchSKUs(ids []int64) (map[int64]*inventory.SKU, error) {
+	skus, err := r.api.skus.GetSKUs(r.c, ids)
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	tenantID, err := public.GetTenantID(r.c)
+	if err != nil {
+		return nil, newError(pkgerror.Unauthenticated, err.Error())
+	}
+	allowed := map[int64]bool{}
+	byID := make(map[int64]*inventory.SKU, len(skus))
+	for _, sku := range skus {
+		ok, checked := allowed[sku.SellerID]
+		if !checked {
+			if ok, err = r.api.access.ValidateSellerIDs(r.c, tenantID, sku.GetSellerIDs()); err != nil {
+				log.WithError(err).Error("access control validation failed")
+				return nil, newError(pkgerror.InternalError, "internal server error")
+			}
+			allowed[sku.SellerID] = ok
+		}
+		if ok {
+			byID[sku.ID] = sku
+		}
+	}
+	return byID, nil
+}
@@ -0,0 +1,239 @@
+package gqlapi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+// Field names follow the JSON of the REST API. IDs are of type ID, sent as strings, as
+// GraphQL's Int cannot hold every int64.
+
+// hubPage and skuPage are pages of a listing, as in the meta block of the REST API.
+type hubPage struct {
+	Items      []*inventory.Hub `json:"items"`
+	NextCursor *string          `json:"next_cursor"`
+	Total      *int64           `json:"total"`
+}
+
+type skuPage struct {
+	Items      []*inventory.SKU `json:"items"`
+	NextCursor *string          `json:"next_cursor"`
+	Total      *int64           `json:"total"`
+}
+
+func nextCursor(meta *inventory.PageMeta) *string {
+	if meta == nil || meta.NextCursor == "" {
+		return nil
+	}
+	return &meta.NextCursor
+}
+
+var hubStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "HubStatus",
+	Values: graphql.EnumValueConfigMap{
+		string(inventory.HubStatusActive):         {Value: inventory.HubStatusActive},
+		string(inventory.HubStatusPaused):         {Value: inventory.HubStatusPaused},
+		string(inventory.HubStatusDecommissioned): {Value: inventory.HubStatusDecommissioned},
+	},
+})
+
+func (a *API) buildSchema() (graphql.Schema, error) {
+	var hubType, skuType, balanceType *graphql.Object
+
+	skuType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SKU",
+		Fields: graphql.Fields{
+			"id":               {Type: graphql.NewNonNull(graphql.ID)},
+			"seller_id":        {Type: graphql.NewNonNull(graphql.ID)},
+			"sku_code":         {Type: graphql.NewNonNull(graphql.String)},
+			"name":             {Type: graphql.NewNonNull(graphql.String)},
+			"length_cm":        {Type: graphql.NewNonNull(graphql.Float)},
+			"width_cm":         {Type: graphql.NewNonNull(graphql.Float)},
+			"height_cm":        {Type: graphql.NewNonNull(graphql.Float)},
+			"units_per_pallet": {Type: graphql.NewNonNull(graphql.Int)},
+			"version":          {Type: graphql.NewNonNull(graphql.Int)},
+			"created_at":       {Type: graphql.NewNonNull(graphql.DateTime)},
+			"updated_at":       {Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	balanceType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "InventoryBalance",
+		Description: "Stock of a SKU at a hub.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"hub_id":   {Type: graphql.NewNonNull(graphql.ID)},
+				"sku_id":   {Type: graphql.NewNonNull(graphql.ID)},
+				"quantity": {Type: graphql.NewNonNull(graphql.Int)},
+				"reserved": {Type: graphql.NewNonNull(graphql.Int)},
+				"version":  {Type: graphql.NewNonNull(graphql.Int), Description: "0 while the SKU was never stocked at the hub."},
+				"hub":      {Type: hubType, Resolve: a.balanceHub},
+				"sku": {Type: skuType, Resolve: a.balanceSKU,
+					Description: "Null when the SKU belongs to a seller the caller may not access."},
+			}
+		}),
+	})
+
+	hubType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Hub",
+		Fields: graphql.Fields{
+			"id":              {Type: graphql.NewNonNull(graphql.ID)},
+			"name":            {Type: graphql.NewNonNull(graphql.String)},
+			"address":         {Type: graphql.NewNonNull(graphql.String)},
+			"type":            {Type: graphql.NewNonNull(graphql.String)},
+			"status":          {Type: graphql.NewNonNull(hubStatusEnum)},
+			"timezone":        {Type: graphql.NewNonNull(graphql.String)},
+			"latitude":        {Type: graphql.Float},
+			"longitude":       {Type: graphql.Float},
+			"contact_name":    {Type: graphql.NewNonNull(graphql.String)},
+			"contact_phone":   {Type: graphql.NewNonNull(graphql.String)},
+			"contact_email":   {Type: graphql.NewNonNull(graphql.String)},
+			"capacity":        {Type: graphql.Float},
+			"capacity_unit":   {Type: graphql.NewNonNull(graphql.String)},
+			"capacity_policy": {Type: graphql.NewNonNull(graphql.String)},
+			"version":         {Type: graphql.NewNonNull(graphql.Int)},
+			"created_at":      {Type: graphql.NewNonNull(graphql.DateTime)},
+			"updated_at":      {Type: graphql.NewNonNull(graphql.DateTime)},
+			"balances": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(balanceType))),
+				Description: "Stock held at the hub, by SKU ID: the first limit rows, 50 by default and at most 200.",
+				Args: graphql.FieldConfigArgument{
+					"sku_ids": {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+					"min_qty": {Type: graphql.Int},
+					"max_qty": {Type: graphql.Int},
+					"limit":   {Type: graphql.Int},
+				},
+				Resolve: a.hubBalances,
+			},
+		},
+	})
+
+	pageFields := func(name string, item *graphql.Object) *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: name,
+			Fields: graphql.Fields{
+				"items":       {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item)))},
+				"next_cursor": {Type: graphql.String, Description: "Cursor of the next page; null on the last page."},
+				"total":       {Type: graphql.Int, Description: "Number of matches, on the first page only."},
+			},
+		})
+	}
+	pageArgs := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args["limit"] = &graphql.ArgumentConfig{Type: graphql.Int, Description: "50 by default, at most 200."}
+		args["cursor"] = &graphql.ArgumentConfig{Type: graphql.String}
+		args["sort"] = &graphql.ArgumentConfig{Type: graphql.String}
+		args["order"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "asc or desc."}
+		return args
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"hub": {
+				Type:    hubType,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: a.getHub,
+			},
+			"hubs": {
+				Type: graphql.NewNonNull(pageFields("HubPage", hubType)),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"statuses":    {Type: graphql.NewList(graphql.NewNonNull(hubStatusEnum))},
+					"name_prefix": {Type: graphql.String},
+					"hub_ids":     {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+				}),
+				Resolve: a.listHubs,
+			},
+			"sku": {
+				Type:    skuType,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: a.getSKU,
+			},
+			"skus": {
+				Type: graphql.NewNonNull(pageFields("SKUPage", skuType)),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"seller_id":   {Type: graphql.ID},
+					"sku_codes":   {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"name_prefix": {Type: graphql.String},
+				}),
+				Resolve: a.listSKUs,
+			},
+			"inventory": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(balanceType))),
+				Description: "Stock of SKUs at a hub, with SKUs never stocked there at 0.",
+				Args: graphql.FieldConfigArgument{
+					"hub_id":  {Type: graphql.NewNonNull(graphql.ID)},
+					"sku_ids": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: a.viewInventory,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// Argument helpers. The schema has already checked argument types.
+
+func idArg(args map[string]interface{}, name string) (int64, error) {
+	s, _ := args[name].(string)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, newError(oerror.RequestInvalid, fmt.Sprintf("invalid %s", name))
+	}
+	return id, nil
+}
+
+func optionalIDArg(args map[string]interface{}, name string) (*int64, error) {
+	if args[name] == nil {
+		return nil, nil
+	}
+	id, err := idArg(args, name)
+	return &id, err
+}
+
+func idsArg(args map[string]interface{}, name string) ([]int64, error) {
+	values, _ := args[name].([]interface{})
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		id, err := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+		if err != nil {
+			return nil, newError(oerror.RequestInvalid, fmt.Sprintf("invalid %s", name))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func intArg(args map[string]interface{}, name string) *int64 {
+	v, ok := args[name].(int)
+	if !ok {
+		return nil
+	}
+	n := int64(v)
+	return &n
+}
+
+func stringsArg(args map[string]interface{}, name string) []string {
+	values, _ := args[name].([]interface{})
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, fmt.Sprint(v))
+	}
+	return out
+}
+
+func pageArg(args map[string]interface{}) inventory.Page {
+	page := inventory.Page{}
+	if limit, ok := args["limit"].(int); ok {
+		page.Limit = limit
+	}
+	page.Cursor, _ = args["cursor"].(string)
+	page.SortBy, _ = args["sort"].(string)
+	order, _ := args["order"].(string)
+	page.Desc = order == "desc"
+	return page
+}
@@ -0,0 +1,167 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/go_commons/env"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/jwt/private"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/http"
+	"github.com/omniful/ims_rohit/internal/audit"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+	"github.com/omniful/ims_rohit/pkg/response"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authenticator runs the middlewares the HTTP API authenticates requests with over the
+// metadata of a call, so RPCs see the same request ID, user details and audit metadata in
+// their context as HTTP handlers do.
+type authenticator struct {
+	engine *gin.Engine
+}
+
+// authResult receives the context of an authenticated call from the engine.
+type authResult struct {
+	ctx *gin.Context
+}
+
+type authResultKey struct{}
+
+func newAuthenticator() *authenticator {
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	engine.Use(env.RequestID(), http.LoggerContextMiddleware(), private.AuthenticateJWT(), audit.Middleware())
+	engine.Any("/*method", func(c *gin.Context) {
+		result := c.Request.Context().Value(authResultKey{}).(*authResult)
+		// The engine reuses c once this handler returns; the copy outlives it.
+		result.ctx = c.Copy()
+	})
+	return &authenticator{engine: engine}
+}
+
+// authenticate returns the context RPCs of the call run with, a *gin.Context that falls
+// back to ctx for cancellation and deadlines.
+func (a *authenticator) authenticate(ctx context.Context, fullMethod string) (*gin.Context, error) {
+	result := &authResult{}
+	req, err := nethttp.NewRequestWithContext(context.WithValue(ctx, authResultKey{}, result), nethttp.MethodPost, fullMethod, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		// Pseudo-headers such as :authority are not HTTP headers.
+		if strings.HasPrefix(key, ":") {
+			continue
+		}
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	w := &responseRecorder{header: nethttp.Header{}, status: nethttp.StatusOK}
+	a.engine.ServeHTTP(w, req)
+	if result.ctx == nil {
+		return nil, w.err()
+	}
+	return result.ctx, nil
+}
+
+// responseRecorder keeps the error response of a middleware that rejected the call.
+type responseRecorder struct {
+	header nethttp.Header
+	status int
+	body   []byte
+}
+
+func (w *responseRecorder) Header() nethttp.Header {
+	return w.header
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body = append(w.body, b...)
+	return len(b), nil
+}
+
+func (w *responseRecorder) WriteHeader(statusCode int) {
+	w.status = statusCode
+}
+
+// err turns the recorded error response into a gRPC status.
+func (w *responseRecorder) err() error {
+	var res response.ErrorResponse
+	message := nethttp.StatusText(w.status)
+	if json.Unmarshal(w.body, &res) == nil && res.Error.Message != "" {
+		message = res.Error.Message
+	}
+	code := codes.Unknown
+	switch w.status {
+	case nethttp.StatusBadRequest:
+		code = pkgerror.GRPCCode(oerror.RequestInvalid)
+	case nethttp.StatusUnauthorized:
+		code = pkgerror.GRPCCode(pkgerror.Unauthenticated)
+	case nethttp.StatusForbidden:
+		code = pkgerror.GRPCCode(pkgerror.Forbidden)
+	case nethttp.StatusInternalServerError:
+		code = pkgerror.GRPCCode(pkgerror.InternalError)
+	}
+	return status.Error(code, message)
+}
+
+// unaryInterceptor authenticates unary calls and turns panics into internal errors.
+func (a *authenticator) unaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (res interface{}, err error) {
+	defer recoverCall(info.FullMethod, &err)
+	c, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(c, req)
+}
+
+// streamInterceptor authenticates a stream once, for all of its messages.
+func (a *authenticator) streamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	defer recoverCall(info.FullMethod, &err)
+	c, err := a.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: c})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func recoverCall(method string, err *error) {
+	if r := recover(); r != nil {
+		log.Errorf("panic in %s: %v\n%s", method, r, debug.Stack())
+		*err = status.Error(codes.Internal, "internal server error")
+	}
+}
@@ -0,0 +1,116 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/omniful/ims_rohit/inventory"
+	imsv1 "github.com/omniful/ims_rohit/proto/ims/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Conversions between the inventory types and the messages of ims.proto.
+
+func page(r *imsv1.PageRequest) inventory.Page {
+	return inventory.Page{
+		Limit:  int(r.GetLimit()),
+		Cursor: r.GetCursor(),
+		SortBy: r.GetSort(),
+		Desc:   r.GetOrder() == "desc",
+	}
+}
+
+func pageMeta(m *inventory.PageMeta) *imsv1.PageMeta {
+	if m == nil {
+		return nil
+	}
+	return &imsv1.PageMeta{
+		Limit:      int32(m.Limit),
+		Sort:       m.SortBy,
+		Order:      m.Order,
+		NextCursor: m.NextCursor,
+		Total:      m.Total,
+	}
+}
+
+func hubStatuses(statuses []string) []inventory.HubStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+	converted := make([]inventory.HubStatus, len(statuses))
+	for i, s := range statuses {
+		converted[i] = inventory.HubStatus(s)
+	}
+	return converted
+}
+
+func hubMessage(h *inventory.Hub) *imsv1.Hub {
+	hours := make([]*imsv1.OperatingWindow, len(h.OperatingHours))
+	for i, w := range h.OperatingHours {
+		hours[i] = &imsv1.OperatingWindow{Day: w.Day, Open: w.Open, Close: w.Close}
+	}
+	area := make([]*imsv1.GeoPoint, len(h.ServiceArea))
+	for i, p := range h.ServiceArea {
+		area[i] = &imsv1.GeoPoint{Latitude: p.Latitude, Longitude: p.Longitude}
+	}
+	return &imsv1.Hub{
+		Id:             h.ID,
+		TenantId:       h.TenantID,
+		Name:           h.Name,
+		Address:        h.Address,
+		Type:           string(h.Type),
+		Status:         string(h.Status),
+		Timezone:       h.Timezone,
+		OperatingHours: hours,
+		Latitude:       h.Latitude,
+		Longitude:      h.Longitude,
+		ServiceArea:    area,
+		ContactName:    h.ContactName,
+		ContactPhone:   h.ContactPhone,
+		ContactEmail:   h.ContactEmail,
+		Capacity:       h.Capacity,
+		CapacityUnit:   string(h.CapacityUnit),
+		CapacityPolicy: string(h.CapacityPolicy),
+		Version:        h.Version,
+		CreatedAt:      timestamp(h.CreatedAt),
+		UpdatedAt:      timestamp(h.UpdatedAt),
+	}
+}
+
+func skuMessage(s *inventory.SKU) *imsv1.SKU {
+	return &imsv1.SKU{
+		Id:             s.ID,
+		TenantId:       s.TenantID,
+		SellerId:       s.SellerID,
+		SkuCode:        s.SKUCode,
+		Name:           s.Name,
+		LengthCm:       s.LengthCm,
+		WidthCm:        s.WidthCm,
+		HeightCm:       s.HeightCm,
+		UnitsPerPallet: s.UnitsPerPallet,
+		Version:        s.Version,
+		CreatedAt:      timestamp(s.CreatedAt),
+		UpdatedAt:      timestamp(s.UpdatedAt),
+	}
+}
+
+func stockMessages(invs []*inventory.Inventory) []*imsv1.Stock {
+	stock := make([]*imsv1.Stock, len(invs))
+	for i, inv := range invs {
+		stock[i] = &imsv1.Stock{
+			HubId:    inv.HubID,
+			SkuId:    inv.SKUID,
+			Quantity: inv.Qty,
+			Reserved: inv.Reserved,
+			Version:  inv.Version,
+		}
+	}
+	return stock
+}
+
+// timestamp leaves unset times, such as those of a row that was never stored, unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
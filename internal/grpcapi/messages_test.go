@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omniful/ims_rohit/inventory"
+	imsv1 "github.com/omniful/ims_rohit/proto/ims/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHubMessageSurvivesTheWire(t *testing.T) {
+	lat, capacity := 24.7, 500.0
+	created := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	hub := &inventory.Hub{
+		ID: 3, TenantID: 1, Name: "Riyadh DC", Status: inventory.HubStatusActive, Latitude: &lat,
+		OperatingHours: inventory.OperatingHours{{Day: "monday", Open: "08:00", Close: "20:00"}},
+		ServiceArea:    inventory.GeoPolygon{{Latitude: 24, Longitude: 46}, {Latitude: 25, Longitude: 46}},
+		Capacity:       &capacity, CapacityPolicy: inventory.CapacityPolicyWarn, Version: 4, CreatedAt: created,
+	}
+
+	data, err := proto.Marshal(hubMessage(hub))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got imsv1.Hub
+	if err := proto.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Id != 3 || got.Status != "active" || got.GetLatitude() != lat || got.Longitude != nil ||
+		got.GetCapacity() != capacity || got.CapacityPolicy != "warn" || got.Version != 4 {
+		t.Fatalf("got %v, want the hub's fields", &got)
+	}
+	if len(got.OperatingHours) != 1 || got.OperatingHours[0].Close != "20:00" || len(got.ServiceArea) != 2 {
+		t.Fatalf("got hours %v and area %v", got.OperatingHours, got.ServiceArea)
+	}
+	if !got.CreatedAt.AsTime().Equal(created) || got.UpdatedAt != nil {
+		t.Fatalf("got created %v, updated %v, want the creation time and no update time", got.CreatedAt, got.UpdatedAt)
+	}
+}
+
+func TestPageConversions(t *testing.T) {
+	p := page(&imsv1.PageRequest{Limit: 20, Cursor: "c", Sort: "name", Order: "desc"})
+	if p != (inventory.Page{Limit: 20, Cursor: "c", SortBy: "name", Desc: true}) {
+		t.Errorf("page: got %+v", p)
+	}
+	if p := page(nil); p != (inventory.Page{}) {
+		t.Errorf("page without a request: got %+v, want the defaults", p)
+	}
+
+	total := int64(41)
+	meta := pageMeta(&inventory.PageMeta{Limit: 20, SortBy: "name", Order: "asc", NextCursor: "n", Total: &total})
+	if meta.Limit != 20 || meta.Sort != "name" || meta.NextCursor != "n" || meta.GetTotal() != 41 {
+		t.Errorf("pageMeta: got %v", meta)
+	}
+	if pageMeta(nil) != nil {
+		t.Error("pageMeta of no meta is not nil")
+	}
+}
+
+func TestServiceRegistersTheProtoServices(t *testing.T) {
+	server := grpc.NewServer()
+	(&service{}).register(server)
+
+	info := server.GetServiceInfo()
+	for name, methods := range map[string]int{"ims.v1.Hubs": 2, "ims.v1.SKUs": 3, "ims.v1.Inventory": 4} {
+		if got := len(info[name].Methods); got != methods {
+			t.Errorf("%s: got %d methods, want %d", name, got, methods)
+		}
+	}
+}
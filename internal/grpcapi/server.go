@@ -0,0 +1,84 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/omniful/api-gateway/pkg/redis"
+	"github.com/omniful/api-gateway/pkg/serializer"
+	"github.com/omniful/go_commons/config"
+	"github.com/omniful/go_commons/log"
+	pkgcache "github.com/omniful/go_commons/redis_cache"
+	"github.com/omniful/go_commons/shutdown"
+	"github.com/omniful/ims_rohit/internal/access_control"
+	"github.com/omniful/ims_rohit/internal/hub"
+	"github.com/omniful/ims_rohit/internal/seller"
+	"github.com/omniful/ims_rohit/inventory"
+	"google.golang.org/grpc"
+)
+
+// Server serves the services of proto/ims/v1/ims.proto over gRPC on grpc.port. Calls are
+// authenticated like API requests, from the authorization and other headers in their
+// metadata.
+type Server struct {
+	*grpc.Server
+	addr string
+}
+
+func NewServer(ctx context.Context) *Server {
+	redisClient := pkgcache.NewRedisCacheClient(redis.GetClient().Client, serializer.NewMsgpackSerializer(), config.GetString(ctx, "service.name"))
+	hubCache, err := hub.NewCache(ctx, redisClient)
+	if err != nil {
+		panic(err)
+	}
+	sellerCache, err := seller.NewCache(ctx, redisClient)
+	if err != nil {
+		panic(err)
+	}
+
+	auth := newAuthenticator()
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	)
+	repo := inventory.NewPostgresRepository()
+	svc := &service{
+		hubs:   repo,
+		skus:   repo,
+		stock:  repo,
+		access: access_control.NewAccessControl(hubCache, sellerCache),
+	}
+	svc.register(server)
+
+	return &Server{Server: server, addr: ":" + config.GetString(ctx, "grpc.port")}
+}
+
+// StartServer serves until the server is closed.
+func (s *Server) StartServer(serviceName string) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	shutdown.RegisterShutdownCallback(serviceName, s)
+
+	log.Infof("Starting gRPC server on %s", s.addr)
+	return s.Serve(lis)
+}
+
+// Close lets running calls finish, for up to 25 seconds, before stopping the server.
+func (s *Server) Close() error {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(25 * time.Second):
+		log.Errorf("gRPC server did not stop in time, closing open calls")
+		s.Stop()
+	}
+	return nil
+}
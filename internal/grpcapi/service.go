@@ -0,0 +1,335 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/jwt/public"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/internal/access_control"
+	"github.com/omniful/ims_rohit/internal/apierror"
+	"github.com/omniful/ims_rohit/internal/permission"
+	"github.com/omniful/ims_rohit/inventory"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+	imsv1 "github.com/omniful/ims_rohit/proto/ims/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// service implements the RPCs of the Hubs, SKUs and Inventory services of ims.proto over
+// the same repositories and access checks as the HTTP handlers.
+type service struct {
+	imsv1.UnimplementedHubsServer
+	imsv1.UnimplementedSKUsServer
+	imsv1.UnimplementedInventoryServer
+
+	hubs   inventory.HubRepository
+	skus   inventory.SKURepository
+	stock  inventory.InventoryRepository
+	access *access_control.AccessControl
+}
+
+func (s *service) register(server *grpc.Server) {
+	imsv1.RegisterHubsServer(server, s)
+	imsv1.RegisterSKUsServer(server, s)
+	imsv1.RegisterInventoryServer(server, s)
+}
+
+func (s *service) GetHub(ctx context.Context, req *imsv1.GetHubRequest) (*imsv1.Hub, error) {
+	hub, err := s.hubs.GetHub(ctx, req.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if hub == nil {
+		return nil, newStatus(pkgerror.NotFound, "hub not found")
+	}
+	if err := s.authorize(ctx, hub.GetHubIDs(), nil); err != nil {
+		return nil, err
+	}
+	return hubMessage(hub), nil
+}
+
+func (s *service) ListHubs(ctx context.Context, req *imsv1.ListHubsRequest) (*imsv1.ListHubsResponse, error) {
+	ctx, err := s.scope(ctx, s.access.ValidateAndSetHubIDs, req.HubIds)
+	if err != nil {
+		return nil, err
+	}
+	filter := inventory.HubFilter{Statuses: hubStatuses(req.Statuses), NamePrefix: req.NamePrefix}
+	hubs, meta, err := s.hubs.ListHubs(ctx, filter, page(req.Page))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	res := &imsv1.ListHubsResponse{Hubs: make([]*imsv1.Hub, len(hubs)), Meta: pageMeta(meta)}
+	for i, hub := range hubs {
+		res.Hubs[i] = hubMessage(hub)
+	}
+	return res, nil
+}
+
+func (s *service) GetSKU(ctx context.Context, req *imsv1.GetSKURequest) (*imsv1.SKU, error) {
+	sku, err := s.skus.GetSKU(ctx, req.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if sku == nil {
+		return nil, newStatus(pkgerror.NotFound, "sku not found")
+	}
+	if err := s.authorize(ctx, nil, sku.GetSellerIDs()); err != nil {
+		return nil, err
+	}
+	return skuMessage(sku), nil
+}
+
+func (s *service) ListSKUs(ctx context.Context, req *imsv1.ListSKUsRequest) (*imsv1.ListSKUsResponse, error) {
+	var sellerIDs []int64
+	if req.SellerId != nil {
+		sellerIDs = []int64{*req.SellerId}
+	}
+	ctx, err := s.scope(ctx, s.access.ValidateAndSetSellerIDs, sellerIDs)
+	if err != nil {
+		return nil, err
+	}
+	filter := inventory.SKUFilter{SellerID: req.SellerId, SKUCodes: req.SkuCodes, NamePrefix: req.NamePrefix}
+	skus, meta, err := s.skus.ListSKUs(ctx, filter, page(req.Page))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	res := &imsv1.ListSKUsResponse{Skus: make([]*imsv1.SKU, len(skus)), Meta: pageMeta(meta)}
+	for i, sku := range skus {
+		res.Skus[i] = skuMessage(sku)
+	}
+	return res, nil
+}
+
+func (s *service) CheckSKUsExistence(ctx context.Context, req *imsv1.CheckSKUsExistenceRequest) (*imsv1.CheckSKUsExistenceResponse, error) {
+	if len(req.SkuIds) == 0 {
+		return nil, newStatus(oerror.RequestInvalid, "sku_ids is required")
+	}
+	// SKUs of sellers outside the caller's scope count as missing.
+	ctx, err := s.scope(ctx, s.access.ValidateAndSetSellerIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	existence, invalid, err := s.skus.CheckSKUsExistence(ctx, req.SkuIds)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &imsv1.CheckSKUsExistenceResponse{Existence: existence, Invalid: invalid}, nil
+}
+
+func (s *service) ViewInventory(ctx context.Context, req *imsv1.ViewInventoryRequest) (*imsv1.ViewInventoryResponse, error) {
+	return s.view(ctx, req)
+}
+
+func (s *service) view(ctx context.Context, req *imsv1.ViewInventoryRequest) (*imsv1.ViewInventoryResponse, error) {
+	if req.HubId == 0 || len(req.SkuIds) == 0 {
+		return nil, newStatus(oerror.RequestInvalid, "hub_id and sku_ids are required")
+	}
+	if err := s.authorize(ctx, []string{strconv.FormatInt(req.HubId, 10)}, nil); err != nil {
+		return nil, err
+	}
+	ctx, err := s.scope(ctx, s.access.ValidateAndSetSellerIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	invs, err := s.stock.ViewInventory(ctx, req.HubId, req.SkuIds)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &imsv1.ViewInventoryResponse{HubId: req.HubId, Inventory: stockMessages(invs)}, nil
+}
+
+// ViewInventoryBatch answers each ViewInventoryRequest of the stream in order. A request
+// that fails gets a response carrying the error; the stream goes on.
+func (s *service) ViewInventoryBatch(stream imsv1.Inventory_ViewInventoryBatchServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		res, err := s.view(ctx, req)
+		if err != nil {
+			res = &imsv1.ViewInventoryResponse{HubId: req.HubId, Error: itemError(err)}
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *service) AdjustOne(ctx context.Context, req *imsv1.Adjustment) (*imsv1.AdjustmentResult, error) {
+	return s.adjust(ctx, req)
+}
+
+// adjust applies an adjustment with the checks of the HTTP upsert and set routes: the role
+// must allow the kind of change, and the caller must have access to the hub and the SKU's
+// seller.
+func (s *service) adjust(ctx context.Context, req *imsv1.Adjustment) (*imsv1.AdjustmentResult, error) {
+	if req.HubId == 0 || req.SkuId == 0 {
+		return nil, newStatus(oerror.RequestInvalid, "hub_id and sku_id are required")
+	}
+	var actions []permission.Action
+	switch {
+	case req.Set && req.Qty < 0:
+		return nil, newStatus(oerror.RequestInvalid, "qty must be at least 0")
+	case req.Set:
+		actions = []permission.Action{permission.InventorySet}
+	case req.Qty > 0:
+		actions = []permission.Action{permission.InventoryAdjust}
+	default:
+		actions = []permission.Action{permission.InventoryDecrement, permission.InventoryAdjust}
+	}
+	if err := allow(ctx, actions...); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, []string{strconv.FormatInt(req.HubId, 10)}, nil); err != nil {
+		return nil, err
+	}
+	ctx, err := s.scope(ctx, s.access.ValidateAndSetSellerIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *inventory.UpsertResult
+	if req.Set {
+		result, err = s.stock.SetInventory(ctx, req.HubId, req.SkuId, req.Qty, req.ExpectedVersion)
+	} else {
+		result, err = s.stock.UpsertInventory(ctx, req.HubId, req.SkuId, req.Qty, req.ExpectedVersion)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &imsv1.AdjustmentResult{HubId: req.HubId, SkuId: req.SkuId, Version: result.Version, Warnings: result.Warnings}, nil
+}
+
+// AdjustInventory applies each Adjustment of the stream in order, each in its own
+// transaction, and answers with its result. A failed adjustment does not end the stream.
+func (s *service) AdjustInventory(stream imsv1.Inventory_AdjustInventoryServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		res, err := s.adjust(ctx, req)
+		if err != nil {
+			res = &imsv1.AdjustmentResult{HubId: req.HubId, SkuId: req.SkuId, Error: itemError(err)}
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// authorize checks the caller may access the hubs and sellers, like the response checks
+// of the HTTP API.
+func (s *service) authorize(ctx context.Context, hubIDs, sellerIDs []string) error {
+	tenantID, err := public.GetTenantID(ctx)
+	if err != nil {
+		return newStatus(pkgerror.Unauthenticated, err.Error())
+	}
+	validHubs, err := s.access.ValidateHubIDs(ctx, tenantID, hubIDs)
+	if err != nil {
+		log.WithError(err).Error("access control validation failed")
+		return newStatus(pkgerror.InternalError, "internal server error")
+	}
+	validSellers, err := s.access.ValidateSellerIDs(ctx, tenantID, sellerIDs)
+	if err != nil {
+		log.WithError(err).Error("access control validation failed")
+		return newStatus(pkgerror.InternalError, "internal server error")
+	}
+	if !validHubs || !validSellers {
+		return newStatus(pkgerror.Forbidden, "you don't have access to this resource")
+	}
+	return nil
+}
+
+// scope checks the caller may access ids with validateAndSet, one of the access control's
+// ValidateAndSet methods, and returns a context limiting listings to the permitted ones.
+// Without ids, the context holds the caller's whole scope.
+func (s *service) scope(
+	ctx context.Context,
+	validateAndSet func(c *gin.Context, tenantID string, ids []string) (bool, error),
+	ids []int64,
+) (context.Context, error) {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return nil, newStatus(pkgerror.Unauthenticated, "call is not authenticated")
+	}
+	// Keys set for this listing stay with it.
+	c = c.Copy()
+	tenantID, err := public.GetTenantID(c)
+	if err != nil {
+		return nil, newStatus(pkgerror.Unauthenticated, err.Error())
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	valid, err := validateAndSet(c, tenantID, values)
+	if err != nil {
+		log.WithError(err).Error("access control validation failed")
+		return nil, newStatus(pkgerror.InternalError, "internal server error")
+	}
+	if !valid {
+		return nil, newStatus(pkgerror.Forbidden, "you don't have access to this resource")
+	}
+	return c, nil
+}
+
+// allow fails unless the caller's role grants one of the actions, like permission.Require.
+func allow(ctx context.Context, actions ...permission.Action) error {
+	allowed, err := permission.Allowed(ctx, actions...)
+	if err != nil {
+		log.WithError(err).Error("permission check failed")
+	}
+	if err != nil || !allowed {
+		return newStatus(pkgerror.Forbidden, "you don't have permission to perform this action")
+	}
+	return nil
+}
+
+// callError fails a call with an error code of the API. gRPC sends it as the status
+// GRPCStatus returns; streams report the code itself for failed items.
+type callError struct {
+	code    oerror.Code
+	message string
+}
+
+func newStatus(code oerror.Code, message string) error {
+	return &callError{code: code, message: message}
+}
+
+func (e *callError) Error() string {
+	return e.message
+}
+
+func (e *callError) GRPCStatus() *status.Status {
+	return status.New(pkgerror.GRPCCode(e.code), e.message)
+}
+
+// statusError is the gRPC status of an error of the inventory package.
+func statusError(err error) error {
+	cusErr := apierror.New(err)
+	return newStatus(cusErr.ErrorCode(), cusErr.ErrorMessage())
+}
+
+// itemError describes the failure of one item of a stream.
+func itemError(err error) *imsv1.Error {
+	var callErr *callError
+	if errors.As(err, &callErr) {
+		return &imsv1.Error{Code: string(callErr.code), Message: callErr.message}
+	}
+	return &imsv1.Error{Code: string(pkgerror.InternalError), Message: "internal server error"}
+}
@@ -6,12 +6,13 @@ import (
 	"github.com/omniful/api-gateway/constants"
 	"github.com/omniful/api-gateway/external_service/wms/private"
 	cache2 "github.com/omniful/go_commons/redis_cache"
+	"github.com/omniful/ims_rohit/internal/tenantcache"
 	"sync"
 )
 
 type Cache struct {
-	cache     cache2.ICache
-	wmsClient private.Client
+	tenantHubs *tenantcache.Cache
+	wmsClient  private.Client
 }
 
 var cache *Cache
@@ -26,25 +27,21 @@ func NewCache(ctx context.Context, c cache2.ICache) (*Cache, error) {
 		}
 
 		cache = &Cache{
-			cache:     c,
 			wmsClient: wmsClient,
 		}
+		cache.tenantHubs = tenantcache.New(ctx, tenantHubsCacheKeyPrefix, c, cache.fetchTenantHubIDs, constants.OneDayExpiration)
 	})
 
 	return cache, cacheErr
 }
 
+// GetTenantHubIDs returns the tenant's hub IDs from the cache, fetching them from WMS at
+// most once at a time per tenant. See tenantcache for refresh and stale-serving rules.
 func (c *Cache) GetTenantHubIDs(ctx context.Context, tenantID string) (hubIDs []string, err error) {
-	cacheKey := getTenantHubsCacheKey(ctx, tenantID)
-	found, err := c.cache.Get(ctx, cacheKey, &hubIDs)
-	if err != nil {
-		return
-	}
-
-	if found {
-		return
-	}
+	return c.tenantHubs.Get(ctx, tenantID)
+}
 
+func (c *Cache) fetchTenantHubIDs(ctx context.Context, tenantID string) (hubIDs []string, err error) {
 	tenantHubs, interSvcErr := c.wmsClient.GetTenantHubs(ctx, tenantID)
 	if interSvcErr != nil {
 		err = errors.New(interSvcErr.Message)
@@ -54,32 +51,13 @@ func (c *Cache) GetTenantHubIDs(ctx context.Context, tenantID string) (hubIDs []
 	for _, hub := range tenantHubs.HubIDs {
 		hubIDs = append(hubIDs, hub)
 	}
-
-	err = c.setTenantHubIDs(ctx, tenantID, hubIDs)
-	if err != nil {
-		return
-	}
-	return
-}
-
-func (c *Cache) setTenantHubIDs(ctx context.Context, tenantID string, sellers []string) (err error) {
-	cacheKey := getTenantHubsCacheKey(ctx, tenantID)
-	_, err = c.cache.Set(ctx, cacheKey, sellers, constants.OneDayExpiration)
-	if err != nil {
-		return
-	}
 	return
 }
 
 func (c *Cache) InvalidateTenantHubIDs(ctx context.Context, tenantID string) (err error) {
-	cacheKey := getTenantHubsCacheKey(ctx, tenantID)
-	_, err = c.cache.Unlink(ctx, []string{cacheKey})
-	if err != nil {
-		return
-	}
-	return
+	return c.tenantHubs.Invalidate(ctx, tenantID)
 }
 
-func getTenantHubsCacheKey(ctx context.Context, tenantID string) string {
-	return "tenant_hubs_" + tenantID
-}
+// tenantHubsCacheKeyPrefix is versioned since entries switched from a bare ID list to
+// tenantcache entries.
+const tenantHubsCacheKeyPrefix = "tenant_hubs_v2_"
@@ -0,0 +1,279 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/jwt/public"
+	"github.com/omniful/go_commons/log"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+	"github.com/omniful/ims_rohit/pkg/pg"
+	"github.com/omniful/ims_rohit/pkg/response"
+)
+
+const (
+	// HeaderKey is the request header carrying the client's idempotency key.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on responses replayed from a stored key.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// redactedKey is the gin context key of the values Redact keeps out of storage.
+	redactedKey = "idempotency_redacted"
+)
+
+// stored is a key's row: the hash of the request that claimed it and, once that request
+// finished, its response.
+type stored struct {
+	requestHash string
+	status      sql.NullInt64
+	contentType sql.NullString
+	body        []byte
+}
+
+// recorder keeps a copy of the response body so it can be stored with the key.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware makes POST, PUT and DELETE requests carrying an Idempotency-Key header safe
+// to retry. The first request with a key runs and its response is stored for ttl; a retry
+// with the same key and the same method, path and body gets the stored response back
+// without running the handler again. Reusing a key for a different request is rejected
+// with 422, and a retry arriving while the first request is still running with 409.
+//
+// Responses with a 5xx status are not stored, so the retry runs the request again. A key
+// whose request never finished, for example because the pod died, can be claimed again
+// after lockTimeout. Keys are scoped to the caller's tenant and user, so a stored response
+// is only replayed to the user whose request produced it, and the middleware must run
+// after the JWT middleware.
+func Middleware(ttl, lockTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			abort(c, oerror.RequestInvalid, HeaderKey+" must be at most 255 characters")
+			return
+		}
+		owner, err := callerOf(c)
+		if err != nil {
+			// Left to the handler, which rejects requests without a tenant.
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, oerror.RequestInvalid, "failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash := requestHash(c.Request, body)
+
+		claimed, err := claim(c, owner, key, hash, ttl, lockTimeout)
+		if err != nil {
+			log.WithError(err).Error("failed to claim idempotency key")
+			abort(c, pkgerror.InternalError, "failed to check idempotency key")
+			return
+		}
+		if !claimed {
+			replay(c, owner, key, hash)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		// The outcome is saved even if the client went away, so that its retry replays it.
+		saveCtx := context.WithoutCancel(c)
+		defer func() {
+			if p := recover(); p != nil {
+				release(saveCtx, owner, key)
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		if rec.Status() >= http.StatusInternalServerError {
+			release(saveCtx, owner, key)
+			return
+		}
+		if err := complete(saveCtx, owner, key, rec.Status(), rec.Header().Get("Content-Type"), redact(c, rec.body.Bytes())); err != nil {
+			log.WithError(err).Error("failed to store idempotent response")
+		}
+	}
+}
+
+// Redact keeps the string values out of the response stored for the request, for values
+// the API shows only once, such as secrets. A replay answers with the response the
+// request got, with the values set to "".
+func Redact(c *gin.Context, values ...string) {
+	redacted := c.GetStringSlice(redactedKey)
+	c.Set(redactedKey, append(redacted, values...))
+}
+
+// redact blanks the values passed to Redact wherever body holds them as JSON strings.
+func redact(c *gin.Context, body []byte) []byte {
+	for _, value := range c.GetStringSlice(redactedKey) {
+		if value == "" {
+			continue
+		}
+		// Encoded both with and without HTML escaping, as encoders differ.
+		for _, escapeHTML := range []bool{true, false} {
+			var quoted bytes.Buffer
+			enc := json.NewEncoder(&quoted)
+			enc.SetEscapeHTML(escapeHTML)
+			if err := enc.Encode(value); err != nil {
+				continue
+			}
+			body = bytes.ReplaceAll(body, bytes.TrimSuffix(quoted.Bytes(), []byte("\n")), []byte(`""`))
+		}
+	}
+	return body
+}
+
+// abort answers with the error envelope the handlers use.
+func abort(c *gin.Context, code oerror.Code, message string) {
+	response.NewErrorResponse(c, oerror.NewCustomError(code, message), nil)
+}
+
+func isMutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodDelete
+}
+
+// caller is who a key belongs to. Callers without a user, such as service tokens, share
+// the tenant's keys under an empty user ID.
+type caller struct {
+	tenantID int64
+	userID   string
+}
+
+func callerOf(c *gin.Context) (caller, error) {
+	tenantID, err := public.GetTenantID(c)
+	if err != nil {
+		return caller{}, err
+	}
+	id, err := strconv.ParseInt(tenantID, 10, 64)
+	if err != nil {
+		return caller{}, err
+	}
+	userID, _ := public.GetUserID(c)
+	return caller{tenantID: id, userID: userID}, nil
+}
+
+// requestHash identifies a request by its method, path, query and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// claim records the key as in progress for this request. It returns false when the key is
+// already held by an unexpired request, which may have finished or still be running.
+func claim(ctx context.Context, owner caller, key, hash string, ttl, lockTimeout time.Duration) (bool, error) {
+	db := pg.GetClient().DB
+	var claimed bool
+	err := db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (tenant_id, user_id, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 millisecond')
+		ON CONFLICT (tenant_id, user_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response_status = NULL, response_content_type = NULL,
+			response_body = NULL, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+			OR (idempotency_keys.response_status IS NULL
+				AND idempotency_keys.created_at <= NOW() - $6 * INTERVAL '1 millisecond')
+		RETURNING true`,
+		owner.tenantID, owner.userID, key, hash, ttl.Milliseconds(), lockTimeout.Milliseconds()).Scan(&claimed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return claimed, err
+}
+
+// replay answers a request whose key is already held with the stored response.
+func replay(c *gin.Context, owner caller, key, hash string) {
+	db := pg.GetClient().DB
+	var s stored
+	err := db.QueryRowContext(c, `
+		SELECT request_hash, response_status, response_content_type, response_body
+		FROM idempotency_keys WHERE tenant_id = $1 AND user_id = $2 AND key = $3`, owner.tenantID, owner.userID, key).
+		Scan(&s.requestHash, &s.status, &s.contentType, &s.body)
+	switch {
+	case err == sql.ErrNoRows:
+		// Released between the claim and now; the client can retry straight away.
+		abort(c, pkgerror.Conflict, "a request with this "+HeaderKey+" is in progress")
+	case err != nil:
+		log.WithError(err).Error("failed to load idempotency key")
+		abort(c, pkgerror.InternalError, "failed to check idempotency key")
+	case s.requestHash != hash:
+		abort(c, pkgerror.ValidationFailed, HeaderKey+" was already used for a different request")
+	case !s.status.Valid:
+		abort(c, pkgerror.Conflict, "a request with this "+HeaderKey+" is in progress")
+	default:
+		c.Header(HeaderReplayed, "true")
+		c.Data(int(s.status.Int64), s.contentType.String, s.body)
+		c.Abort()
+	}
+}
+
+// complete stores the response of the request holding the key.
+func complete(ctx context.Context, owner caller, key string, status int, contentType string, body []byte) error {
+	db := pg.GetClient().DB
+	_, err := db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET response_status = $1, response_content_type = $2, response_body = $3
+		WHERE tenant_id = $4 AND user_id = $5 AND key = $6`, status, contentType, body, owner.tenantID, owner.userID, key)
+	return err
+}
+
+// release drops the key of a request that failed, so a retry runs it again.
+func release(ctx context.Context, owner caller, key string) {
+	db := pg.GetClient().DB
+	_, err := db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE tenant_id = $1 AND user_id = $2 AND key = $3`,
+		owner.tenantID, owner.userID, key)
+	if err != nil {
+		log.WithError(err).Error("failed to release idempotency key")
+	}
+}
+
+// RunPurge deletes expired keys every interval until ctx is done. Expired keys are
+// reclaimed on reuse anyway; this only keeps the table from growing.
+func RunPurge(ctx context.Context, interval time.Duration) {
+	for {
+		db := pg.GetClient().DB
+		if _, err := db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`); err != nil {
+			log.WithError(err).Error("idempotency key purge failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestHash(t *testing.T) {
+	hash := func(method, target, body string) string {
+		return requestHash(httptest.NewRequest(method, target, nil), []byte(body))
+	}
+	base := hash(http.MethodPost, "/api/v1/hubs/?x=1", `{"name":"A"}`)
+	if base != hash(http.MethodPost, "/api/v1/hubs/?x=1", `{"name":"A"}`) {
+		t.Fatal("the same request hashes differently")
+	}
+	for name, other := range map[string]string{
+		"method": hash(http.MethodPut, "/api/v1/hubs/?x=1", `{"name":"A"}`),
+		"path":   hash(http.MethodPost, "/api/v1/skus/?x=1", `{"name":"A"}`),
+		"query":  hash(http.MethodPost, "/api/v1/hubs/?x=2", `{"name":"A"}`),
+		"body":   hash(http.MethodPost, "/api/v1/hubs/?x=1", `{"name":"B"}`),
+	} {
+		if other == base {
+			t.Errorf("requests differing in %s hash the same", name)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	Redact(c, "whsec_<a&b>", "")
+
+	body := []byte(`{"secret":"whsec_<a&b>","copy":"whsec_<a&b>","url":"https://example.com"}`)
+	got := string(redact(c, body))
+	if want := `{"secret":"","copy":"","url":"https://example.com"}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMiddlewareSkipsRequestsItDoesNotCover(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	r := gin.New()
+	r.Use(Middleware(time.Hour, time.Minute))
+	r.Any("/hubs", func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/hubs", nil),
+		withKey(httptest.NewRequest(http.MethodGet, "/hubs", nil), "k-1"),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent || w.Header().Get(HeaderReplayed) != "" {
+			t.Errorf("%s without a key or read-only: got %d", req.Method, w.Code)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("handler ran %d times, want 2", calls)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, withKey(httptest.NewRequest(http.MethodPost, "/hubs", strings.NewReader("{}")), strings.Repeat("k", maxKeyLength+1)))
+	if w.Code != http.StatusBadRequest || calls != 2 {
+		t.Fatalf("over-long key: got %d after %d calls, want 400 without running the handler", w.Code, calls)
+	}
+}
+
+func withKey(r *http.Request, key string) *http.Request {
+	r.Header.Set(HeaderKey, key)
+	return r
+}
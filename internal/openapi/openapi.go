@@ -0,0 +1,288 @@
+// Package openapi describes the HTTP API as an OpenAPI 3 document built from the request and
+// response types of the handlers, serves it with a viewer, and validates requests against it.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/ims_rohit/pkg/response"
+)
+
+const (
+	// SpecPath serves the OpenAPI document as JSON.
+	SpecPath = "/openapi.json"
+	// ViewerPath serves a browser viewer for the document.
+	ViewerPath = "/docs"
+)
+
+//go:embed viewer.html
+var viewerHTML []byte
+
+// Route describes one route of the router. Query and Body are zero values of the structs
+// the handler binds, read through their form and json tags; binding rules become schema
+// constraints. Response is the data of the success envelope.
+type Route struct {
+	Method      string
+	Path        string // as registered with gin, e.g. /api/v1/hubs/:id
+	Summary     string
+	Description string
+	Tag         string
+	Query       interface{}
+	Body        interface{}
+	Headers     []Header
+	Status      int // success status, 200 when zero
+	Response    interface{}
+	Meta        interface{}
+	// Plain responses are written as is rather than in the success envelope.
+	Plain bool
+	// ContentType is the media type of the success response, application/json when empty.
+	ContentType string
+	// Public routes need no bearer token.
+	Public bool
+}
+
+// Header is a request header a route reads.
+type Header struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Spec is the OpenAPI document of the routes added to it.
+type Spec struct {
+	doc    *Document
+	types  map[reflect.Type]string
+	routes map[string]*Operation // by method and gin path
+	json   []byte
+}
+
+func New(title, version, description string) *Spec {
+	s := &Spec{
+		doc: &Document{
+			OpenAPI: "3.0.3",
+			Info:    Info{Title: title, Version: version, Description: description},
+			Paths:   map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]*SecurityScheme{
+					bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			},
+		},
+		types:  map[reflect.Type]string{},
+		routes: map[string]*Operation{},
+	}
+	s.schemaOf(reflect.TypeOf(response.ErrorResponse{}))
+	return s
+}
+
+// Add describes routes. It panics on a route described twice or a Query that is not a
+// struct, both mistakes in the description itself.
+func (s *Spec) Add(routes ...Route) *Spec {
+	for _, r := range routes {
+		key := routeKey(r.Method, r.Path)
+		if _, ok := s.routes[key]; ok {
+			panic("openapi: route described twice: " + key)
+		}
+		op := s.operation(r)
+		s.routes[key] = op
+
+		path := openAPIPath(r.Path)
+		item := s.doc.Paths[path]
+		if item == nil {
+			item = PathItem{}
+			s.doc.Paths[path] = item
+		}
+		item[strings.ToLower(r.Method)] = op
+	}
+	s.json = nil
+	return s
+}
+
+func (s *Spec) operation(r Route) *Operation {
+	op := &Operation{
+		OperationID: operationID(r.Method, r.Path),
+		Summary:     r.Summary,
+		Description: r.Description,
+		Responses:   map[string]*Response{},
+	}
+	if r.Tag != "" {
+		op.Tags = []string{r.Tag}
+	}
+	if !r.Public {
+		op.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+
+	for _, segment := range strings.Split(r.Path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name: segment[1:], In: "path", Required: true,
+				Schema: &Schema{Type: "integer", Format: "int64"},
+			})
+		}
+	}
+	if r.Query != nil {
+		t := reflect.TypeOf(r.Query)
+		if t.Kind() != reflect.Struct {
+			panic("openapi: query of " + routeKey(r.Method, r.Path) + " is not a struct")
+		}
+		for _, f := range s.fields(t, "form") {
+			op.Parameters = append(op.Parameters, &Parameter{Name: f.name, In: "query", Required: f.required, Schema: f.schema})
+		}
+	}
+	for _, h := range r.Headers {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name: h.Name, In: "header", Description: h.Description, Required: h.Required,
+			Schema: &Schema{Type: "string"},
+		})
+	}
+	if r.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: s.schemaOf(reflect.TypeOf(r.Body))}},
+		}
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if status != http.StatusNoContent {
+		contentType := r.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success.Content = map[string]*MediaType{contentType: {Schema: s.responseSchema(r)}}
+	}
+	op.Responses[fmt.Sprint(status)] = success
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{"application/json": {
+			Schema: s.schemaOf(reflect.TypeOf(response.ErrorResponse{})),
+		}},
+	}
+	return op
+}
+
+// responseSchema is the schema of the success envelope around the route's response.
+func (s *Spec) responseSchema(r Route) *Schema {
+	data := &Schema{}
+	if r.Response != nil {
+		data = s.schemaOf(reflect.TypeOf(r.Response))
+	}
+	if r.Plain {
+		return data
+	}
+	meta := &Schema{Nullable: true}
+	if r.Meta != nil {
+		meta = s.schemaOf(reflect.TypeOf(r.Meta))
+	}
+	return &Schema{
+		Type:     "object",
+		Required: []string{"is_success", "status_code", "data"},
+		Properties: map[string]*Schema{
+			"is_success":  {Type: "boolean"},
+			"status_code": {Type: "integer"},
+			"data":        data,
+			"meta":        meta,
+		},
+	}
+}
+
+// Check reports routes registered with gin but not described, and routes described but
+// not registered. The document and viewer routes need no description.
+func (s *Spec) Check(registered gin.RoutesInfo) error {
+	seen := map[string]bool{}
+	var undescribed []string
+	for _, r := range registered {
+		if r.Path == SpecPath || r.Path == ViewerPath {
+			continue
+		}
+		key := routeKey(r.Method, r.Path)
+		seen[key] = true
+		if _, ok := s.routes[key]; !ok {
+			undescribed = append(undescribed, key)
+		}
+	}
+	var unregistered []string
+	for key := range s.routes {
+		if !seen[key] {
+			unregistered = append(unregistered, key)
+		}
+	}
+	sort.Strings(undescribed)
+	sort.Strings(unregistered)
+
+	var problems []string
+	if len(undescribed) > 0 {
+		problems = append(problems, "routes missing from the OpenAPI spec: "+strings.Join(undescribed, ", "))
+	}
+	if len(unregistered) > 0 {
+		problems = append(problems, "OpenAPI spec describes unregistered routes: "+strings.Join(unregistered, ", "))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Document returns the OpenAPI document.
+func (s *Spec) Document() *Document {
+	return s.doc
+}
+
+// Register serves the document at SpecPath and the viewer at ViewerPath.
+func (s *Spec) Register(r gin.IRoutes) {
+	r.GET(SpecPath, s.serveDocument)
+	r.GET(ViewerPath, serveViewer)
+}
+
+func (s *Spec) serveDocument(c *gin.Context) {
+	if s.json == nil {
+		b, err := json.Marshal(s.doc)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		s.json = b
+	}
+	c.Data(http.StatusOK, "application/json", s.json)
+}
+
+func serveViewer(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", viewerHTML)
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// openAPIPath turns gin's :id and *path segments into {id} and {path}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func operationID(method, path string) string {
+	var parts []string
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.TrimLeft(segment, ":*")
+		if segment != "" {
+			parts = append(parts, strings.ReplaceAll(segment, "-", "_"))
+		}
+	}
+	return strings.ToLower(method) + "_" + strings.Join(parts, "_")
+}
@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaOf returns the schema of values of t as encoding/json writes them. Named structs
+// become components referenced by name.
+func (s *Spec) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t, nullable = t.Elem(), true
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &Schema{Type: "array", Items: s.schemaOf(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.objectSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	default:
+		return &Schema{}
+	}
+}
+
+// component registers the named struct t as a component schema and returns its name.
+func (s *Spec) component(t reflect.Type) string {
+	if name, ok := s.types[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := s.doc.Components.Schemas[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	s.types[t] = name
+	// Reserve the name before filling the schema in, for types that refer to themselves.
+	s.doc.Components.Schemas[name] = &Schema{}
+	*s.doc.Components.Schemas[name] = *s.objectSchema(t)
+	return name
+}
+
+func (s *Spec) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range s.fields(t, "json") {
+		schema.Properties[f.name] = f.schema
+		if f.required {
+			schema.Required = append(schema.Required, f.name)
+		}
+	}
+	return schema
+}
+
+type field struct {
+	name     string
+	schema   *Schema
+	required bool
+}
+
+// fields lists the fields of struct t by their tagKey name, flattening embedded structs
+// the way encoding/json and gin's form binding do.
+func (s *Spec) fields(t reflect.Type, tagKey string) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get(tagKey), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, s.fields(embedded, tagKey)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema := s.schemaOf(f.Type)
+		required := applyRules(schema, f.Tag.Get("binding"))
+		fields = append(fields, field{name: name, schema: schema, required: required})
+	}
+	return fields
+}
+
+// applyRules adds the constraints of a binding tag to schema and reports whether the
+// field is required. Rules after dive apply to the items of a slice.
+func applyRules(schema *Schema, binding string) (required bool) {
+	if binding == "" {
+		return false
+	}
+	rules := strings.Split(binding, ",")
+	for i, rule := range rules {
+		if rule == "dive" {
+			if schema.Items != nil {
+				applyRules(schema.Items, strings.Join(rules[i+1:], ","))
+			}
+			break
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+			continue
+		case "omitempty":
+			schema.omitEmpty = true
+			continue
+		}
+		if schema.Ref == "" {
+			applyRule(schema, name, param)
+		}
+	}
+	return required
+}
+
+func applyRule(schema *Schema, name, param string) {
+	switch name {
+	case "oneof":
+		for _, value := range strings.Fields(param) {
+			schema.Enum = append(schema.Enum, enumValue(schema, value))
+		}
+	case "min", "gte", "gt", "max", "lte", "lt":
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		applyBound(schema, name, bound)
+	case "latitude":
+		applyBound(schema, "gte", -90)
+		applyBound(schema, "lte", 90)
+	case "longitude":
+		applyBound(schema, "gte", -180)
+		applyBound(schema, "lte", 180)
+	case "email":
+		schema.Format = "email"
+	case "url":
+		schema.Format = "uri"
+	}
+}
+
+// applyBound sets a bound on the value of numbers, the length of strings and the size of
+// arrays, as the validator's min, max, gt and lt rules do.
+func applyBound(schema *Schema, rule string, bound float64) {
+	lower := rule == "min" || rule == "gte" || rule == "gt"
+	exclusive := rule == "gt" || rule == "lt"
+	switch schema.Type {
+	case "integer", "number":
+		if lower {
+			schema.Minimum, schema.ExclusiveMinimum = &bound, exclusive
+		} else {
+			schema.Maximum, schema.ExclusiveMaximum = &bound, exclusive
+		}
+	case "string", "array":
+		n := int(bound)
+		if exclusive && lower {
+			n++
+		} else if exclusive {
+			n--
+		}
+		switch {
+		case schema.Type == "string" && lower:
+			schema.MinLength = &n
+		case schema.Type == "string":
+			schema.MaxLength = &n
+		case lower:
+			schema.MinItems = &n
+		default:
+			schema.MaxItems = &n
+		}
+	}
+}
+
+func enumValue(schema *Schema, value string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
@@ -0,0 +1,273 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/ims_rohit/pkg/response"
+	validator "github.com/omniful/ims_rohit/pkg/validate"
+)
+
+// ValidateRequests rejects requests whose path parameters, query parameters or JSON body
+// do not match the route's description, with the same field errors binding failures get.
+// Fields the description does not know are left to the handler, which ignores them.
+func (s *Spec) ValidateRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op, ok := s.routes[routeKey(c.Request.Method, c.FullPath())]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		var errs []validator.FieldError
+		for _, p := range op.Parameters {
+			switch p.In {
+			case "path":
+				errs = append(errs, s.validateParam(p, c.Param(p.Name), true)...)
+			case "query":
+				value, present := c.GetQuery(p.Name)
+				errs = append(errs, s.validateParam(p, value, present)...)
+			}
+		}
+
+		if op.RequestBody != nil {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				abortInvalid(c, "failed to read request body", nil)
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			if len(bytes.TrimSpace(body)) == 0 {
+				abortInvalid(c, "invalid request: request body is required", nil)
+				return
+			}
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				abortInvalid(c, "invalid request: "+err.Error(), nil)
+				return
+			}
+			errs = append(errs, s.validateValue(op.RequestBody.Content["application/json"].Schema, value, "")...)
+		}
+
+		if len(errs) > 0 {
+			abortInvalid(c, "request validation failed", errs)
+			return
+		}
+		c.Next()
+	}
+}
+
+func abortInvalid(c *gin.Context, message string, fields []validator.FieldError) {
+	var details interface{}
+	if fields != nil {
+		details = fields
+	}
+	response.NewErrorResponse(c, oerror.NewCustomError(oerror.RequestInvalid, message), details)
+}
+
+// validateParam checks a path or query parameter, given as text.
+func (s *Spec) validateParam(p *Parameter, raw string, present bool) []validator.FieldError {
+	if !present || raw == "" {
+		if p.Required {
+			return []validator.FieldError{validator.NewFieldError(p.Name, "required", "")}
+		}
+		return nil
+	}
+	var value interface{} = raw
+	switch p.Schema.Type {
+	case "integer", "number":
+		value = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []validator.FieldError{validator.NewFieldError(p.Name, "type", "boolean")}
+		}
+		value = b
+	}
+	return s.validateValue(p.Schema, value, p.Name)
+}
+
+// validateValue checks a decoded JSON value against schema. field names the value in
+// errors, e.g. postcodes[0].latitude. Nulls are not checked: the handlers read them as
+// zero values and their binding rules decide.
+func (s *Spec) validateValue(schema *Schema, value interface{}, field string) []validator.FieldError {
+	schema = s.resolve(schema)
+	if schema == nil || value == nil || schema.omitEmpty && isZero(value) {
+		return nil
+	}
+	name := field
+	if name == "" {
+		name = "body"
+	}
+
+	switch schema.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []validator.FieldError{validator.NewFieldError(name, "type", "string")}
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return []validator.FieldError{validator.NewFieldError(name, "format", "RFC 3339 date-time")}
+			}
+		}
+		length := utf8.RuneCountInString(str)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			return []validator.FieldError{validator.NewFieldError(name, "min", strconv.Itoa(*schema.MinLength))}
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			return []validator.FieldError{validator.NewFieldError(name, "max", strconv.Itoa(*schema.MaxLength))}
+		}
+		return checkEnum(schema, str, name)
+
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			return []validator.FieldError{validator.NewFieldError(name, "type", schema.Type)}
+		}
+		var n float64
+		if schema.Type == "integer" {
+			i, err := num.Int64()
+			if err != nil {
+				return []validator.FieldError{validator.NewFieldError(name, "type", "integer")}
+			}
+			n = float64(i)
+		} else {
+			f, err := num.Float64()
+			if err != nil {
+				return []validator.FieldError{validator.NewFieldError(name, "type", "number")}
+			}
+			n = f
+		}
+		if err := checkBounds(schema, n, name); err != nil {
+			return []validator.FieldError{*err}
+		}
+		return checkEnum(schema, n, name)
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []validator.FieldError{validator.NewFieldError(name, "type", "boolean")}
+		}
+		return nil
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []validator.FieldError{validator.NewFieldError(name, "type", "array")}
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			return []validator.FieldError{validator.NewFieldError(name, "min", strconv.Itoa(*schema.MinItems))}
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			return []validator.FieldError{validator.NewFieldError(name, "max", strconv.Itoa(*schema.MaxItems))}
+		}
+		var errs []validator.FieldError
+		for i, item := range items {
+			errs = append(errs, s.validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+		return errs
+
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []validator.FieldError{validator.NewFieldError(name, "type", "object")}
+		}
+		var errs []validator.FieldError
+		for _, required := range schema.Required {
+			if object[required] == nil {
+				errs = append(errs, validator.NewFieldError(join(field, required), "required", ""))
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v := object[key]
+			if prop, ok := schema.Properties[key]; ok {
+				errs = append(errs, s.validateValue(prop, v, join(field, key))...)
+			} else if schema.AdditionalProperties != nil {
+				errs = append(errs, s.validateValue(schema.AdditionalProperties, v, join(field, key))...)
+			}
+		}
+		return errs
+	}
+	return nil
+}
+
+// resolve follows a component reference.
+func (s *Spec) resolve(schema *Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	return s.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+}
+
+func checkBounds(schema *Schema, n float64, name string) *validator.FieldError {
+	if min := schema.Minimum; min != nil && (n < *min || schema.ExclusiveMinimum && n == *min) {
+		rule := "min"
+		if schema.ExclusiveMinimum {
+			rule = "gt"
+		}
+		err := validator.NewFieldError(name, rule, strconv.FormatFloat(*min, 'f', -1, 64))
+		return &err
+	}
+	if max := schema.Maximum; max != nil && (n > *max || schema.ExclusiveMaximum && n == *max) {
+		rule := "max"
+		if schema.ExclusiveMaximum {
+			rule = "lt"
+		}
+		err := validator.NewFieldError(name, rule, strconv.FormatFloat(*max, 'f', -1, 64))
+		return &err
+	}
+	return nil
+}
+
+func checkEnum(schema *Schema, value interface{}, name string) []validator.FieldError {
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+	allowed := make([]string, len(schema.Enum))
+	for i, e := range schema.Enum {
+		allowed[i] = fmt.Sprint(e)
+		if allowed[i] == fmt.Sprint(value) {
+			return nil
+		}
+	}
+	return []validator.FieldError{validator.NewFieldError(name, "oneof", strings.Join(allowed, " "))}
+}
+
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func join(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/omniful/go_commons/config"
+)
+
+// Event is a change to publish. Events with the same Key are published in the order
+// they were written. AggregateType selects the topic from outbox.topics in config.yaml.
+type Event struct {
+	TenantID      int64
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Key           string
+	Data          interface{}
+}
+
+// Write stores the event inside tx, so it is published if and only if the change it
+// describes commits. The relay picks it up from there.
+func Write(ctx context.Context, tx *sql.Tx, e Event) error {
+	topic := config.GetString(ctx, "outbox.topics."+e.AggregateType)
+	if topic == "" {
+		return fmt.Errorf("no outbox topic configured for %s events", e.AggregateType)
+	}
+
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", e.EventType, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO outbox_events (tenant_id, aggregate_type, aggregate_id, event_type, topic, partition_key, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		e.TenantID, e.AggregateType, e.AggregateID, e.EventType, topic, e.Key, data)
+	if err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/go_commons/pubsub"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+// relayLockKey is the advisory lock held while a relay claims events. Claims are taken
+// one relay at a time, so two relays never claim events of the same key and publish them
+// out of order. The lock is only held for the claim, not while publishing.
+const relayLockKey = 7301
+
+type Publisher interface {
+	Publish(ctx context.Context, msg *pubsub.Message) error
+}
+
+// Envelope is the message body consumers receive. EventID is stable across redeliveries,
+// so consumers dedupe on it.
+type Envelope struct {
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	TenantID   int64           `json:"tenant_id"`
+	Aggregate  string          `json:"aggregate"`
+	ID         string          `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type Config struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// MaxAttempts is the number of failed publishes after which an event is parked. Parked
+	// events are no longer published and no longer hold back later events of their key.
+	MaxAttempts int
+	// ClaimTimeout is how long claimed events are left to the relay that claimed them. Events
+	// of a relay that stopped before publishing them are claimed again afterwards.
+	ClaimTimeout time.Duration
+}
+
+type Relay struct {
+	publisher Publisher
+	cfg       Config
+}
+
+func NewRelay(publisher Publisher, cfg Config) *Relay {
+	return &Relay{publisher: publisher, cfg: cfg}
+}
+
+// Run publishes pending events until ctx is done. Full batches are followed immediately
+// by the next one; otherwise the relay waits a poll interval.
+func (r *Relay) Run(ctx context.Context) {
+	log.Infof("Starting outbox relay")
+	for {
+		published, err := r.publishBatch(ctx)
+		if err != nil {
+			log.WithError(err).Error("outbox relay batch failed")
+		}
+		if err == nil && published == r.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+type pendingEvent struct {
+	id int64
+	Envelope
+	topic string
+	key   string
+}
+
+// partitionKey identifies the events that must be published in order.
+type partitionKey struct {
+	topic string
+	key   string
+}
+
+// publishBatch claims the oldest pending events and publishes them in id order, marking
+// each published as it goes. When an event fails, the later events of its key in the batch
+// are left for the next batch, and other keys carry on. Delivery is at least once: a crash
+// between publishing and marking republishes the event with the same event ID.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	events, err := r.claimEvents(ctx)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	db := pg.GetClient().DB
+	var (
+		published int
+		firstErr  error
+		failed    = map[partitionKey]bool{}
+		skipped   []int64
+	)
+	for _, e := range events {
+		key := partitionKey{topic: e.topic, key: e.key}
+		if failed[key] {
+			skipped = append(skipped, e.id)
+			continue
+		}
+		if err := r.publish(ctx, e); err != nil {
+			failed[key] = true
+			if firstErr == nil {
+				firstErr = err
+			}
+			if err := r.recordFailure(ctx, db, e, err); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `UPDATE outbox_events SET published_at = NOW(), claimed_until = NULL WHERE id = $1`, e.id); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	if len(skipped) > 0 {
+		if _, err := db.ExecContext(ctx, `UPDATE outbox_events SET claimed_until = NULL WHERE id = ANY($1)`, pq.Array(skipped)); err != nil {
+			return published, err
+		}
+	}
+	return published, firstErr
+}
+
+// claimEvents claims the oldest pending events of keys no other relay has claimed events
+// of, in id order.
+func (r *Relay) claimEvents(ctx context.Context) ([]*pendingEvent, error) {
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, relayLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	events, err := pendingEvents(ctx, tx, r.cfg.BatchSize, r.cfg.ClaimTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return events, tx.Commit()
+}
+
+// recordFailure counts a failed publish of e, parking it once it has used up its attempts.
+func (r *Relay) recordFailure(ctx context.Context, db *sql.DB, e *pendingEvent, publishErr error) error {
+	var parked bool
+	err := db.QueryRowContext(ctx, `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $1, claimed_until = NULL,
+			parked_at = CASE WHEN attempts + 1 >= $2 THEN NOW() END
+		WHERE id = $3
+		RETURNING parked_at IS NOT NULL`, publishErr.Error(), r.cfg.MaxAttempts, e.id).Scan(&parked)
+	if err != nil {
+		return err
+	}
+	if parked {
+		log.WithError(publishErr).Error(fmt.Sprintf("outbox event %s parked after %d attempts", e.EventID, r.cfg.MaxAttempts))
+	}
+	return nil
+}
+
+// pendingEvents claims up to limit unpublished, unparked events for claimTimeout. Keys with
+// claimed events are passed over, so events of a key are only ever held by one relay.
+func pendingEvents(ctx context.Context, tx *sql.Tx, limit int, claimTimeout time.Duration) ([]*pendingEvent, error) {
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE outbox_events
+		SET claimed_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT e.id
+			FROM outbox_events e
+			WHERE e.published_at IS NULL AND e.parked_at IS NULL
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox_events c
+					WHERE c.topic = e.topic AND c.partition_key = e.partition_key
+						AND c.published_at IS NULL AND c.parked_at IS NULL AND c.claimed_until > NOW())
+			ORDER BY e.id
+			LIMIT $1)
+		RETURNING id, event_id, event_type, tenant_id, aggregate_type, aggregate_id, topic, partition_key, data, created_at`,
+		limit, claimTimeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*pendingEvent
+	for rows.Next() {
+		var (
+			e    = &pendingEvent{}
+			data []byte
+		)
+		err := rows.Scan(&e.id, &e.EventID, &e.EventType, &e.TenantID, &e.Aggregate, &e.ID, &e.topic, &e.key,
+			&data, &e.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		e.Data = data
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the order of the subquery.
+	sort.Slice(events, func(i, j int) bool { return events[i].id < events[j].id })
+	return events, nil
+}
+
+func (r *Relay) publish(ctx context.Context, e *pendingEvent) error {
+	value, err := json.Marshal(e.Envelope)
+	if err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, &pubsub.Message{
+		Topic: e.topic,
+		Key:   e.key,
+		Value: value,
+		Headers: map[string]string{
+			"event":     e.EventType,
+			"event_id":  e.EventID,
+			"tenant_id": strconv.FormatInt(e.TenantID, 10),
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omniful/go_commons/pubsub"
+)
+
+// recordingPublisher keeps the messages it is asked to publish.
+type recordingPublisher struct {
+	messages []*pubsub.Message
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, msg *pubsub.Message) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func TestPublishSendsTheEnvelope(t *testing.T) {
+	publisher := &recordingPublisher{}
+	r := NewRelay(publisher, Config{})
+	event := &pendingEvent{
+		id: 41,
+		Envelope: Envelope{
+			EventID:    "evt-1",
+			EventType:  "inventory.updated",
+			TenantID:   3,
+			Aggregate:  "inventory",
+			ID:         "2:10",
+			OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Data:       json.RawMessage(`{"quantity":5}`),
+		},
+		topic: "inventory-events",
+		key:   "3:2",
+	}
+	if err := r.publish(context.Background(), event); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(publisher.messages))
+	}
+	msg := publisher.messages[0]
+	if msg.Topic != "inventory-events" || msg.Key != "3:2" {
+		t.Errorf("got topic %q and key %q", msg.Topic, msg.Key)
+	}
+	wantHeaders := map[string]string{"event": "inventory.updated", "event_id": "evt-1", "tenant_id": "3"}
+	if !reflect.DeepEqual(msg.Headers, wantHeaders) {
+		t.Errorf("got headers %v, want %v", msg.Headers, wantHeaders)
+	}
+	var got Envelope
+	if err := json.Unmarshal(msg.Value, &got); err != nil {
+		t.Fatalf("the value is not an envelope: %v", err)
+	}
+	if !reflect.DeepEqual(got, event.Envelope) {
+		t.Errorf("got envelope %+v, want %+v", got, event.Envelope)
+	}
+}
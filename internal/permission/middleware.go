@@ -0,0 +1,33 @@
+package permission
+
+import (
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	"github.com/omniful/go_commons/log"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+	"github.com/omniful/ims_rohit/pkg/response"
+)
+
+// Require lets the request through only if the caller's role grants at least one of the
+// actions.
+func Require(actions ...Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, err := Allowed(c, actions...)
+		if err != nil {
+			log.WithError(err).Error("permission check failed")
+			AbortForbidden(c)
+			return
+		}
+		if !allowed {
+			AbortForbidden(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AbortForbidden answers with the standard error envelope and the forbidden code.
+func AbortForbidden(c *gin.Context) {
+	response.NewErrorResponse(c, oerror.NewCustomError(pkgerror.Forbidden, "you don't have permission to perform this action"), nil)
+}
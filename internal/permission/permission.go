@@ -0,0 +1,110 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/omniful/go_commons/config"
+	"github.com/omniful/go_commons/constants"
+	"github.com/omniful/go_commons/jwt/private"
+	"github.com/omniful/go_commons/jwt/public"
+)
+
+type Action string
+
+const (
+	InventoryDecrement       Action = "inventory:decrement"
+	InventoryAdjust          Action = "inventory:adjust"
+	InventorySet             Action = "inventory:set"
+	InventoryCount           Action = "inventory:count"
+	InventoryApproveVariance Action = "inventory:approve_variance"
+	SKUWrite                 Action = "sku:write"
+	HubWrite                 Action = "hub:write"
+	HubAdmin                 Action = "hub:admin"
+	AuditRead                Action = "audit:read"
+	WebhookAdmin             Action = "webhook:admin"
+
+	// ConsumerAdmin covers service-wide data such as dead-lettered messages of every
+	// tenant, so AllActions does not include it; it has to be granted by name.
+	ConsumerAdmin Action = "consumer:admin"
+
+	// AllActions grants every tenant-level action to a role.
+	AllActions Action = "*"
+)
+
+var serviceActions = map[Action]bool{ConsumerAdmin: true}
+
+const (
+	RolePicker     = "picker"
+	RoleSupervisor = "supervisor"
+	RoleAdmin      = "admin"
+)
+
+// defaultRoleActions applies when neither the tenant nor the service config maps a role.
+var defaultRoleActions = map[string][]Action{
+	RolePicker: {InventoryDecrement, InventoryCount},
+	RoleSupervisor: {
+		InventoryDecrement, InventoryAdjust, InventorySet, InventoryCount, InventoryApproveVariance,
+		SKUWrite, HubWrite, AuditRead,
+	},
+	RoleAdmin: {AllActions},
+}
+
+// actionsForRole resolves what a role may do for a tenant. A tenant-specific mapping under
+// permissions.tenants.<tenant_id> wins over permissions.default, which wins over the
+// built-in defaults.
+func actionsForRole(ctx context.Context, tenantID, role string) []Action {
+	keys := []string{
+		fmt.Sprintf("permissions.tenants.%s.%s", tenantID, role),
+		fmt.Sprintf("permissions.default.%s", role),
+	}
+	for _, key := range keys {
+		if values := config.GetStringSlice(ctx, key); len(values) > 0 {
+			actions := make([]Action, 0, len(values))
+			for _, v := range values {
+				actions = append(actions, Action(v))
+			}
+			return actions
+		}
+	}
+	return defaultRoleActions[role]
+}
+
+// roleFromContext returns the caller's role from the user details the JWT middleware
+// placed in the context.
+func roleFromContext(ctx context.Context) (string, error) {
+	userDetails, ok := ctx.Value(constants.PrivateUserDetails).(*private.UserDetails)
+	if !ok {
+		return "", errors.New("user details not found in ctx")
+	}
+	return userDetails.Role, nil
+}
+
+// Allowed reports whether the caller may perform any of the given actions.
+func Allowed(ctx context.Context, actions ...Action) (bool, error) {
+	tenantID, err := public.GetTenantID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	role, err := roleFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return grants(actionsForRole(ctx, tenantID, role), actions...), nil
+}
+
+// grants reports whether granted includes any of actions. AllActions includes every
+// action but the service-wide ones.
+func grants(granted []Action, actions ...Action) bool {
+	for _, g := range granted {
+		for _, a := range actions {
+			if g == a || (g == AllActions && !serviceActions[a]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package permission
+
+import "testing"
+
+func TestGrants(t *testing.T) {
+	cases := []struct {
+		name    string
+		granted []Action
+		actions []Action
+		want    bool
+	}{
+		{"picker decrements", defaultRoleActions[RolePicker], []Action{InventoryDecrement}, true},
+		{"picker sets", defaultRoleActions[RolePicker], []Action{InventorySet}, false},
+		{"any of the actions", defaultRoleActions[RolePicker], []Action{InventoryAdjust, InventoryDecrement}, true},
+		{"supervisor approves variances", defaultRoleActions[RoleSupervisor], []Action{InventoryApproveVariance}, true},
+		{"supervisor manages webhooks", defaultRoleActions[RoleSupervisor], []Action{WebhookAdmin}, false},
+		{"admin manages webhooks", defaultRoleActions[RoleAdmin], []Action{WebhookAdmin}, true},
+		{"admin replays dead letters", defaultRoleActions[RoleAdmin], []Action{ConsumerAdmin}, false},
+		{"consumer admin by name", []Action{ConsumerAdmin}, []Action{ConsumerAdmin}, true},
+		{"unknown role", defaultRoleActions["driver"], []Action{InventoryDecrement}, false},
+		{"no actions asked", []Action{AllActions}, nil, false},
+	}
+	for _, c := range cases {
+		if got := grants(c.granted, c.actions...); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
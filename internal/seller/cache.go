@@ -6,12 +6,13 @@ import (
 	"github.com/omniful/api-gateway/constants"
 	"github.com/omniful/api-gateway/external_service/tenant/private"
 	cache2 "github.com/omniful/go_commons/redis_cache"
+	"github.com/omniful/ims_rohit/internal/tenantcache"
 	"sync"
 )
 
 type Cache struct {
-	cache        cache2.ICache
-	tenantClient private.Client
+	tenantSellers *tenantcache.Cache
+	tenantClient  private.Client
 }
 
 var cache *Cache
@@ -26,25 +27,22 @@ func NewCache(ctx context.Context, c cache2.ICache) (*Cache, error) {
 		}
 
 		cache = &Cache{
-			cache:        c,
 			tenantClient: tenantSvcClient,
 		}
+		cache.tenantSellers = tenantcache.New(ctx, tenantSellersCacheKeyPrefix, c, cache.fetchTenantSellerIDs, constants.OneDayExpiration)
 	})
 
 	return cache, cacheErr
 }
 
+// GetTenantSellerIDs returns the tenant's seller IDs from the cache, fetching them from the
+// tenant service at most once at a time per tenant. See tenantcache for refresh and
+// stale-serving rules.
 func (c *Cache) GetTenantSellerIDs(ctx context.Context, tenantID string) (sellers []string, err error) {
-	cacheKey := getTenantSellersCacheKey(ctx, tenantID)
-	found, err := c.cache.Get(ctx, cacheKey, &sellers)
-	if err != nil {
-		return
-	}
-
-	if found {
-		return
-	}
+	return c.tenantSellers.Get(ctx, tenantID)
+}
 
+func (c *Cache) fetchTenantSellerIDs(ctx context.Context, tenantID string) (sellers []string, err error) {
 	tenantSellers, interSvcErr := c.tenantClient.GetTenantSellers(ctx, tenantID, map[string][]string{})
 	if interSvcErr != nil {
 		err = errors.New(interSvcErr.Message)
@@ -54,32 +52,13 @@ func (c *Cache) GetTenantSellerIDs(ctx context.Context, tenantID string) (seller
 	for _, v := range *tenantSellers {
 		sellers = append(sellers, v.ID)
 	}
-
-	err = c.setTenantSellers(ctx, tenantID, sellers)
-	if err != nil {
-		return
-	}
-	return
-}
-
-func (c *Cache) setTenantSellers(ctx context.Context, tenantID string, sellers []string) (err error) {
-	cacheKey := getTenantSellersCacheKey(ctx, tenantID)
-	_, err = c.cache.Set(ctx, cacheKey, sellers, constants.OneDayExpiration)
-	if err != nil {
-		return
-	}
 	return
 }
 
 func (c *Cache) InvalidateSellerIDs(ctx context.Context, sellerID string) (err error) {
-	cacheKey := getTenantSellersCacheKey(ctx, sellerID)
-	_, err = c.cache.Unlink(ctx, []string{cacheKey})
-	if err != nil {
-		return
-	}
-	return
+	return c.tenantSellers.Invalidate(ctx, sellerID)
 }
 
-func getTenantSellersCacheKey(ctx context.Context, tenantID string) string {
-	return "tenant_sellers_" + tenantID
-}
+// tenantSellersCacheKeyPrefix is versioned since entries switched from a bare ID list to
+// tenantcache entries.
+const tenantSellersCacheKeyPrefix = "tenant_sellers_v2_"
@@ -0,0 +1,222 @@
+package stockstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/omniful/api-gateway/pkg/redis"
+	"github.com/omniful/go_commons/config"
+)
+
+// Change is the balance of a SKU at a hub after an inventory write.
+type Change struct {
+	HubID     int64     `json:"hub_id"`
+	SKUID     int64     `json:"sku_id"`
+	Qty       int64     `json:"quantity"`
+	Reserved  int64     `json:"reserved"`
+	Version   int64     `json:"version"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Event is a Change as delivered to subscribers. ID is the change's position in its hub's
+// history; a subscriber passes the last ID it saw to resume from there.
+type Event struct {
+	ID string
+	Change
+}
+
+// publish appends a change to its hub's history, capped at about ARGV[2] entries, and
+// announces it on the hub's channel, ARGV[4], as "<id> <change>". Doing both in one
+// script keeps the history and the channel in the same order.
+var publish = goredis.NewScript(`
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[2], '*', 'change', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('PUBLISH', ARGV[4], id .. ' ' .. ARGV[1])
+return id
+`)
+
+// Stream carries the stock changes of every hub: writers publish to it and subscribers,
+// such as dashboards, follow one hub. Each hub's recent changes are kept so a subscriber
+// that lost its connection can resume without missing any.
+type Stream struct {
+	client    goredis.UniversalClient
+	prefix    string
+	history   int64
+	retention time.Duration
+	buffer    int
+
+	mu     sync.Mutex
+	subs   map[hubKey]map[*Subscription]struct{}
+	pubsub *goredis.PubSub
+	closed bool
+}
+
+type hubKey struct {
+	tenantID int64
+	hubID    int64
+}
+
+// defaultBuffer is the number of changes a subscriber may fall behind by when
+// stock_stream.buffer is not set.
+const defaultBuffer = 256
+
+var stream *Stream
+var streamOnce sync.Once
+
+func GetStream(ctx context.Context) *Stream {
+	streamOnce.Do(func() {
+		stream = &Stream{
+			client:    redis.GetClient().Client,
+			prefix:    config.GetString(ctx, "service.name"),
+			history:   int64(config.GetInt(ctx, "stock_stream.history")),
+			retention: config.GetDuration(ctx, "stock_stream.retention"),
+			buffer:    config.GetInt(ctx, "stock_stream.buffer"),
+			subs:      map[hubKey]map[*Subscription]struct{}{},
+		}
+		if stream.buffer <= 0 {
+			stream.buffer = defaultBuffer
+		}
+	})
+	return stream
+}
+
+// Enabled reports whether inventory writes publish their changes, per stock_stream.enabled.
+func Enabled(ctx context.Context) bool {
+	return config.GetBool(ctx, "stock_stream.enabled")
+}
+
+func (s *Stream) historyKey(k hubKey) string {
+	return fmt.Sprintf("%s:stock_history:%d:%d", s.prefix, k.tenantID, k.hubID)
+}
+
+func (s *Stream) channel(k hubKey) string {
+	return fmt.Sprintf("%s:stock_changes:%d:%d", s.prefix, k.tenantID, k.hubID)
+}
+
+// parseChannel is the inverse of channel.
+func (s *Stream) parseChannel(channel string) (hubKey, bool) {
+	rest, ok := strings.CutPrefix(channel, s.prefix+":stock_changes:")
+	if !ok {
+		return hubKey{}, false
+	}
+	tenant, hub, ok := strings.Cut(rest, ":")
+	if !ok {
+		return hubKey{}, false
+	}
+	tenantID, err := strconv.ParseInt(tenant, 10, 64)
+	if err != nil {
+		return hubKey{}, false
+	}
+	hubID, err := strconv.ParseInt(hub, 10, 64)
+	if err != nil {
+		return hubKey{}, false
+	}
+	return hubKey{tenantID: tenantID, hubID: hubID}, true
+}
+
+// Publish records changes of a tenant's hub, in order.
+func (s *Stream) Publish(ctx context.Context, tenantID, hubID int64, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	k := hubKey{tenantID: tenantID, hubID: hubID}
+	pipe := s.client.Pipeline()
+	for _, c := range changes {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("failed to encode stock change: %w", err)
+		}
+		publish.Eval(ctx, pipe, []string{s.historyKey(k)}, data, s.history, s.retention.Milliseconds(), s.channel(k))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// since returns the changes of a hub after the one with ID lastID, oldest first. complete
+// is false when some of them are no longer kept, because they were trimmed from the
+// history or are older than its retention.
+func (s *Stream) since(ctx context.Context, k hubKey, lastID string) (events []*Event, complete bool, err error) {
+	last, ok := parseID(lastID)
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Since(time.UnixMilli(int64(last.ms))) > s.retention {
+		return nil, false, nil
+	}
+
+	key := s.historyKey(k)
+	oldest, err := s.client.XRangeN(ctx, key, "-", "+", 1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(oldest) > 0 {
+		if first, _ := parseID(oldest[0].ID); last.less(first) {
+			return nil, false, nil
+		}
+	}
+
+	entries, err := s.client.XRange(ctx, key, lastID, "+").Result()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, entry := range entries {
+		if entry.ID == lastID {
+			continue
+		}
+		data, _ := entry.Values["change"].(string)
+		event, err := decodeEvent(entry.ID, data)
+		if err != nil {
+			return nil, false, err
+		}
+		events = append(events, event)
+	}
+	return events, true, nil
+}
+
+func decodeEvent(id, data string) (*Event, error) {
+	event := &Event{ID: id}
+	if err := json.Unmarshal([]byte(data), &event.Change); err != nil {
+		return nil, fmt.Errorf("failed to decode stock change %s: %w", id, err)
+	}
+	return event, nil
+}
+
+// eventID is a Redis stream entry ID, <milliseconds>-<sequence>.
+type eventID struct {
+	ms, seq uint64
+}
+
+func parseID(id string) (eventID, bool) {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return eventID{}, false
+	}
+	var (
+		parsed eventID
+		err    error
+	)
+	if parsed.ms, err = strconv.ParseUint(ms, 10, 64); err != nil {
+		return eventID{}, false
+	}
+	if parsed.seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+		return eventID{}, false
+	}
+	return parsed, true
+}
+
+func (id eventID) less(other eventID) bool {
+	return id.ms < other.ms || (id.ms == other.ms && id.seq < other.seq)
+}
+
+// After reports whether the event with ID a comes after the one with ID b.
+func After(a, b string) bool {
+	idA, okA := parseID(a)
+	idB, okB := parseID(b)
+	return okA && okB && idB.less(idA)
+}
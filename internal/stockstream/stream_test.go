@@ -0,0 +1,169 @@
+package stockstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+func newTestStream(t *testing.T, buffer int) (*Stream, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	s := &Stream{
+		client:    client,
+		prefix:    "ims",
+		history:   100,
+		retention: time.Hour,
+		buffer:    buffer,
+		subs:      map[hubKey]map[*Subscription]struct{}{},
+	}
+	t.Cleanup(func() {
+		s.Close()
+		client.Close()
+	})
+	return s, server
+}
+
+func TestAfter(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"1700000000001-0", "1700000000000-5", true},
+		{"1700000000000-6", "1700000000000-5", true},
+		{"1700000000000-5", "1700000000000-5", false},
+		{"1700000000000-4", "1700000000000-5", false},
+		{"1700000000001-0", "not-an-id", false},
+		{"", "1700000000000-5", false},
+	}
+	for _, c := range cases {
+		if got := After(c.a, c.b); got != c.want {
+			t.Errorf("After(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	s := &Stream{prefix: "ims"}
+	k := hubKey{tenantID: 4, hubID: 9}
+	if got, ok := s.parseChannel(s.channel(k)); !ok || got != k {
+		t.Fatalf("got %+v, %v, want %+v", got, ok, k)
+	}
+	for _, channel := range []string{"other:stock_changes:4:9", "ims:stock_changes:4", "ims:stock_changes:x:9"} {
+		if _, ok := s.parseChannel(channel); ok {
+			t.Errorf("%s parsed as a hub's channel", channel)
+		}
+	}
+}
+
+func TestSinceResumesFromTheHistory(t *testing.T) {
+	s, _ := newTestStream(t, 8)
+	ctx := context.Background()
+	changes := []Change{{HubID: 2, SKUID: 10, Qty: 5}, {HubID: 2, SKUID: 11, Qty: 3}, {HubID: 2, SKUID: 10, Qty: 4}}
+	if err := s.Publish(ctx, 1, 2, changes); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	all, err := s.client.XRange(ctx, s.historyKey(hubKey{tenantID: 1, hubID: 2}), "-", "+").Result()
+	if err != nil || len(all) != 3 {
+		t.Fatalf("history: got %d entries, %v", len(all), err)
+	}
+
+	sub, err := s.Subscribe(1, 2, []int64{10})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	events, complete, err := sub.Since(ctx, all[0].ID)
+	if err != nil || !complete {
+		t.Fatalf("Since: complete %v, %v", complete, err)
+	}
+	if len(events) != 1 || events[0].ID != all[2].ID || events[0].Qty != 4 {
+		t.Fatalf("got %+v, want only the last change of SKU 10", events)
+	}
+
+	if _, complete, _ := sub.Since(ctx, "garbage"); complete {
+		t.Error("a malformed last ID resumed as complete")
+	}
+	if _, complete, _ := sub.Since(ctx, "1-0"); complete {
+		t.Error("a last ID older than the retention resumed as complete")
+	}
+}
+
+func TestSubscriptionsReceiveTheirHubsChanges(t *testing.T) {
+	s, server := newTestStream(t, 8)
+	ctx := context.Background()
+	sub, err := s.Subscribe(1, 2, []int64{10})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	// PSUBSCRIBE is sent on its own connection; publish once Redis has it.
+	for deadline := time.Now().Add(time.Second); server.PubSubNumPat() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the Redis subscription was not made")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := s.Publish(ctx, 1, 3, []Change{{HubID: 3, SKUID: 10, Qty: 1}}); err != nil {
+		t.Fatalf("Publish to another hub: %v", err)
+	}
+	if err := s.Publish(ctx, 1, 2, []Change{{HubID: 2, SKUID: 11, Qty: 1}, {HubID: 2, SKUID: 10, Qty: 7}}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	select {
+	case event := <-sub.Events():
+		if event.HubID != 2 || event.SKUID != 10 || event.Qty != 7 || event.ID == "" {
+			t.Fatalf("got %+v, want the change of SKU 10 at hub 2", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no change arrived")
+	}
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("got %+v, want no other change", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSlowSubscribersAreDropped(t *testing.T) {
+	s := &Stream{buffer: 1, subs: map[hubKey]map[*Subscription]struct{}{}, pubsub: &goredis.PubSub{}}
+	sub, err := s.Subscribe(1, 2, nil)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	key := hubKey{tenantID: 1, hubID: 2}
+	s.dispatch(key, &Event{ID: "1-0"})
+	s.dispatch(key, &Event{ID: "2-0"})
+
+	if event := <-sub.Events(); event.ID != "1-0" {
+		t.Fatalf("got %s, want the buffered change", event.ID)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("a subscriber a full buffer behind was kept")
+	}
+	if len(s.subs) != 0 {
+		t.Fatalf("got %d hubs with subscribers, want none", len(s.subs))
+	}
+}
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	s, _ := newTestStream(t, 8)
+	sub, err := s.Subscribe(1, 2, nil)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("the subscription is still open")
+	}
+	sub.Close()
+	if _, err := s.Subscribe(1, 2, nil); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Subscribe after Close: got %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,156 @@
+package stockstream
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/omniful/go_commons/log"
+)
+
+// ErrClosed is returned by Subscribe once the stream is closed.
+var ErrClosed = errors.New("stock stream is closed")
+
+// Subscription receives the changes of one hub published after it was made.
+type Subscription struct {
+	stream *Stream
+	key    hubKey
+	skuIDs map[int64]bool
+	events chan *Event
+	done   bool
+}
+
+// Subscribe follows the changes of a tenant's hub, only those of skuIDs when there are
+// any. All subscriptions of a process share one Redis subscription.
+func (s *Stream) Subscribe(tenantID, hubID int64, skuIDs []int64) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.pubsub == nil {
+		// Subscribing from a fresh context: the Redis subscription outlives the request
+		// that started it.
+		s.pubsub = s.client.PSubscribe(context.Background(), s.prefix+":stock_changes:*")
+		go s.receive()
+	}
+
+	sub := &Subscription{
+		stream: s,
+		key:    hubKey{tenantID: tenantID, hubID: hubID},
+		events: make(chan *Event, s.buffer),
+	}
+	if len(skuIDs) > 0 {
+		sub.skuIDs = make(map[int64]bool, len(skuIDs))
+		for _, id := range skuIDs {
+			sub.skuIDs[id] = true
+		}
+	}
+	if s.subs[sub.key] == nil {
+		s.subs[sub.key] = map[*Subscription]struct{}{}
+	}
+	s.subs[sub.key][sub] = struct{}{}
+	return sub, nil
+}
+
+// Events delivers the subscription's changes in order. It is closed when the subscription
+// ends: on Close, when the stream closes, or when the subscriber falls a full buffer
+// behind. The subscriber can then resume from the last event it received.
+func (sub *Subscription) Events() <-chan *Event {
+	return sub.events
+}
+
+// Since returns the subscription's changes published after the event with ID lastID,
+// for a subscriber resuming after it. complete is false when some of them are no longer
+// kept; the subscriber then has to read the current stock afresh. Changes published since
+// the subscription was made also arrive on Events: those not After the last one returned
+// here are duplicates.
+func (sub *Subscription) Since(ctx context.Context, lastID string) (events []*Event, complete bool, err error) {
+	all, complete, err := sub.stream.since(ctx, sub.key, lastID)
+	if err != nil || sub.skuIDs == nil {
+		return all, complete, err
+	}
+	for _, event := range all {
+		if sub.skuIDs[event.SKUID] {
+			events = append(events, event)
+		}
+	}
+	return events, complete, nil
+}
+
+func (sub *Subscription) Close() {
+	sub.stream.mu.Lock()
+	defer sub.stream.mu.Unlock()
+	sub.stream.remove(sub)
+}
+
+// remove ends a subscription. s.mu must be held.
+func (s *Stream) remove(sub *Subscription) {
+	if sub.done {
+		return
+	}
+	sub.done = true
+	close(sub.events)
+	delete(s.subs[sub.key], sub)
+	if len(s.subs[sub.key]) == 0 {
+		delete(s.subs, sub.key)
+	}
+}
+
+// receive hands the messages of the Redis subscription to the subscriptions of their hub
+// until the stream is closed.
+func (s *Stream) receive() {
+	for msg := range s.pubsub.Channel() {
+		key, ok := s.parseChannel(msg.Channel)
+		if !ok {
+			continue
+		}
+		id, data, ok := strings.Cut(msg.Payload, " ")
+		if !ok {
+			continue
+		}
+		event, err := decodeEvent(id, data)
+		if err != nil {
+			log.WithError(err).Error("dropping malformed stock change")
+			continue
+		}
+		s.dispatch(key, event)
+	}
+}
+
+func (s *Stream) dispatch(key hubKey, event *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs[key] {
+		if sub.skuIDs != nil && !sub.skuIDs[event.SKUID] {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// A subscriber this far behind resumes from the history instead of holding
+			// up the others.
+			s.remove(sub)
+		}
+	}
+}
+
+// Close ends every subscription, so open streams finish, and refuses new ones. The server
+// closes the stream when it starts shutting down.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for _, subs := range s.subs {
+		for sub := range subs {
+			s.remove(sub)
+		}
+	}
+	if s.pubsub != nil {
+		return s.pubsub.Close()
+	}
+	return nil
+}
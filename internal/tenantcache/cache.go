@@ -0,0 +1,167 @@
+package tenantcache
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/omniful/go_commons/config"
+	"github.com/omniful/go_commons/log"
+	cache2 "github.com/omniful/go_commons/redis_cache"
+)
+
+// FetchFunc loads a tenant's IDs from the owning service.
+type FetchFunc func(ctx context.Context, tenantID string) ([]string, error)
+
+// entry is what both tiers store. FetchedAt and FetchTook are unix nanoseconds and
+// nanoseconds, which msgpack round-trips without loss.
+type entry struct {
+	IDs       []string
+	FetchedAt int64
+	FetchTook int64
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, e.FetchedAt))
+}
+
+// Cache keeps per-tenant ID lists fetched from another service in an in-process LRU in
+// front of Redis.
+//
+// Entries are fresh for ttl and are then kept for staleFor more, to be served when the
+// upstream service is failing. Concurrent fetches for a tenant are coalesced into one,
+// and a fresh entry may be refreshed early in the background, with a probability rising
+// as it nears expiry, so that keys do not all expire under load at once.
+type Cache struct {
+	keyPrefix string
+	redis     cache2.ICache
+	fetch     FetchFunc
+	local     *lru
+	fetches   group
+
+	ttl       time.Duration
+	staleFor  time.Duration
+	localTTL  time.Duration
+	earlyBeta float64
+}
+
+// New builds a cache whose Redis keys are keyPrefix followed by the tenant ID. Tuning
+// comes from tenant_cache in config.yaml, with ttl falling back to defaultTTL.
+func New(ctx context.Context, keyPrefix string, redis cache2.ICache, fetch FetchFunc, defaultTTL time.Duration) *Cache {
+	ttl := config.GetDuration(ctx, "tenant_cache.ttl")
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	localSize := config.GetInt(ctx, "tenant_cache.local_size")
+	if localSize <= 0 {
+		localSize = 1000
+	}
+	return &Cache{
+		keyPrefix: keyPrefix,
+		redis:     redis,
+		fetch:     fetch,
+		local:     newLRU(localSize),
+		ttl:       ttl,
+		staleFor:  config.GetDuration(ctx, "tenant_cache.stale_for"),
+		localTTL:  config.GetDuration(ctx, "tenant_cache.local_ttl"),
+		earlyBeta: config.GetFloat64(ctx, "tenant_cache.early_refresh_beta"),
+	}
+}
+
+func (c *Cache) key(tenantID string) string {
+	return c.keyPrefix + tenantID
+}
+
+// Get returns the tenant's IDs. It only fails when nothing, not even a stale entry, is
+// cached and the upstream service fails.
+func (c *Cache) Get(ctx context.Context, tenantID string) ([]string, error) {
+	now := time.Now()
+	key := c.key(tenantID)
+
+	local, inLocal := c.local.get(key)
+	if inLocal && local.age(now) < c.localTTL && local.age(now) < c.ttl {
+		return local.IDs, nil
+	}
+
+	e := &entry{}
+	found, err := c.redis.Get(ctx, key, e)
+	if err != nil {
+		log.WithError(err).Error("failed to read tenant cache " + key)
+		found = false
+	}
+	if !found {
+		e, err = c.refresh(ctx, tenantID)
+		if err != nil {
+			if inLocal && local.age(now) < c.ttl+c.staleFor {
+				log.WithError(err).Error("serving stale tenant cache " + key + " from memory")
+				return local.IDs, nil
+			}
+			return nil, err
+		}
+		return e.IDs, nil
+	}
+
+	c.local.add(key, e)
+	if e.age(now) >= c.ttl {
+		fetched, err := c.refresh(ctx, tenantID)
+		if err != nil {
+			log.WithError(err).Error("serving stale tenant cache " + key)
+			return e.IDs, nil
+		}
+		return fetched.IDs, nil
+	}
+	if c.refreshEarly(e, now) {
+		go func() {
+			if _, err := c.refresh(context.WithoutCancel(ctx), tenantID); err != nil {
+				log.WithError(err).Error("early refresh of tenant cache " + key + " failed")
+			}
+		}()
+	}
+	return e.IDs, nil
+}
+
+// refreshEarly decides whether to refresh a fresh entry ahead of expiry. The chance grows
+// as the entry ages and with how long fetching it took (probabilistic early expiration,
+// "XFetch").
+func (c *Cache) refreshEarly(e *entry, now time.Time) bool {
+	if c.earlyBeta <= 0 {
+		return false
+	}
+	gap := -float64(e.FetchTook) * c.earlyBeta * math.Log(1-rand.Float64())
+	return e.age(now)+time.Duration(gap) >= c.ttl
+}
+
+// refresh fetches the tenant's IDs and stores them in both tiers. Concurrent refreshes of
+// a tenant share one fetch.
+func (c *Cache) refresh(ctx context.Context, tenantID string) (*entry, error) {
+	key := c.key(tenantID)
+	v, err := c.fetches.do(key, func() (interface{}, error) {
+		// The fetch is shared, so one caller giving up must not fail the others.
+		ctx := context.WithoutCancel(ctx)
+		start := time.Now()
+		ids, err := c.fetch(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		e := &entry{IDs: ids, FetchedAt: start.UnixNano(), FetchTook: int64(time.Since(start))}
+		if _, err := c.redis.Set(ctx, key, e, c.ttl+c.staleFor); err != nil {
+			log.WithError(err).Error("failed to write tenant cache " + key)
+		}
+		c.local.add(key, e)
+		return e, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*entry), nil
+}
+
+// Invalidate drops the tenant's entry. Other processes keep serving their in-process copy
+// for up to tenant_cache.local_ttl.
+func (c *Cache) Invalidate(ctx context.Context, tenantID string) error {
+	key := c.key(tenantID)
+	c.local.remove(key)
+	_, err := c.redis.Unlink(ctx, []string{key})
+	return err
+}
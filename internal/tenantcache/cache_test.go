@@ -0,0 +1,205 @@
+package tenantcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cache2 "github.com/omniful/go_commons/redis_cache"
+)
+
+// memoryRedis stores entries in a map in place of Redis.
+type memoryRedis struct {
+	cache2.ICache
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{entries: map[string]entry{}}
+}
+
+func (r *memoryRedis) Get(_ context.Context, key string, v interface{}) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[key]
+	if ok {
+		*v.(*entry) = e
+	}
+	return ok, nil
+}
+
+func (r *memoryRedis) Set(_ context.Context, key string, v interface{}, _ time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[key] = *v.(*entry)
+	return true, nil
+}
+
+func (r *memoryRedis) Unlink(_ context.Context, keys []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.entries, key)
+	}
+	return int64(len(keys)), nil
+}
+
+func newTestCache(redis *memoryRedis, fetch FetchFunc) *Cache {
+	return &Cache{
+		keyPrefix: "hubs:",
+		redis:     redis,
+		fetch:     fetch,
+		local:     newLRU(10),
+		ttl:       time.Minute,
+		staleFor:  time.Hour,
+		localTTL:  time.Second,
+	}
+}
+
+func TestGroupCoalescesConcurrentCalls(t *testing.T) {
+	var (
+		g       group
+		calls   int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	results := make([]interface{}, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.do("tenant-1", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return "ids", nil
+			})
+		}(i)
+	}
+	// Let the callers reach the running call before it finishes.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("got %d calls, want 1", calls)
+	}
+	for i, v := range results {
+		if v != "ids" {
+			t.Fatalf("caller %d got %v, want the shared result", i, v)
+		}
+	}
+	if _, err := g.do("tenant-1", func() (interface{}, error) { return nil, errors.New("down") }); err == nil {
+		t.Fatal("a call after the first one finished reused its result")
+	}
+}
+
+func TestGetCoalescesFetchesOnMiss(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	c := newTestCache(newMemoryRedis(), func(ctx context.Context, tenantID string) ([]string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []string{"1", "2"}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ids, err := c.Get(context.Background(), "7"); err != nil || len(ids) != 2 {
+				t.Errorf("Get: got %v, %v", ids, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("got %d fetches, want 1", calls)
+	}
+	if ids, err := c.Get(context.Background(), "7"); err != nil || len(ids) != 2 || calls != 1 {
+		t.Fatalf("cached Get: got %v, %v after %d fetches", ids, err, calls)
+	}
+}
+
+func TestGetServesStaleEntriesWhenFetchFails(t *testing.T) {
+	redis := newMemoryRedis()
+	errDown := errors.New("tenant service down")
+	c := newTestCache(redis, func(ctx context.Context, tenantID string) ([]string, error) {
+		return nil, errDown
+	})
+
+	if _, err := c.Get(context.Background(), "7"); !errors.Is(err, errDown) {
+		t.Fatalf("nothing cached: got %v, want the fetch error", err)
+	}
+
+	expired := time.Now().Add(-2 * time.Minute).UnixNano()
+	redis.entries["hubs:7"] = entry{IDs: []string{"3"}, FetchedAt: expired}
+	ids, err := c.Get(context.Background(), "7")
+	if err != nil || len(ids) != 1 || ids[0] != "3" {
+		t.Fatalf("stale entry: got %v, %v, want the stale IDs", ids, err)
+	}
+
+	// Redis lost the entry too: the in-process copy is still within staleFor.
+	delete(redis.entries, "hubs:7")
+	if ids, err := c.Get(context.Background(), "7"); err != nil || len(ids) != 1 {
+		t.Fatalf("stale in-process entry: got %v, %v, want the stale IDs", ids, err)
+	}
+}
+
+func TestGetRefreshesExpiredEntries(t *testing.T) {
+	redis := newMemoryRedis()
+	c := newTestCache(redis, func(ctx context.Context, tenantID string) ([]string, error) {
+		return []string{"4", "5"}, nil
+	})
+	redis.entries["hubs:7"] = entry{IDs: []string{"3"}, FetchedAt: time.Now().Add(-2 * time.Minute).UnixNano()}
+
+	ids, err := c.Get(context.Background(), "7")
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("got %v, %v, want the fetched IDs", ids, err)
+	}
+	if stored := redis.entries["hubs:7"]; len(stored.IDs) != 2 {
+		t.Fatalf("Redis holds %v, want the fetched IDs", stored.IDs)
+	}
+}
+
+func TestInvalidateDropsBothTiers(t *testing.T) {
+	redis := newMemoryRedis()
+	var calls int32
+	c := newTestCache(redis, func(ctx context.Context, tenantID string) ([]string, error) {
+		atomic.AddInt32(&calls, 1)
+		return []string{"1"}, nil
+	})
+	if _, err := c.Get(context.Background(), "7"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := c.Invalidate(context.Background(), "7"); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if _, err := c.Get(context.Background(), "7"); err != nil || calls != 2 {
+		t.Fatalf("Get after Invalidate: got %v after %d fetches, want a second fetch", err, calls)
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	l := newLRU(2)
+	l.add("a", &entry{})
+	l.add("b", &entry{})
+	l.get("a")
+	l.add("c", &entry{})
+
+	if _, ok := l.get("b"); ok {
+		t.Error("b was used least recently but is still cached")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := l.get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+// Signature headers sent with every delivery. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" with the subscription secret, so receivers can reject replays of
+// old requests by checking the timestamp.
+const (
+	HeaderEvent      = "X-IMS-Event"
+	HeaderEventID    = "X-IMS-Event-ID"
+	HeaderDeliveryID = "X-IMS-Delivery-ID"
+	HeaderTimestamp  = "X-IMS-Timestamp"
+	HeaderSignature  = "X-IMS-Signature"
+)
+
+// Sign returns the value of HeaderSignature for a body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Config struct {
+	BatchSize      int
+	PollInterval   time.Duration
+	RequestTimeout time.Duration
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	// DisableAfter is the number of failed attempts in a row, across deliveries, after
+	// which a subscription is disabled.
+	DisableAfter int
+}
+
+type Dispatcher struct {
+	cfg    Config
+	client *http.Client
+}
+
+func NewDispatcher(cfg Config) *Dispatcher {
+	return &Dispatcher{
+		cfg:    cfg,
+		client: deliveryClient(cfg.RequestTimeout),
+	}
+}
+
+// Run turns outbox events into deliveries and sends due deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Infof("Starting webhook dispatcher")
+	for {
+		enqueued, err := d.enqueue(ctx)
+		if err != nil {
+			log.WithError(err).Error("webhook enqueue failed")
+		}
+		sent, err := d.deliverDue(ctx)
+		if err != nil {
+			log.WithError(err).Error("webhook delivery failed")
+		}
+		if enqueued == d.cfg.BatchSize || sent == d.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.cfg.PollInterval):
+		}
+	}
+}
+
+type outboxEvent struct {
+	id         int64
+	eventID    string
+	eventType  string
+	tenantID   int64
+	occurredAt time.Time
+	data       json.RawMessage
+}
+
+// Payload is the JSON body of a delivery.
+type Payload struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	TenantID   int64           `json:"tenant_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// enqueue creates deliveries for outbox events not yet looked at, one per matching active
+// subscription. Workers running side by side split the events between them.
+func (d *Dispatcher) enqueue(ctx context.Context) (int, error) {
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_id, event_type, tenant_id, created_at, data
+		FROM outbox_events
+		WHERE webhooks_enqueued_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var events []*outboxEvent
+	for rows.Next() {
+		var (
+			e    = &outboxEvent{}
+			data []byte
+		)
+		if err := rows.Scan(&e.id, &e.eventID, &e.eventType, &e.tenantID, &e.occurredAt, &data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		e.data = data
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	subsByTenant := map[int64][]*Subscription{}
+	for _, e := range events {
+		subs, ok := subsByTenant[e.tenantID]
+		if !ok {
+			if subs, err = activeSubscriptions(ctx, tx, e.tenantID); err != nil {
+				return 0, err
+			}
+			subsByTenant[e.tenantID] = subs
+		}
+		for _, s := range subs {
+			for _, eventType := range webhookEventTypes(s, e) {
+				if err := insertDelivery(ctx, tx, s, e, eventType); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE outbox_events SET webhooks_enqueued_at = NOW() WHERE id = $1`, e.id); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), tx.Commit()
+}
+
+func activeSubscriptions(ctx context.Context, tx *sql.Tx, tenantID int64) ([]*Subscription, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE tenant_id = $1 AND active`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// webhookEventTypes maps an outbox event to the webhook events the subscription wants.
+// A stock change can be both stock.changed and stock.low.
+func webhookEventTypes(s *Subscription, e *outboxEvent) []string {
+	var types []string
+	if e.eventType != "inventory.updated" {
+		if s.subscribesTo(e.eventType) {
+			types = append(types, e.eventType)
+		}
+		return types
+	}
+
+	if s.subscribesTo(EventStockChanged) {
+		types = append(types, EventStockChanged)
+	}
+	if s.subscribesTo(EventStockLow) {
+		var change struct {
+			Qty         int64 `json:"quantity"`
+			PreviousQty int64 `json:"previous_quantity"`
+		}
+		if err := json.Unmarshal(e.data, &change); err == nil &&
+			change.Qty <= s.LowStockThreshold && change.PreviousQty > s.LowStockThreshold {
+			types = append(types, EventStockLow)
+		}
+	}
+	return types
+}
+
+func insertDelivery(ctx context.Context, tx *sql.Tx, s *Subscription, e *outboxEvent, eventType string) error {
+	payload, err := json.Marshal(Payload{
+		ID:         e.eventID,
+		Type:       eventType,
+		TenantID:   e.tenantID,
+		OccurredAt: e.occurredAt,
+		Data:       e.data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, tenant_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4, $5)`, s.ID, e.tenantID, e.eventID, eventType, payload)
+	return err
+}
+
+type dueDelivery struct {
+	*Delivery
+	url    string
+	secret string
+	// claim is the delivery's claim count once claimed by this dispatcher.
+	claim int
+}
+
+// deliverDue sends up to BatchSize due deliveries, claiming each one just before sending
+// it, and records the outcomes.
+func (d *Dispatcher) deliverDue(ctx context.Context) (int, error) {
+	sent := 0
+	for sent < d.cfg.BatchSize {
+		dd, err := d.claimDue(ctx)
+		if err != nil || dd == nil {
+			return sent, err
+		}
+		statusCode, sendErr := d.send(ctx, dd)
+		if err := d.recordAttempt(ctx, dd, statusCode, sendErr); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// claimDue claims the next due delivery by pushing its next attempt past the request
+// timeout, or returns nil when none is due. The lease covers one request: a dispatcher
+// that dies mid-send leaves the claim to expire, and the delivery is retried.
+func (d *Dispatcher) claimDue(ctx context.Context) (*dueDelivery, error) {
+	db := pg.GetClient().DB
+	lease := d.cfg.RequestTimeout * 2
+	row := db.QueryRowContext(ctx, `
+		UPDATE webhook_deliveries wd
+		SET next_attempt_at = NOW() + make_interval(secs => $1), claims = wd.claims + 1
+		FROM webhook_subscriptions s
+		WHERE wd.subscription_id = s.id AND wd.id = (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions ws ON ws.id = d.subscription_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND ws.active
+			ORDER BY d.next_attempt_at
+			LIMIT 1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING wd.id, wd.subscription_id, wd.event_id, wd.event_type, wd.payload, wd.status, wd.attempts,
+			wd.next_attempt_at, wd.last_status_code, wd.last_error, wd.replay_of, wd.created_at, wd.delivered_at,
+			s.url, s.secret, wd.claims`, lease.Seconds())
+	dd := &dueDelivery{}
+	var err error
+	dd.Delivery, err = scanDelivery(pg.WithExtraColumns(row, &dd.url, &dd.secret, &dd.claim))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dd, nil
+}
+
+// send posts the delivery and returns the response status. Any non-2xx status is an error.
+func (d *Dispatcher) send(ctx context.Context, dd *dueDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dd.url, bytes.NewReader(dd.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, dd.EventType)
+	req.Header.Set(HeaderEventID, dd.EventID)
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(dd.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(dd.secret, timestamp, dd.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait before the given retry: BaseBackoff doubled per attempt, capped
+// at MaxBackoff, with up to 20% jitter so failing receivers are not hit in lockstep.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	wait := float64(d.cfg.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if wait > float64(d.cfg.MaxBackoff) {
+		wait = float64(d.cfg.MaxBackoff)
+	}
+	return time.Duration(wait * (1 + 0.2*rand.Float64()))
+}
+
+func (d *Dispatcher) recordAttempt(ctx context.Context, dd *dueDelivery, statusCode int, sendErr error) error {
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	attempts := dd.Attempts + 1
+
+	if sendErr == nil {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE webhook_deliveries
+			SET status = $1, attempts = $2, last_status_code = $3, last_error = '', delivered_at = NOW()
+			WHERE id = $4 AND claims = $5`, DeliverySucceeded, attempts, code, dd.ID, dd.claim)
+		if err != nil {
+			return err
+		}
+		if held, err := claimHeld(res, dd); !held {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = $1`, dd.SubscriptionID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	status, nextAttempt := DeliveryPending, time.Now().Add(d.backoff(attempts))
+	if attempts >= d.cfg.MaxAttempts {
+		status = DeliveryFailed
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5
+		WHERE id = $6 AND claims = $7`, status, attempts, code, sendErr.Error(), nextAttempt, dd.ID, dd.claim)
+	if err != nil {
+		return err
+	}
+	if held, err := claimHeld(res, dd); !held {
+		return err
+	}
+
+	var disabled bool
+	err = tx.QueryRowContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = consecutive_failures + 1,
+			active = consecutive_failures + 1 < $1,
+			disabled_at = CASE WHEN consecutive_failures + 1 >= $1 THEN NOW() ELSE disabled_at END
+		WHERE id = $2
+		RETURNING NOT active`, d.cfg.DisableAfter, dd.SubscriptionID).Scan(&disabled)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		log.Warnf("Disabled webhook subscription %d after %d consecutive failures", dd.SubscriptionID, d.cfg.DisableAfter)
+	}
+	return tx.Commit()
+}
+
+// claimHeld reports whether an update of the delivery's outcome applied, i.e. whether the
+// dispatcher still held its claim. A delivery claimed again after the claim expired is
+// left to the dispatcher holding it now.
+func claimHeld(res sql.Result, dd *dueDelivery) (bool, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		log.Warnf("Webhook delivery %d was claimed again before its attempt was recorded", dd.ID)
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,316 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/omniful/ims_rohit/pkg/keyset"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+// Event types tenants can subscribe to. stock.low fires when a stock change takes a
+// SKU's quantity at a hub to or below the subscription's low-stock threshold.
+const (
+	EventStockChanged = "stock.changed"
+	EventStockLow     = "stock.low"
+	EventSKUCreated   = "sku.created"
+	EventSKUUpdated   = "sku.updated"
+	EventSKUDeleted   = "sku.deleted"
+	EventHubCreated   = "hub.created"
+	EventHubUpdated   = "hub.updated"
+	EventHubDeleted   = "hub.deleted"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+type Subscription struct {
+	ID                  int64      `json:"id"`
+	TenantID            int64      `json:"tenant_id"`
+	URL                 string     `json:"url" binding:"required,url"`
+	Secret              string     `json:"secret,omitempty"`
+	EventTypes          []string   `json:"event_types" binding:"required,min=1,dive,oneof=stock.changed stock.low sku.created sku.updated sku.deleted hub.created hub.updated hub.deleted"`
+	LowStockThreshold   int64      `json:"low_stock_threshold" binding:"gte=0"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int64      `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+const subscriptionColumns = `id, tenant_id, url, secret, event_types, low_stock_threshold, active, consecutive_failures,
+	disabled_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row rowScanner) (*Subscription, error) {
+	s := &Subscription{}
+	err := row.Scan(&s.ID, &s.TenantID, &s.URL, &s.Secret, pq.Array(&s.EventTypes), &s.LowStockThreshold, &s.Active,
+		&s.ConsecutiveFailures, &s.DisabledAt, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (s *Subscription) subscribesTo(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateSubscription stores an active subscription, generating a signing secret when the
+// caller did not provide one. The returned subscription is the only place the secret is
+// shown. URLs of hosts that are not public fail with ErrForbiddenURL.
+func CreateSubscription(ctx context.Context, tenantID int64, s *Subscription) error {
+	db := pg.GetClient().DB
+	if err := validateURL(ctx, s.URL); err != nil {
+		return err
+	}
+	if s.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+		s.Secret = secret
+	}
+	created, err := scanSubscription(db.QueryRowContext(ctx, `
+		INSERT INTO webhook_subscriptions (tenant_id, url, secret, event_types, low_stock_threshold)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+subscriptionColumns, tenantID, s.URL, s.Secret, pq.Array(s.EventTypes), s.LowStockThreshold))
+	if err != nil {
+		return err
+	}
+	*s = *created
+	return nil
+}
+
+// GetSubscription returns a subscription of the tenant without its secret.
+func GetSubscription(ctx context.Context, tenantID, id int64) (*Subscription, error) {
+	db := pg.GetClient().DB
+	s, err := scanSubscription(db.QueryRowContext(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Secret = ""
+	return s, nil
+}
+
+// ListSubscriptions returns a page of the tenant's subscriptions, newest first, without
+// their secrets.
+func ListSubscriptions(ctx context.Context, tenantID int64, page keyset.Page) ([]*Subscription, *keyset.Meta, error) {
+	db := pg.GetClient().DB
+	beforeID, err := page.Before()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where := ` WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	var total *int64
+	if beforeID == 0 {
+		total = new(int64)
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_subscriptions`+where, args...).Scan(total); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		args = append(args, beforeID)
+		where += fmt.Sprintf(` AND id < $%d`, len(args))
+	}
+
+	query := fmt.Sprintf(`SELECT `+subscriptionColumns+` FROM webhook_subscriptions%s ORDER BY id DESC LIMIT $%d`, where, len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	subs := []*Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.Secret = ""
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	subs, meta := keyset.Trim(page, subs, func(s *Subscription) int64 { return s.ID })
+	meta.Total = total
+	return subs, meta, nil
+}
+
+// UpdateSubscription changes the URL, event types, threshold and active flag. Activating
+// a subscription clears its failure count, so a subscription disabled for failing can be
+// turned back on once the receiver is fixed. The secret is kept unless a new one is given.
+// URLs of hosts that are not public fail with ErrForbiddenURL.
+func UpdateSubscription(ctx context.Context, tenantID int64, s *Subscription) error {
+	db := pg.GetClient().DB
+	if err := validateURL(ctx, s.URL); err != nil {
+		return err
+	}
+	updated, err := scanSubscription(db.QueryRowContext(ctx, `
+		UPDATE webhook_subscriptions SET
+			url = $1,
+			event_types = $2,
+			low_stock_threshold = $3,
+			secret = COALESCE(NULLIF($4, ''), secret),
+			consecutive_failures = CASE WHEN $5 AND NOT active THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE WHEN $5 THEN NULL ELSE COALESCE(disabled_at, NOW()) END,
+			active = $5,
+			updated_at = NOW()
+		WHERE id = $6 AND tenant_id = $7
+		RETURNING `+subscriptionColumns,
+		s.URL, pq.Array(s.EventTypes), s.LowStockThreshold, s.Secret, s.Active, s.ID, tenantID))
+	if err == sql.ErrNoRows {
+		return ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	updated.Secret = ""
+	*s = *updated
+	return nil
+}
+
+func DeleteSubscription(ctx context.Context, tenantID, id int64) error {
+	db := pg.GetClient().DB
+	res, err := db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+type Delivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	ReplayOf       *int64          `json:"replay_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, replay_of, created_at, delivered_at`
+
+func scanDelivery(row rowScanner) (*Delivery, error) {
+	d := &Delivery{}
+	var payload []byte
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.ReplayOf, &d.CreatedAt, &d.DeliveredAt)
+	d.Payload = payload
+	return d, err
+}
+
+// ListDeliveries returns one page of a subscription's delivery log, newest first.
+func ListDeliveries(ctx context.Context, tenantID, subscriptionID int64, status DeliveryStatus, page keyset.Page) ([]*Delivery, *keyset.Meta, error) {
+	db := pg.GetClient().DB
+	beforeID, err := page.Before()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where := ` WHERE subscription_id = $1 AND tenant_id = $2`
+	args := []interface{}{subscriptionID, tenantID}
+	if status != "" {
+		args = append(args, status)
+		where += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+
+	var total *int64
+	if beforeID == 0 {
+		total = new(int64)
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries`+where, args...).Scan(total); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		args = append(args, beforeID)
+		where += fmt.Sprintf(` AND id < $%d`, len(args))
+	}
+
+	query := fmt.Sprintf(`SELECT `+deliveryColumns+` FROM webhook_deliveries%s ORDER BY id DESC LIMIT $%d`, where, len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	deliveries, meta := keyset.Trim(page, deliveries, func(d *Delivery) int64 { return d.ID })
+	meta.Total = total
+	return deliveries, meta, nil
+}
+
+// ReplayDelivery queues a fresh copy of a delivery, whatever its outcome. The copy keeps
+// the event ID so receivers that already processed the event can dedupe it.
+func ReplayDelivery(ctx context.Context, tenantID, id int64) (*Delivery, error) {
+	db := pg.GetClient().DB
+	d, err := scanDelivery(db.QueryRowContext(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, tenant_id, event_id, event_type, payload, replay_of)
+		SELECT subscription_id, tenant_id, event_id, event_type, payload, id
+		FROM webhook_deliveries
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING `+deliveryColumns, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrDeliveryNotFound
+	}
+	return d, err
+}
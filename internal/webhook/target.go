@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenURL is returned for subscription URLs that are not http(s) URLs of public
+// hosts. Deliveries are sent from inside the network, so a URL pointing at loopback,
+// private, link-local or cloud metadata addresses would let tenants reach internal
+// services.
+var ErrForbiddenURL = errors.New("webhook url must be an http or https url of a public host")
+
+// blockedHostnames are names of internal services that resolve to blocked addresses on
+// some networks only, and are refused whatever they resolve to.
+var blockedHostnames = []string{"localhost", "metadata.google.internal", "metadata"}
+
+// sharedAddressSpace is the carrier-grade NAT range, which also holds some cloud metadata
+// endpoints.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedIP reports whether deliveries may not be sent to ip.
+func blockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip) || ip.To4() != nil && ip.To4()[0] == 0
+}
+
+// validateURL checks a subscription URL when it is stored. Its host must only resolve to
+// public addresses. The dispatcher checks the addresses again when connecting, as DNS
+// answers can change after the check.
+func validateURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return ErrForbiddenURL
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, name := range blockedHostnames {
+		if host == name || strings.HasSuffix(host, "."+name) {
+			return ErrForbiddenURL
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if blockedIP(ip) {
+			return ErrForbiddenURL
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: %s does not resolve", ErrForbiddenURL, host)
+	}
+	for _, addr := range addrs {
+		if blockedIP(addr.IP) {
+			return ErrForbiddenURL
+		}
+	}
+	return nil
+}
+
+// deliveryClient is the HTTP client of the dispatcher. It refuses to connect to blocked
+// addresses, checking the address actually dialled, after DNS resolution and on every
+// redirect, and does not go through a proxy, which would hide the address.
+func deliveryClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenURL, address)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/internal/balance"
+	"github.com/omniful/ims_rohit/internal/stockstream"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+// refreshBalances writes the committed balances of SKUs at a hub to the cache and
+// publishes them to the stock stream. It runs after the write commits; a failure is only
+// logged, and the cached value expires with its TTL.
+func refreshBalances(ctx context.Context, tenantID, hubID int64, skuIDs ...int64) {
+	caching, streaming := balance.Enabled(ctx), stockstream.Enabled(ctx)
+	if (!caching && !streaming) || len(skuIDs) == 0 {
+		return
+	}
+
+	db := pg.GetClient().DB
+	rows, err := db.QueryContext(ctx, `SELECT sku_id, quantity, reserved, version FROM inventory WHERE hub_id = $1 AND sku_id = ANY($2)`,
+		hubID, pq.Array(skuIDs))
+	if err != nil {
+		log.WithError(err).Error("failed to read balances for the cache")
+		return
+	}
+	defer rows.Close()
+
+	var (
+		balances = map[balance.Key]balance.Balance{}
+		changes  []stockstream.Change
+		now      = time.Now()
+	)
+	for rows.Next() {
+		var (
+			skuID int64
+			b     balance.Balance
+		)
+		if err := rows.Scan(&skuID, &b.Qty, &b.Reserved, &b.Version); err != nil {
+			log.WithError(err).Error("failed to read balances for the cache")
+			return
+		}
+		balances[balance.Key{TenantID: tenantID, HubID: hubID, SKUID: skuID}] = b
+		changes = append(changes, stockstream.Change{
+			HubID: hubID, SKUID: skuID, Qty: b.Qty, Reserved: b.Reserved, Version: b.Version, ChangedAt: now,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		log.WithError(err).Error("failed to read balances for the cache")
+		return
+	}
+
+	if caching {
+		if err := balance.GetCache(ctx).Set(ctx, balances); err != nil {
+			log.WithError(err).Error("failed to refresh cached balances")
+		}
+	}
+	if streaming {
+		if err := stockstream.GetStream(ctx).Publish(ctx, tenantID, hubID, changes); err != nil {
+			log.WithError(err).Error("failed to publish stock changes")
+		}
+	}
+}
+
+// cachedInventory returns the cached balances of SKUs at a hub and the SKU IDs to read
+// from the database. A cache failure turns every SKU into a miss.
+func cachedInventory(ctx context.Context, tenantID, hubID int64, skuIDs []int64) ([]*Inventory, []int64) {
+	keys := make([]balance.Key, len(skuIDs))
+	for i, id := range skuIDs {
+		keys[i] = balance.Key{TenantID: tenantID, HubID: hubID, SKUID: id}
+	}
+	found, err := balance.GetCache(ctx).Get(ctx, keys)
+	if err != nil {
+		log.WithError(err).Error("failed to read cached balances")
+		return nil, skuIDs
+	}
+
+	var (
+		invs   []*Inventory
+		misses []int64
+	)
+	for _, k := range keys {
+		b, ok := found[k]
+		if !ok {
+			misses = append(misses, k.SKUID)
+			continue
+		}
+		invs = append(invs, &Inventory{HubID: hubID, SKUID: k.SKUID, Qty: b.Qty, Reserved: b.Reserved, Version: b.Version})
+	}
+	return invs, misses
+}
+
+// cacheInventory populates the cache with balances read from the database.
+func cacheInventory(ctx context.Context, tenantID int64, invs []*Inventory) {
+	balances := make(map[balance.Key]balance.Balance, len(invs))
+	for _, inv := range invs {
+		balances[balance.Key{TenantID: tenantID, HubID: inv.HubID, SKUID: inv.SKUID}] =
+			balance.Balance{Qty: inv.Qty, Reserved: inv.Reserved, Version: inv.Version}
+	}
+	if err := balance.GetCache(ctx).Set(ctx, balances); err != nil {
+		log.WithError(err).Error("failed to populate cached balances")
+	}
+}
+
+// forgetBalances drops the cached balances of a SKU at the hubs it was stocked in, for
+// when its inventory rows are deleted along with it.
+func forgetBalances(ctx context.Context, tenantID, skuID int64, hubIDs []int64) {
+	keys := make([]balance.Key, len(hubIDs))
+	for i, hubID := range hubIDs {
+		keys[i] = balance.Key{TenantID: tenantID, HubID: hubID, SKUID: skuID}
+	}
+	dropBalances(ctx, keys)
+}
+
+// forgetHubBalances drops the cached balances of the SKUs stocked at a hub, for when its
+// inventory rows are deleted along with it.
+func forgetHubBalances(ctx context.Context, tenantID, hubID int64, skuIDs []int64) {
+	keys := make([]balance.Key, len(skuIDs))
+	for i, skuID := range skuIDs {
+		keys[i] = balance.Key{TenantID: tenantID, HubID: hubID, SKUID: skuID}
+	}
+	dropBalances(ctx, keys)
+}
+
+func dropBalances(ctx context.Context, keys []balance.Key) {
+	if !balance.Enabled(ctx) || len(keys) == 0 {
+		return
+	}
+	if err := balance.GetCache(ctx).Delete(ctx, keys); err != nil {
+		log.WithError(err).Error("failed to drop cached balances")
+	}
+}
@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+// Batch reads, for callers resolving many entities at once such as the GraphQL API. Each
+// is one query however many IDs it is given.
+
+// GetHubs returns the hubs among ids that belong to the caller's tenant, in no particular
+// order. Other IDs are left out.
+func GetHubs(ctx context.Context, ids []int64) ([]*Hub, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT `+hubColumns+` FROM hubs WHERE tenant_id = $1 AND id = ANY($2)`,
+		tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hubs []*Hub
+	for rows.Next() {
+		h, err := scanHub(rows)
+		if err != nil {
+			return nil, err
+		}
+		hubs = append(hubs, h)
+	}
+	return hubs, rows.Err()
+}
+
+// GetSKUs returns the SKUs among ids that belong to the caller's tenant, in no particular
+// order. Other IDs are left out.
+func GetSKUs(ctx context.Context, ids []int64) ([]*SKU, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT `+skuColumns+` FROM skus WHERE tenant_id = $1 AND id = ANY($2)`,
+		tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var skus []*SKU
+	for rows.Next() {
+		s, err := scanSKU(rows)
+		if err != nil {
+			return nil, err
+		}
+		skus = append(skus, s)
+	}
+	return skus, rows.Err()
+}
+
+// HubStockFilter narrows ListHubsInventory. Nil bounds are open; with no SKU IDs, every
+// SKU stocked at the hub is listed.
+type HubStockFilter struct {
+	SKUIDs []int64
+	MinQty *int64
+	MaxQty *int64
+}
+
+// ListHubsInventory returns the stock held at each of hubIDs, by hub, sorted by SKU ID
+// and limited to limit rows per hub (DefaultPageLimit when 0, at most MaxPageLimit).
+// Hubs of other tenants hold no stock.
+func ListHubsInventory(ctx context.Context, hubIDs []int64, filter HubStockFilter, limit int) (map[int64][]*Inventory, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stock := make(map[int64][]*Inventory, len(hubIDs))
+	if len(hubIDs) == 0 {
+		return stock, nil
+	}
+
+	q := &listQuery{}
+	q.where(`i.hub_id = ANY($%d)`, pq.Array(hubIDs))
+	q.where(`h.tenant_id = $%d`, tenantID)
+	if len(filter.SKUIDs) > 0 {
+		q.where(`i.sku_id = ANY($%d)`, pq.Array(filter.SKUIDs))
+	}
+	if sellerIDs, restricted := permittedSellerIDs(ctx); restricted {
+		q.where(`i.sku_id IN (SELECT id FROM skus WHERE seller_id = ANY($%d))`, pq.Array(sellerIDs))
+	}
+	if filter.MinQty != nil {
+		q.where(`i.quantity >= $%d`, *filter.MinQty)
+	}
+	if filter.MaxQty != nil {
+		q.where(`i.quantity <= $%d`, *filter.MaxQty)
+	}
+	args := append(q.args, pageLimit(limit))
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT hub_id, sku_id, quantity, reserved, version FROM (
+			SELECT i.hub_id, i.sku_id, i.quantity, i.reserved, i.version,
+				ROW_NUMBER() OVER (PARTITION BY i.hub_id ORDER BY i.sku_id) AS position
+			FROM inventory i JOIN hubs h ON h.id = i.hub_id`+q.whereClause()+`
+		) ranked
+		WHERE position <= $`+strconv.Itoa(len(args))+`
+		ORDER BY hub_id, sku_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		inv := &Inventory{}
+		if err := rows.Scan(&inv.HubID, &inv.SKUID, &inv.Qty, &inv.Reserved, &inv.Version); err != nil {
+			return nil, err
+		}
+		stock[inv.HubID] = append(stock[inv.HubID], inv)
+	}
+	return stock, rows.Err()
+}
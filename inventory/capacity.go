@@ -0,0 +1,197 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lib/pq"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+type CapacityUnit string
+
+const (
+	CapacityUnitUnits           CapacityUnit = "units"
+	CapacityUnitVolumeM3        CapacityUnit = "volume_m3"
+	CapacityUnitPalletPositions CapacityUnit = "pallet_positions"
+)
+
+// CapacityPolicy decides what happens to a receipt that would take a hub over capacity.
+type CapacityPolicy string
+
+const (
+	CapacityPolicyReject CapacityPolicy = "reject"
+	CapacityPolicyWarn   CapacityPolicy = "warn"
+)
+
+var ErrHubCapacityExceeded = errors.New("hub capacity exceeded")
+
+func applyCapacityDefaults(hub *Hub) {
+	if hub.CapacityUnit == "" {
+		hub.CapacityUnit = CapacityUnitUnits
+	}
+	if hub.CapacityPolicy == "" {
+		hub.CapacityPolicy = CapacityPolicyReject
+	}
+}
+
+// usageSQL is the per-row usage of inventory i of SKU s in the given unit. SKUs missing the
+// dimensions a unit needs count as zero and are reported as unmeasured.
+func usageSQL(unit CapacityUnit) string {
+	switch unit {
+	case CapacityUnitVolumeM3:
+		return `GREATEST(i.quantity, 0) * s.length_cm * s.width_cm * s.height_cm / 1000000.0`
+	case CapacityUnitPalletPositions:
+		return `CASE WHEN s.units_per_pallet > 0 THEN CEIL(GREATEST(i.quantity, 0)::numeric / s.units_per_pallet) ELSE 0 END`
+	default:
+		return `GREATEST(i.quantity, 0)`
+	}
+}
+
+func unmeasuredSQL(unit CapacityUnit) string {
+	switch unit {
+	case CapacityUnitVolumeM3:
+		return `s.length_cm * s.width_cm * s.height_cm = 0`
+	case CapacityUnitPalletPositions:
+		return `s.units_per_pallet = 0`
+	default:
+		return `FALSE`
+	}
+}
+
+// skuUsage is the Go counterpart of usageSQL for a single SKU quantity.
+func skuUsage(unit CapacityUnit, sku *SKU, qty int64) float64 {
+	if qty < 0 {
+		qty = 0
+	}
+	switch unit {
+	case CapacityUnitVolumeM3:
+		return float64(qty) * sku.LengthCm * sku.WidthCm * sku.HeightCm / 1000000.0
+	case CapacityUnitPalletPositions:
+		if sku.UnitsPerPallet == 0 {
+			return 0
+		}
+		return math.Ceil(float64(qty) / float64(sku.UnitsPerPallet))
+	default:
+		return float64(qty)
+	}
+}
+
+// usageByUnitSQL is usageSQL, or unmeasuredSQL with unmeasured set, for the unit of hub h.
+func usageByUnitSQL(unmeasured bool) string {
+	expr := usageSQL
+	if unmeasured {
+		expr = unmeasuredSQL
+	}
+	return fmt.Sprintf(`CASE h.capacity_unit WHEN '%s' THEN %s WHEN '%s' THEN %s ELSE %s END`,
+		CapacityUnitVolumeM3, expr(CapacityUnitVolumeM3), CapacityUnitPalletPositions, expr(CapacityUnitPalletPositions),
+		expr(CapacityUnitUnits))
+}
+
+// hubCapacity is the capacity configuration of a hub locked for a stock write.
+type hubCapacity struct {
+	capacity *float64
+	unit     CapacityUnit
+	policy   CapacityPolicy
+}
+
+// checkHubCapacity works out the hub's usage after receiving qty of the SKU. Over capacity,
+// a reject hub fails with ErrHubCapacityExceeded and a warn hub returns a warning. The hub
+// must have been locked with lockStockHub, so concurrent receipts into the same hub are
+// checked one after another.
+func checkHubCapacity(ctx context.Context, tx *sql.Tx, hub *hubCapacity, hubID, skuID, qty int64) (string, error) {
+	if hub.capacity == nil {
+		return "", nil
+	}
+
+	var used float64
+	query := fmt.Sprintf(`SELECT COALESCE(SUM(%s), 0) FROM inventory i JOIN skus s ON s.id = i.sku_id WHERE i.hub_id = $1`, usageSQL(hub.unit))
+	if err := tx.QueryRowContext(ctx, query, hubID).Scan(&used); err != nil {
+		return "", err
+	}
+
+	sku, err := scanSKU(tx.QueryRowContext(ctx, `SELECT `+skuColumns+` FROM skus WHERE id = $1`, skuID))
+	if err != nil {
+		return "", err
+	}
+	var current int64
+	err = tx.QueryRowContext(ctx, `SELECT quantity FROM inventory WHERE hub_id = $1 AND sku_id = $2`, hubID, skuID).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+
+	projected := used - skuUsage(hub.unit, sku, current) + skuUsage(hub.unit, sku, current+qty)
+	return capacityOutcome(hubID, projected, *hub.capacity, hub.unit, hub.policy)
+}
+
+// capacityOutcome applies the hub's capacity policy to its projected usage.
+func capacityOutcome(hubID int64, projected, capacity float64, unit CapacityUnit, policy CapacityPolicy) (string, error) {
+	if projected <= capacity {
+		return "", nil
+	}
+
+	msg := fmt.Sprintf("hub %d would be at %.2f of %.2f %s", hubID, projected, capacity, unit)
+	if policy == CapacityPolicyWarn {
+		return msg, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrHubCapacityExceeded, msg)
+}
+
+type HubUtilisation struct {
+	HubID          int64        `json:"hub_id"`
+	HubName        string       `json:"hub_name"`
+	Status         HubStatus    `json:"status"`
+	CapacityUnit   CapacityUnit `json:"capacity_unit"`
+	Capacity       *float64     `json:"capacity"`
+	Used           float64      `json:"used"`
+	UtilisationPct *float64     `json:"utilisation_pct"`
+	UnmeasuredSKUs int64        `json:"unmeasured_skus"`
+}
+
+// HubUtilisationReport returns, for every non-decommissioned hub of the caller's tenant that
+// the caller may access, how much of its capacity is in use. Hubs without a capacity report
+// usage only.
+func HubUtilisationReport(ctx context.Context) ([]*HubUtilisation, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usage of every hub in one pass, each in its own capacity unit. Hubs holding no stock
+	// get no inventory rows from the outer joins and report zero.
+	query := fmt.Sprintf(`
+		SELECT h.id, h.name, h.status, h.capacity_unit, h.capacity,
+			COALESCE(SUM(%s), 0), COUNT(i.sku_id) FILTER (WHERE i.quantity > 0 AND %s)
+		FROM hubs h
+		LEFT JOIN inventory i ON i.hub_id = h.id
+		LEFT JOIN skus s ON s.id = i.sku_id
+		WHERE h.tenant_id = $1 AND h.status <> $2`, usageByUnitSQL(false), usageByUnitSQL(true))
+	args := []interface{}{tenantID, HubStatusDecommissioned}
+	if hubIDs, restricted := permittedHubIDs(ctx); restricted {
+		args = append(args, pq.Array(hubIDs))
+		query += ` AND h.id = ANY($3)`
+	}
+	rows, err := db.QueryContext(ctx, query+` GROUP BY h.id ORDER BY h.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []*HubUtilisation
+	for rows.Next() {
+		u := &HubUtilisation{}
+		if err := rows.Scan(&u.HubID, &u.HubName, &u.Status, &u.CapacityUnit, &u.Capacity, &u.Used, &u.UnmeasuredSKUs); err != nil {
+			return nil, err
+		}
+		if u.Capacity != nil && *u.Capacity > 0 {
+			pct := math.Round(u.Used / *u.Capacity * 10000) / 100
+			u.UtilisationPct = &pct
+		}
+		report = append(report, u)
+	}
+	return report, rows.Err()
+}
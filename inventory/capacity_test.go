@@ -0,0 +1,87 @@
+package inventory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCapacityOutcome(t *testing.T) {
+	cases := []struct {
+		name        string
+		projected   float64
+		policy      CapacityPolicy
+		wantWarning bool
+		wantErr     bool
+	}{
+		{"within capacity", 80, CapacityPolicyReject, false, false},
+		{"at capacity", 100, CapacityPolicyReject, false, false},
+		{"over capacity, rejected", 101, CapacityPolicyReject, false, true},
+		{"over capacity, warned", 101, CapacityPolicyWarn, true, false},
+	}
+	for _, c := range cases {
+		warning, err := capacityOutcome(1, c.projected, 100, CapacityUnitUnits, c.policy)
+		if (warning != "") != c.wantWarning {
+			t.Errorf("%s: got warning %q, want one: %v", c.name, warning, c.wantWarning)
+		}
+		if c.wantErr != errors.Is(err, ErrHubCapacityExceeded) || !c.wantErr && err != nil {
+			t.Errorf("%s: got error %v, want ErrHubCapacityExceeded: %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestSKUUsage(t *testing.T) {
+	sku := &SKU{LengthCm: 50, WidthCm: 40, HeightCm: 50, UnitsPerPallet: 12}
+	cases := []struct {
+		unit CapacityUnit
+		qty  int64
+		want float64
+	}{
+		{CapacityUnitUnits, 7, 7},
+		{CapacityUnitUnits, -3, 0},
+		{CapacityUnitVolumeM3, 10, 1},
+		{CapacityUnitPalletPositions, 25, 3},
+		{CapacityUnitPalletPositions, 24, 2},
+	}
+	for _, c := range cases {
+		if got := skuUsage(c.unit, sku, c.qty); got != c.want {
+			t.Errorf("skuUsage(%s, %d) = %v, want %v", c.unit, c.qty, got, c.want)
+		}
+	}
+	if got := skuUsage(CapacityUnitPalletPositions, &SKU{}, 10); got != 0 {
+		t.Errorf("SKU without pallet size: got %v, want 0", got)
+	}
+}
+
+func TestMemoryReceiptsFollowCapacityPolicy(t *testing.T) {
+	for _, policy := range []CapacityPolicy{CapacityPolicyReject, CapacityPolicyWarn} {
+		repo := NewMemoryRepository()
+		ctx := tenantContext(1)
+		capacity := 10.0
+		hubID, err := repo.CreateHub(ctx, &Hub{Name: "Riyadh DC", Capacity: &capacity, CapacityPolicy: policy})
+		if err != nil {
+			t.Fatalf("CreateHub: %v", err)
+		}
+		skuID, err := repo.CreateSKU(ctx, &SKU{SellerID: 7, SKUCode: "MUG-01", Name: "Mug"})
+		if err != nil {
+			t.Fatalf("CreateSKU: %v", err)
+		}
+
+		if _, err := repo.UpsertInventory(ctx, hubID, skuID, 10, nil); err != nil {
+			t.Fatalf("%s: receipt up to capacity: %v", policy, err)
+		}
+		result, err := repo.UpsertInventory(ctx, hubID, skuID, 1, nil)
+		switch policy {
+		case CapacityPolicyReject:
+			if !errors.Is(err, ErrHubCapacityExceeded) {
+				t.Errorf("reject: got %v, want ErrHubCapacityExceeded", err)
+			}
+		case CapacityPolicyWarn:
+			if err != nil || len(result.Warnings) != 1 {
+				t.Errorf("warn: got %+v, %v, want one warning", result, err)
+			}
+		}
+		if _, err := repo.UpsertInventory(ctx, hubID, skuID, -2, nil); err != nil {
+			t.Errorf("%s: a decrement over capacity is not a receipt: %v", policy, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/internal/outbox"
+)
+
+// recordChange writes the audit entry and the outbox event for a change inside the
+// change's own transaction. before is nil for creates and after is nil for deletes.
+func recordChange(ctx context.Context, tx *sql.Tx, tenantID int64, entity, id string, action audit.Action, before, after interface{}) error {
+	if err := audit.Record(ctx, tx, tenantID, entity, id, action, before, after); err != nil {
+		return err
+	}
+
+	data := after
+	if action == audit.ActionDelete {
+		data = before
+	}
+	return writeEvent(ctx, tx, tenantID, entity, id, action, data)
+}
+
+// writeEvent writes the outbox event of a change. Events are keyed by entity ID so each
+// entity's events stay in order.
+func writeEvent(ctx context.Context, tx *sql.Tx, tenantID int64, entity, id string, action audit.Action, data interface{}) error {
+	return outbox.Write(ctx, tx, outbox.Event{
+		TenantID:      tenantID,
+		AggregateType: entity,
+		AggregateID:   id,
+		EventType:     fmt.Sprintf("%s.%sd", entity, action),
+		Key:           id,
+		Data:          data,
+	})
+}
+
+func recordHub(ctx context.Context, tx *sql.Tx, action audit.Action, before, after *Hub) error {
+	h := after
+	if h == nil {
+		h = before
+	}
+	id := strconv.FormatInt(h.ID, 10)
+	return recordChange(ctx, tx, h.TenantID, audit.EntityHub, id, action, before, after)
+}
+
+func recordSKU(ctx context.Context, tx *sql.Tx, action audit.Action, before, after *SKU) error {
+	s := after
+	if s == nil {
+		s = before
+	}
+	id := strconv.FormatInt(s.ID, 10)
+	return recordChange(ctx, tx, s.TenantID, audit.EntitySKU, id, action, before, after)
+}
+
+// InventoryChange is the event payload of a stock change, of the quantity on hand, the
+// quantity reserved for orders or both.
+type InventoryChange struct {
+	HubID            int64 `json:"hub_id"`
+	SKUID            int64 `json:"sku_id"`
+	Qty              int64 `json:"quantity"`
+	PreviousQty      int64 `json:"previous_quantity"`
+	Reserved         int64 `json:"reserved"`
+	PreviousReserved int64 `json:"previous_reserved"`
+}
+
+// stockLevel is the stock of a SKU at a hub before or after a change.
+type stockLevel struct {
+	qty      int64
+	reserved int64
+}
+
+// recordInventory records a stock change. Inventory rows are identified by hub and SKU.
+func recordInventory(ctx context.Context, tx *sql.Tx, tenantID, hubID, skuID int64, before, after stockLevel) error {
+	id := fmt.Sprintf("%d:%d", hubID, skuID)
+	err := audit.Record(ctx, tx, tenantID, audit.EntityInventory, id, audit.ActionUpdate,
+		&Inventory{HubID: hubID, SKUID: skuID, Qty: before.qty, Reserved: before.reserved},
+		&Inventory{HubID: hubID, SKUID: skuID, Qty: after.qty, Reserved: after.reserved})
+	if err != nil {
+		return err
+	}
+	return writeEvent(ctx, tx, tenantID, audit.EntityInventory, id, audit.ActionUpdate, &InventoryChange{
+		HubID: hubID, SKUID: skuID,
+		Qty: after.qty, PreviousQty: before.qty,
+		Reserved: after.reserved, PreviousReserved: before.reserved,
+	})
+}
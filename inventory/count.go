@@ -0,0 +1,338 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/omniful/ims_rohit/pkg/keyset"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+type CountStatus string
+
+const (
+	CountStatusPending  CountStatus = "pending"
+	CountStatusApproved CountStatus = "approved"
+	CountStatusRejected CountStatus = "rejected"
+)
+
+var (
+	ErrCountNotFound        = errors.New("inventory count not found")
+	ErrCountAlreadyResolved = errors.New("inventory count already resolved")
+)
+
+// InventoryCount is a physical stock count. The difference between CountedQty and
+// SystemQty is only applied to stock once a supervisor approves it.
+type InventoryCount struct {
+	ID         int64       `json:"id"`
+	TenantID   int64       `json:"tenant_id"`
+	HubID      int64       `json:"hub_id"`
+	SKUID      int64       `json:"sku_id"`
+	CountedQty int64       `json:"counted_qty"`
+	SystemQty  int64       `json:"system_qty"`
+	Variance   int64       `json:"variance"`
+	Status     CountStatus `json:"status"`
+	CountedBy  string      `json:"counted_by"`
+	ResolvedBy string      `json:"resolved_by,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	ResolvedAt *time.Time  `json:"resolved_at,omitempty"`
+}
+
+const countColumns = `id, tenant_id, hub_id, sku_id, counted_qty, system_qty, status, counted_by, resolved_by, created_at, resolved_at`
+
+func scanCount(row rowScanner) (*InventoryCount, error) {
+	c := &InventoryCount{}
+	err := row.Scan(&c.ID, &c.TenantID, &c.HubID, &c.SKUID, &c.CountedQty, &c.SystemQty, &c.Status,
+		&c.CountedBy, &c.ResolvedBy, &c.CreatedAt, &c.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Variance = c.CountedQty - c.SystemQty
+	return c, nil
+}
+
+func (c *InventoryCount) GetHubIDs() []string {
+	return []string{strconv.FormatInt(c.HubID, 10)}
+}
+
+func (c *InventoryCount) GetSellerIDs() []string {
+	return nil
+}
+
+// currentQuantity returns the stock of a SKU at a hub, locking the row when it exists.
+func currentQuantity(ctx context.Context, tx *sql.Tx, hubID, skuID int64) (int64, error) {
+	var qty int64
+	err := tx.QueryRowContext(ctx, `SELECT quantity FROM inventory WHERE hub_id = $1 AND sku_id = $2 FOR UPDATE`, hubID, skuID).
+		Scan(&qty)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return qty, err
+}
+
+// setQuantity overwrites the stock of a SKU at a hub inside tx, checking hub capacity when
+// the quantity goes up and the reserved quantity when it goes down; the hub must have been
+// locked with lockStockHub. counted marks the quantity of a physical count, see
+// reservedOutcome. A non-nil expectedVersion makes the write conditional on the stock's
+// current version.
+func setQuantity(ctx context.Context, tx *sql.Tx, tenantID int64, hub *hubCapacity, hubID, skuID, qty int64,
+	counted bool, expectedVersion *int64) (*UpsertResult, error) {
+	current, err := currentQuantity(ctx, tx, hubID, skuID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result   = &UpsertResult{}
+		reserved int64
+	)
+	if delta := qty - current; delta > 0 {
+		warning, err := checkHubCapacity(ctx, tx, hub, hubID, skuID, delta)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO inventory (hub_id, sku_id, quantity, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (hub_id, sku_id)
+		DO UPDATE SET quantity = EXCLUDED.quantity, version = inventory.version + 1, updated_at = NOW()
+		RETURNING reserved, version`, hubID, skuID, qty).Scan(&reserved, &result.Version)
+	if err != nil {
+		return nil, fmt.Errorf("set inventory failed: %w", err)
+	}
+	if err = checkWrittenVersion(result.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+	before, after := stockLevel{qty: current, reserved: reserved}, stockLevel{qty: qty, reserved: reserved}
+	warning, err := reservedOutcome(hubID, skuID, before, after, counted)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+	if err = recordInventory(ctx, tx, tenantID, hubID, skuID, before, after); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetInventory sets the absolute quantity of a SKU at an active hub of the caller's tenant.
+// A non-nil expectedVersion makes the write conditional on the stock's current version.
+func SetInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error) {
+	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	hub, err := lockStockHub(ctx, tx, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ensureSKUWritable(ctx, tx, tenantID, skuID); err != nil {
+		return nil, err
+	}
+
+	result, err := setQuantity(ctx, tx, tenantID, hub, hubID, skuID, qty, false, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	refreshBalances(ctx, tenantID, hubID, skuID)
+	return result, nil
+}
+
+// SubmitCount records a physical count against the current system quantity. Stock is left
+// untouched until the count is approved.
+func SubmitCount(ctx context.Context, hubID, skuID, countedQty int64, countedBy string) (*InventoryCount, error) {
+	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = ensureHubActive(ctx, tx, tenantID, hubID); err != nil {
+		return nil, err
+	}
+	if err = ensureSKUInTenant(ctx, tx, tenantID, skuID); err != nil {
+		return nil, err
+	}
+
+	var systemQty int64
+	err = tx.QueryRowContext(ctx, `SELECT quantity FROM inventory WHERE hub_id = $1 AND sku_id = $2`, hubID, skuID).
+		Scan(&systemQty)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	count, err := scanCount(tx.QueryRowContext(ctx, `
+		INSERT INTO inventory_counts (tenant_id, hub_id, sku_id, counted_qty, system_qty, counted_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+countColumns, tenantID, hubID, skuID, countedQty, systemQty, countedBy))
+	if err != nil {
+		return nil, err
+	}
+	return count, tx.Commit()
+}
+
+// GetCount returns a count of the caller's tenant, or nil if there is none.
+func GetCount(ctx context.Context, id int64) (*InventoryCount, error) {
+	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := scanCount(db.QueryRowContext(ctx,
+		`SELECT `+countColumns+` FROM inventory_counts WHERE id = $1 AND tenant_id = $2`, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return count, err
+}
+
+// ListCounts returns a page of the tenant's counts, newest first, optionally for one hub
+// and status.
+func ListCounts(ctx context.Context, hubID *int64, status CountStatus, page keyset.Page) ([]*InventoryCount, *keyset.Meta, error) {
+	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	beforeID, err := page.Before()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where := ` WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if hubID != nil {
+		args = append(args, *hubID)
+		where += fmt.Sprintf(` AND hub_id = $%d`, len(args))
+	}
+	if status != "" {
+		args = append(args, status)
+		where += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+	if hubIDs, restricted := permittedHubIDs(ctx); restricted {
+		args = append(args, pq.Array(hubIDs))
+		where += fmt.Sprintf(` AND hub_id = ANY($%d)`, len(args))
+	}
+
+	var total *int64
+	if beforeID == 0 {
+		total = new(int64)
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM inventory_counts`+where, args...).Scan(total); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		args = append(args, beforeID)
+		where += fmt.Sprintf(` AND id < $%d`, len(args))
+	}
+
+	query := fmt.Sprintf(`SELECT `+countColumns+` FROM inventory_counts%s ORDER BY id DESC LIMIT $%d`, where, len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	counts := []*InventoryCount{}
+	for rows.Next() {
+		count, err := scanCount(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	counts, meta := keyset.Trim(page, counts, func(c *InventoryCount) int64 { return c.ID })
+	meta.Total = total
+	return counts, meta, nil
+}
+
+// ResolveCount approves or rejects a pending count. Approving sets the stock to the
+// counted quantity; the variance is against stock at approval time, not at count time.
+func ResolveCount(ctx context.Context, id int64, approve bool, resolvedBy string) (*InventoryCount, *UpsertResult, error) {
+	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	count, err := scanCount(tx.QueryRowContext(ctx,
+		`SELECT `+countColumns+` FROM inventory_counts WHERE id = $1 AND tenant_id = $2 FOR UPDATE`, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrCountNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if count.Status != CountStatusPending {
+		return nil, nil, ErrCountAlreadyResolved
+	}
+
+	status := CountStatusRejected
+	result := &UpsertResult{}
+	if approve {
+		status = CountStatusApproved
+		var hub *hubCapacity
+		if hub, err = lockStockHub(ctx, tx, tenantID, count.HubID); err != nil {
+			return nil, nil, err
+		}
+		if err = ensureSKUWritable(ctx, tx, tenantID, count.SKUID); err != nil {
+			return nil, nil, err
+		}
+		if result, err = setQuantity(ctx, tx, tenantID, hub, count.HubID, count.SKUID, count.CountedQty, true, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	count, err = scanCount(tx.QueryRowContext(ctx, `
+		UPDATE inventory_counts SET status = $1, resolved_by = $2, resolved_at = NOW()
+		WHERE id = $3
+		RETURNING `+countColumns, status, resolvedBy, id))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	if approve {
+		refreshBalances(ctx, tenantID, count.HubID, count.SKUID)
+	}
+	return count, result, nil
+}
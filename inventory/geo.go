@@ -0,0 +1,219 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+const earthRadiusKm = 6371.0
+
+var ErrPostcodeNotFound = errors.New("postcode not found")
+
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude" binding:"latitude"`
+	Longitude float64 `json:"longitude" binding:"longitude"`
+}
+
+// GeoPolygon is a hub's service area as a closed ring of points; the last point joins the first.
+type GeoPolygon []GeoPoint
+
+// Value stores the polygon as JSONB, or NULL when the hub has no service area.
+func (p GeoPolygon) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan reads the polygon from a nullable JSONB column.
+func (p *GeoPolygon) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported type %T for service area", src)
+	}
+}
+
+// Contains reports whether pt lies inside the polygon, using ray casting.
+func (p GeoPolygon) Contains(pt GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Latitude > pt.Latitude) != (b.Latitude > pt.Latitude) &&
+			pt.Longitude < (b.Longitude-a.Longitude)*(pt.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// DistanceKm returns the great-circle distance between two points.
+func DistanceKm(a, b GeoPoint) float64 {
+	dLat := (b.Latitude - a.Latitude) * math.Pi / 180
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(a.Latitude*math.Pi/180)*math.Cos(b.Latitude*math.Pi/180)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// --- Postcode lookup ---
+
+// ResolvePostcode returns the centroid the caller's tenant loaded for postcode.
+func ResolvePostcode(ctx context.Context, postcode string) (*GeoPoint, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db := pg.GetClient().DB
+	pt := &GeoPoint{}
+	query := `SELECT latitude, longitude FROM postcodes WHERE tenant_id = $1 AND postcode = $2`
+	err = db.QueryRowContext(ctx, query, tenantID, normalizePostcode(postcode)).Scan(&pt.Latitude, &pt.Longitude)
+	if err == sql.ErrNoRows {
+		return nil, ErrPostcodeNotFound
+	}
+	return pt, err
+}
+
+type Postcode struct {
+	Postcode  string  `json:"postcode" binding:"required"`
+	Latitude  float64 `json:"latitude" binding:"latitude"`
+	Longitude float64 `json:"longitude" binding:"longitude"`
+}
+
+// UpsertPostcodes loads centroids for the caller's tenant; other tenants' are untouched.
+func UpsertPostcodes(ctx context.Context, postcodes []Postcode) error {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	db := pg.GetClient().DB
+	const query = `
+	INSERT INTO postcodes (tenant_id, postcode, latitude, longitude, updated_at)
+	VALUES ($1, $2, $3, $4, NOW())
+	ON CONFLICT (tenant_id, postcode)
+	DO UPDATE SET latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, updated_at = NOW()`
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, p := range postcodes {
+		if _, err := tx.ExecContext(ctx, query, tenantID, normalizePostcode(p.Postcode), p.Latitude, p.Longitude); err != nil {
+			return fmt.Errorf("upsert postcode %s failed: %w", p.Postcode, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func normalizePostcode(postcode string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(postcode), " ", ""))
+}
+
+// --- Nearest hub lookup ---
+
+type NearbyHubQuery struct {
+	Origin   GeoPoint
+	RadiusKm float64 // 0 means unbounded
+	SKUID    int64   // 0 means no stock requirement
+	MinQty   int64
+	Limit    int
+}
+
+type NearbyHub struct {
+	*Hub
+	DistanceKm float64 `json:"distance_km"`
+	// Available is the stock of the SKU asked for, less the quantity reserved for orders.
+	Available *int64 `json:"available,omitempty"`
+}
+
+// NearestHubs returns the caller's active hubs ordered by distance from the origin. Hubs
+// with a service area are only returned when the origin falls inside it, and when a SKU is
+// given only hubs with at least MinQty of it available, not reserved for orders, are
+// returned.
+func NearestHubs(ctx context.Context, q NearbyHubQuery) ([]*NearbyHub, error) {
+	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		conds = []string{"h.tenant_id = $1", "h.status = $2", "h.latitude IS NOT NULL", "h.longitude IS NOT NULL"}
+		args  = []interface{}{tenantID, HubStatusActive}
+		cols  = qualifiedHubColumns("h")
+		join  string
+		extra []interface{}
+	)
+	if hubIDs, restricted := permittedHubIDs(ctx); restricted {
+		args = append(args, pq.Array(hubIDs))
+		conds = append(conds, fmt.Sprintf("h.id = ANY($%d)", len(args)))
+	}
+	if q.SKUID > 0 {
+		args = append(args, q.SKUID, q.MinQty)
+		join = fmt.Sprintf(" JOIN inventory i ON i.hub_id = h.id AND i.sku_id = $%d AND i.quantity - i.reserved >= $%d", len(args)-1, len(args))
+		cols += ", i.quantity - i.reserved"
+	}
+	if q.RadiusKm > 0 {
+		// Bounding box prefilter; exact distances are checked below.
+		dLat := q.RadiusKm / earthRadiusKm * 180 / math.Pi
+		dLng := dLat / math.Max(math.Cos(q.Origin.Latitude*math.Pi/180), 0.01)
+		args = append(args, q.Origin.Latitude-dLat, q.Origin.Latitude+dLat, q.Origin.Longitude-dLng, q.Origin.Longitude+dLng)
+		n := len(args)
+		conds = append(conds, fmt.Sprintf("h.latitude BETWEEN $%d AND $%d AND h.longitude BETWEEN $%d AND $%d", n-3, n-2, n-1, n))
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM hubs h%s WHERE %s`, cols, join, strings.Join(conds, " AND "))
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hubs []*NearbyHub
+	for rows.Next() {
+		nh := &NearbyHub{}
+		if q.SKUID > 0 {
+			nh.Available = new(int64)
+			extra = []interface{}{nh.Available}
+		}
+		if nh.Hub, err = scanHub(pg.WithExtraColumns(rows, extra...)); err != nil {
+			return nil, err
+		}
+
+		if len(nh.ServiceArea) > 0 && !nh.ServiceArea.Contains(q.Origin) {
+			continue
+		}
+		nh.DistanceKm = DistanceKm(q.Origin, GeoPoint{Latitude: *nh.Latitude, Longitude: *nh.Longitude})
+		if q.RadiusKm > 0 && nh.DistanceKm > q.RadiusKm {
+			continue
+		}
+		hubs = append(hubs, nh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hubs, func(i, j int) bool { return hubs[i].DistanceKm < hubs[j].DistanceKm })
+	if q.Limit > 0 && len(hubs) > q.Limit {
+		hubs = hubs[:q.Limit]
+	}
+	return hubs, nil
+}
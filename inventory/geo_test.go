@@ -0,0 +1,79 @@
+package inventory
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizePostcode(t *testing.T) {
+	cases := map[string]string{
+		"sw1a 1aa":   "SW1A1AA",
+		" 12345 ":    "12345",
+		"SW1A  1AA ": "SW1A1AA",
+	}
+	for in, want := range cases {
+		if got := normalizePostcode(in); got != want {
+			t.Errorf("normalizePostcode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	riyadh := GeoPoint{Latitude: 24.7136, Longitude: 46.6753}
+	jeddah := GeoPoint{Latitude: 21.4858, Longitude: 39.1925}
+
+	if got := DistanceKm(riyadh, riyadh); got != 0 {
+		t.Errorf("distance to itself: got %v, want 0", got)
+	}
+	// Riyadh to Jeddah is about 846 km as the crow flies.
+	if got := DistanceKm(riyadh, jeddah); math.Abs(got-846) > 5 {
+		t.Errorf("Riyadh to Jeddah: got %.1f km, want about 846", got)
+	}
+	if a, b := DistanceKm(riyadh, jeddah), DistanceKm(jeddah, riyadh); a != b {
+		t.Errorf("distance is not symmetric: %v and %v", a, b)
+	}
+}
+
+func TestGeoPolygonContains(t *testing.T) {
+	square := GeoPolygon{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 10},
+		{Latitude: 10, Longitude: 10},
+		{Latitude: 10, Longitude: 0},
+	}
+	cases := []struct {
+		pt   GeoPoint
+		want bool
+	}{
+		{GeoPoint{Latitude: 5, Longitude: 5}, true},
+		{GeoPoint{Latitude: 5, Longitude: 11}, false},
+		{GeoPoint{Latitude: -1, Longitude: 5}, false},
+	}
+	for _, c := range cases {
+		if got := square.Contains(c.pt); got != c.want {
+			t.Errorf("Contains(%+v) = %v, want %v", c.pt, got, c.want)
+		}
+	}
+}
+
+func TestGeoPolygonRoundTrip(t *testing.T) {
+	if v, err := GeoPolygon(nil).Value(); v != nil || err != nil {
+		t.Fatalf("empty polygon: got %v, %v, want NULL", v, err)
+	}
+
+	area := GeoPolygon{{Latitude: 1, Longitude: 2}, {Latitude: 3, Longitude: 4}, {Latitude: 5, Longitude: 6}}
+	v, err := area.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	var scanned GeoPolygon
+	if err := scanned.Scan(v); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(scanned) != len(area) || scanned[2] != area[2] {
+		t.Fatalf("got %v, want %v", scanned, area)
+	}
+	if err := scanned.Scan(nil); err != nil || scanned != nil {
+		t.Fatalf("Scan(nil): got %v, %v, want nil", scanned, err)
+	}
+}
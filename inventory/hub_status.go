@@ -0,0 +1,145 @@
+package inventory
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type HubType string
+
+const (
+	HubTypeWarehouse HubType = "warehouse"
+	HubTypeDarkStore HubType = "dark_store"
+	HubTypeRetail    HubType = "retail"
+	HubType3PL       HubType = "3pl"
+)
+
+type HubStatus string
+
+const (
+	HubStatusActive         HubStatus = "active"
+	HubStatusPaused         HubStatus = "paused"
+	HubStatusDecommissioned HubStatus = "decommissioned"
+)
+
+const defaultHubTimezone = "UTC"
+
+var (
+	ErrHubNotFound          = errors.New("hub not found")
+	ErrHubNotActive         = errors.New("hub is not active")
+	ErrInvalidHubTransition = errors.New("invalid hub status transition")
+)
+
+// hubStatusTransitions lists the statuses a hub may move to from its current status.
+// A decommissioned hub is terminal.
+var hubStatusTransitions = map[HubStatus][]HubStatus{
+	HubStatusActive: {HubStatusPaused, HubStatusDecommissioned},
+	HubStatusPaused: {HubStatusActive, HubStatusDecommissioned},
+}
+
+func (s HubStatus) CanTransitionTo(next HubStatus) bool {
+	for _, allowed := range hubStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// OperatingWindow is a single opening window on a weekday, in the hub's local timezone.
+// Open and Close use the "15:04" layout. A Close before Open wraps past midnight: the
+// window runs from Open on Day until Close on the next day.
+type OperatingWindow struct {
+	Day   string `json:"day" binding:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Open  string `json:"open" binding:"required"`
+	Close string `json:"close" binding:"required"`
+}
+
+type OperatingHours []OperatingWindow
+
+// Value stores operating hours as JSONB.
+func (o OperatingHours) Value() (driver.Value, error) {
+	if o == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(o)
+}
+
+// Scan reads operating hours from a JSONB column.
+func (o *OperatingHours) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*o = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, o)
+	case string:
+		return json.Unmarshal([]byte(v), o)
+	default:
+		return fmt.Errorf("unsupported type %T for operating hours", src)
+	}
+}
+
+// Validate checks every window parses and does not close when it opens.
+func (o OperatingHours) Validate() error {
+	for _, w := range o {
+		open, err := time.Parse("15:04", w.Open)
+		if err != nil {
+			return fmt.Errorf("invalid open time %q for %s", w.Open, w.Day)
+		}
+		closing, err := time.Parse("15:04", w.Close)
+		if err != nil {
+			return fmt.Errorf("invalid close time %q for %s", w.Close, w.Day)
+		}
+		if closing.Equal(open) {
+			return fmt.Errorf("close time must differ from open time for %s", w.Day)
+		}
+	}
+	return nil
+}
+
+// IsOpenAt reports whether the hub is operating at t, evaluated in the hub's timezone.
+// A hub with no operating hours configured is treated as always open.
+func (h *Hub) IsOpenAt(t time.Time) bool {
+	if len(h.OperatingHours) == 0 {
+		return true
+	}
+	loc, err := time.LoadLocation(h.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	day := strings.ToLower(local.Weekday().String())
+	previousDay := strings.ToLower(local.AddDate(0, 0, -1).Weekday().String())
+	clock := local.Hour()*60 + local.Minute()
+	for _, w := range h.OperatingHours {
+		open, okOpen := minuteOfDay(w.Open)
+		closing, okClose := minuteOfDay(w.Close)
+		if !okOpen || !okClose {
+			continue
+		}
+		switch {
+		case closing > open:
+			if w.Day == day && clock >= open && clock < closing {
+				return true
+			}
+		// Overnight window: the evening of Day, then the morning after it.
+		case w.Day == day && clock >= open, w.Day == previousDay && clock < closing:
+			return true
+		}
+	}
+	return false
+}
+
+// minuteOfDay parses a "15:04" clock time into minutes after midnight.
+func minuteOfDay(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
@@ -0,0 +1,73 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHubStatusTransitions(t *testing.T) {
+	cases := []struct {
+		from, to HubStatus
+		want     bool
+	}{
+		{HubStatusActive, HubStatusPaused, true},
+		{HubStatusPaused, HubStatusActive, true},
+		{HubStatusActive, HubStatusDecommissioned, true},
+		{HubStatusDecommissioned, HubStatusActive, false},
+	}
+	for _, c := range cases {
+		if got := c.from.CanTransitionTo(c.to); got != c.want {
+			t.Errorf("%s to %s: got %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestOperatingHoursValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		hours   OperatingHours
+		wantErr bool
+	}{
+		{"same day", OperatingHours{{Day: "monday", Open: "09:00", Close: "17:00"}}, false},
+		{"past midnight", OperatingHours{{Day: "friday", Open: "22:00", Close: "06:00"}}, false},
+		{"empty window", OperatingHours{{Day: "monday", Open: "09:00", Close: "09:00"}}, true},
+		{"bad time", OperatingHours{{Day: "monday", Open: "9am", Close: "17:00"}}, true},
+	}
+	for _, c := range cases {
+		if err := c.hours.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("%s: got %v, want error %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestHubIsOpenAt(t *testing.T) {
+	hub := &Hub{
+		Timezone: "UTC",
+		OperatingHours: OperatingHours{
+			{Day: "monday", Open: "09:00", Close: "17:00"},
+			{Day: "friday", Open: "22:00", Close: "06:00"},
+		},
+	}
+	cases := []struct {
+		at   string
+		want bool
+	}{
+		{"2024-01-01T09:00:00Z", true},  // Monday, opening
+		{"2024-01-01T17:00:00Z", false}, // Monday, closing
+		{"2024-01-02T10:00:00Z", false}, // Tuesday
+		{"2024-01-05T23:30:00Z", true},  // Friday night
+		{"2024-01-06T05:59:00Z", true},  // Saturday, before Friday's window closes
+		{"2024-01-06T06:00:00Z", false}, // Saturday, after it closed
+		{"2024-01-06T22:30:00Z", false}, // Saturday night
+	}
+	for _, c := range cases {
+		at, _ := time.Parse(time.RFC3339, c.at)
+		if got := hub.IsOpenAt(at); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.at, got, c.want)
+		}
+	}
+
+	if !(&Hub{Timezone: "UTC"}).IsOpenAt(time.Now()) {
+		t.Error("a hub without operating hours should always be open")
+	}
+}
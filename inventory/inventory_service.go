@@ -3,186 +3,731 @@ package inventory
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/internal/balance"
 	"github.com/omniful/ims_rohit/pkg/pg"
 )
 
 // --- Hub CRUD ---
 
 type Hub struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Address   string    `json:"address"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             int64          `json:"id"`
+	TenantID       int64          `json:"tenant_id"`
+	Name           string         `json:"name"`
+	Address        string         `json:"address"`
+	Type           HubType        `json:"type" binding:"omitempty,oneof=warehouse dark_store retail 3pl"`
+	Status         HubStatus      `json:"status" binding:"omitempty,oneof=active paused decommissioned"`
+	Timezone       string         `json:"timezone"`
+	OperatingHours OperatingHours `json:"operating_hours" binding:"omitempty,dive"`
+	Latitude       *float64       `json:"latitude" binding:"omitempty,latitude"`
+	Longitude      *float64       `json:"longitude" binding:"omitempty,longitude"`
+	ServiceArea    GeoPolygon     `json:"service_area"`
+	ContactName    string         `json:"contact_name"`
+	ContactPhone   string         `json:"contact_phone"`
+	ContactEmail   string         `json:"contact_email" binding:"omitempty,email"`
+	Capacity       *float64       `json:"capacity" binding:"omitempty,gt=0"`
+	CapacityUnit   CapacityUnit   `json:"capacity_unit" binding:"omitempty,oneof=units volume_m3 pallet_positions"`
+	CapacityPolicy CapacityPolicy `json:"capacity_policy" binding:"omitempty,oneof=reject warn"`
+	Version        int64          `json:"version"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+const hubColumns = `id, tenant_id, name, address, type, status, timezone, operating_hours, latitude, longitude,
+	service_area, contact_name, contact_phone, contact_email, capacity, capacity_unit, capacity_policy,
+	created_at, updated_at, version`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// qualifiedHubColumns returns hubColumns prefixed with a table alias, for use in joins.
+func qualifiedHubColumns(alias string) string {
+	cols := strings.Split(hubColumns, ",")
+	for i, col := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(col)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func scanHub(row rowScanner) (*Hub, error) {
+	h := &Hub{}
+	var address sql.NullString
+	err := row.Scan(&h.ID, &h.TenantID, &h.Name, &address, &h.Type, &h.Status, &h.Timezone, &h.OperatingHours,
+		&h.Latitude, &h.Longitude, &h.ServiceArea, &h.ContactName, &h.ContactPhone, &h.ContactEmail,
+		&h.Capacity, &h.CapacityUnit, &h.CapacityPolicy, &h.CreatedAt, &h.UpdatedAt, &h.Version)
+	h.Address = address.String
+	return h, err
+}
+
+// applyHubDefaults fills in type, status and timezone when the caller left them empty.
+func applyHubDefaults(hub *Hub) {
+	if hub.Type == "" {
+		hub.Type = HubTypeWarehouse
+	}
+	if hub.Status == "" {
+		hub.Status = HubStatusActive
+	}
+	if hub.Timezone == "" {
+		hub.Timezone = defaultHubTimezone
+	}
+	applyCapacityDefaults(hub)
 }
 
 func CreateHub(ctx context.Context, hub *Hub) (int64, error) {
 	db := pg.GetClient().DB
-	query := `INSERT INTO hubs (name, address) VALUES ($1, $2) RETURNING id`
-	err := db.QueryRowContext(ctx, query, hub.Name, hub.Address).Scan(&hub.ID)
-	return hub.ID, err
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	hub.TenantID = tenantID
+	applyHubDefaults(hub)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO hubs (tenant_id, name, address, type, status, timezone, operating_hours, latitude, longitude,
+		service_area, contact_name, contact_phone, contact_email, capacity, capacity_unit, capacity_policy)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING ` + hubColumns
+	created, err := scanHub(tx.QueryRowContext(ctx, query, hub.TenantID, hub.Name, hub.Address, hub.Type, hub.Status, hub.Timezone, hub.OperatingHours,
+		hub.Latitude, hub.Longitude, hub.ServiceArea, hub.ContactName, hub.ContactPhone, hub.ContactEmail,
+		hub.Capacity, hub.CapacityUnit, hub.CapacityPolicy))
+	if err != nil {
+		return 0, err
+	}
+	if err = recordHub(ctx, tx, audit.ActionCreate, nil, created); err != nil {
+		return 0, err
+	}
+	*hub = *created
+	return hub.ID, tx.Commit()
 }
 
+// GetHub returns the hub only if it belongs to the caller's tenant; hubs of other tenants
+// are reported as missing.
 func GetHub(ctx context.Context, id int64) (*Hub, error) {
 	db := pg.GetClient().DB
-	query := `SELECT id, name, address, created_at, updated_at FROM hubs WHERE id = $1`
-	h := &Hub{}
-	err := db.QueryRowContext(ctx, query, id).Scan(&h.ID, &h.Name, &h.Address, &h.CreatedAt, &h.UpdatedAt)
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + hubColumns + ` FROM hubs WHERE id = $1 AND tenant_id = $2`
+	h, err := scanHub(db.QueryRowContext(ctx, query, id, tenantID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return h, err
 }
 
-func UpdateHub(ctx context.Context, hub *Hub) error {
+// UpdateHub updates the operating attributes of a hub. Status changes go through UpdateHubStatus.
+// A non-zero expectedVersion makes the update conditional on the hub's current version.
+// On success hub holds the stored hub.
+func UpdateHub(ctx context.Context, hub *Hub, expectedVersion int64) error {
 	db := pg.GetClient().DB
-	query := `UPDATE hubs SET name = $1, address = $2, updated_at = NOW() WHERE id = $3`
-	_, err := db.ExecContext(ctx, query, hub.Name, hub.Address, hub.ID)
-	return err
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	hub.TenantID = tenantID
+	if hub.Type == "" {
+		hub.Type = HubTypeWarehouse
+	}
+	if hub.Timezone == "" {
+		hub.Timezone = defaultHubTimezone
+	}
+	applyCapacityDefaults(hub)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockHub(ctx, tx, tenantID, hub.ID)
+	if err != nil {
+		return err
+	}
+	if err = checkVersion(before.Version, expectedVersion); err != nil {
+		return err
+	}
+	query := `UPDATE hubs SET name = $1, address = $2, type = $3, timezone = $4, operating_hours = $5,
+		latitude = $6, longitude = $7, service_area = $8, contact_name = $9, contact_phone = $10, contact_email = $11,
+		capacity = $12, capacity_unit = $13, capacity_policy = $14, version = version + 1, updated_at = NOW()
+		WHERE id = $15 RETURNING ` + hubColumns
+	after, err := scanHub(tx.QueryRowContext(ctx, query, hub.Name, hub.Address, hub.Type, hub.Timezone, hub.OperatingHours,
+		hub.Latitude, hub.Longitude, hub.ServiceArea, hub.ContactName, hub.ContactPhone, hub.ContactEmail,
+		hub.Capacity, hub.CapacityUnit, hub.CapacityPolicy, hub.ID))
+	if err != nil {
+		return err
+	}
+	if err = recordHub(ctx, tx, audit.ActionUpdate, before, after); err != nil {
+		return err
+	}
+	*hub = *after
+	return tx.Commit()
 }
 
-func DeleteHub(ctx context.Context, id int64) error {
+// lockHub loads a hub of the tenant for update, or fails with ErrHubNotFound.
+func lockHub(ctx context.Context, tx *sql.Tx, tenantID, id int64) (*Hub, error) {
+	h, err := scanHub(tx.QueryRowContext(ctx, `SELECT `+hubColumns+` FROM hubs WHERE id = $1 AND tenant_id = $2 FOR UPDATE`, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrHubNotFound
+	}
+	return h, err
+}
+
+// UpdateHubStatus moves a hub through its lifecycle, rejecting transitions not allowed
+// from the hub's current status. A non-zero expectedVersion makes the change conditional
+// on the hub's current version.
+func UpdateHubStatus(ctx context.Context, id int64, status HubStatus, expectedVersion int64) error {
 	db := pg.GetClient().DB
-	query := `DELETE FROM hubs WHERE id = $1`
-	_, err := db.ExecContext(ctx, query, id)
-	return err
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockHub(ctx, tx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err = checkVersion(before.Version, expectedVersion); err != nil {
+		return err
+	}
+	if before.Status == status {
+		return nil
+	}
+	if !before.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidHubTransition, before.Status, status)
+	}
+
+	after, err := scanHub(tx.QueryRowContext(ctx, `UPDATE hubs SET status = $1, version = version + 1, updated_at = NOW() WHERE id = $2 RETURNING `+hubColumns,
+		status, id))
+	if err != nil {
+		return err
+	}
+	if err = recordHub(ctx, tx, audit.ActionUpdate, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func ListHubs(ctx context.Context) ([]*Hub, error) {
+// DeleteHub deletes a hub and its stock. A non-zero expectedVersion makes the delete
+// conditional on the hub's current version.
+func DeleteHub(ctx context.Context, id int64, expectedVersion int64) error {
 	db := pg.GetClient().DB
-	query := `SELECT id, name, address, created_at, updated_at FROM hubs`
-	rows, err := db.QueryContext(ctx, query)
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockHub(ctx, tx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err = checkVersion(before.Version, expectedVersion); err != nil {
+		return err
+	}
+	// The hub's inventory rows go with it; note what it stocked to drop those balances
+	// from the cache.
+	stockedSKUIDs, err := hubSKUIDs(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM hubs WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if err = recordHub(ctx, tx, audit.ActionDelete, before, nil); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	forgetHubBalances(ctx, before.TenantID, before.ID, stockedSKUIDs)
+	return nil
+}
+
+func hubSKUIDs(ctx context.Context, tx *sql.Tx, hubID int64) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT sku_id FROM inventory WHERE hub_id = $1`, hubID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var hubs []*Hub
+
+	var skuIDs []int64
 	for rows.Next() {
-		h := &Hub{}
-		if err := rows.Scan(&h.ID, &h.Name, &h.Address, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		var skuID int64
+		if err := rows.Scan(&skuID); err != nil {
 			return nil, err
 		}
+		skuIDs = append(skuIDs, skuID)
+	}
+	return skuIDs, rows.Err()
+}
+
+// HubFilter narrows ListHubs. With no statuses, decommissioned hubs are left out.
+type HubFilter struct {
+	Statuses   []HubStatus
+	NamePrefix string
+	Created    TimeRange
+	Updated    TimeRange
+}
+
+var hubSortFields = map[string]sortField{
+	"id":         {"id", sortInt},
+	"name":       {"name", sortText},
+	"created_at": {"created_at", sortTime},
+	"updated_at": {"updated_at", sortTime},
+}
+
+func hubSortValue(h *Hub, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return h.Name
+	case "created_at":
+		return h.CreatedAt
+	case "updated_at":
+		return h.UpdatedAt
+	default:
+		return h.ID
+	}
+}
+
+func statusStrings(statuses []HubStatus) []string {
+	out := make([]string, len(statuses))
+	for i, s := range statuses {
+		out[i] = string(s)
+	}
+	return out
+}
+
+// ListHubs returns a page of the caller's tenant hubs matching the filter, limited to the
+// hubs the caller may access.
+func ListHubs(ctx context.Context, filter HubFilter, page Page) ([]*Hub, *PageMeta, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	field, afterValue, afterID, err := pageParams(&page, hubSortFields, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := &listQuery{}
+	q.where(`tenant_id = $%d`, tenantID)
+	if hubIDs, restricted := permittedHubIDs(ctx); restricted {
+		q.where(`id = ANY($%d)`, pq.Array(hubIDs))
+	}
+	if len(filter.Statuses) == 0 {
+		q.where(`status <> $%d`, HubStatusDecommissioned)
+	} else {
+		q.where(`status = ANY($%d)`, pq.Array(statusStrings(filter.Statuses)))
+	}
+	q.namePrefix(`name`, filter.NamePrefix)
+	q.timeRange(`created_at`, filter.Created)
+	q.timeRange(`updated_at`, filter.Updated)
+
+	meta := newPageMeta(page)
+	if afterValue == nil {
+		var total int64
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM hubs`+q.whereClause(), q.args...).Scan(&total); err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	clause, args := q.page(page, field, "id", afterValue, afterID)
+	rows, err := db.QueryContext(ctx, `SELECT `+hubColumns+` FROM hubs`+clause, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var hubs []*Hub
+	for rows.Next() {
+		h, err := scanHub(rows)
+		if err != nil {
+			return nil, nil, err
+		}
 		hubs = append(hubs, h)
 	}
-	return hubs, nil
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(hubs) > page.Limit {
+		hubs = hubs[:page.Limit]
+		last := hubs[page.Limit-1]
+		meta.NextCursor = pageCursor(page, field, hubSortValue(last, page.SortBy), last.ID)
+	}
+	return hubs, meta, nil
+}
+
+// ensureHubActive share-locks the hub row for the rest of the transaction and fails unless
+// the hub belongs to the tenant and is active, so stock cannot be written to paused or
+// decommissioned hubs. Writes that check the hub's capacity use lockStockHub instead.
+func ensureHubActive(ctx context.Context, tx *sql.Tx, tenantID, hubID int64) error {
+	_, err := activeHub(ctx, tx, tenantID, hubID, "FOR SHARE")
+	return err
+}
+
+// lockStockHub is ensureHubActive for writes that may check the hub's capacity: it locks
+// the hub row FOR UPDATE straight away and returns its capacity. Upgrading a share lock
+// later would deadlock two concurrent receipts into the same hub.
+func lockStockHub(ctx context.Context, tx *sql.Tx, tenantID, hubID int64) (*hubCapacity, error) {
+	return activeHub(ctx, tx, tenantID, hubID, "FOR UPDATE")
+}
+
+func activeHub(ctx context.Context, tx *sql.Tx, tenantID, hubID int64, lock string) (*hubCapacity, error) {
+	var (
+		status HubStatus
+		hub    = &hubCapacity{}
+	)
+	err := tx.QueryRowContext(ctx, `SELECT status, capacity, capacity_unit, capacity_policy FROM hubs
+		WHERE id = $1 AND tenant_id = $2 `+lock, hubID, tenantID).Scan(&status, &hub.capacity, &hub.unit, &hub.policy)
+	if err == sql.ErrNoRows {
+		return nil, ErrHubNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != HubStatusActive {
+		return nil, fmt.Errorf("%w: hub %d is %s", ErrHubNotActive, hubID, status)
+	}
+	return hub, nil
 }
 
 // --- SKU CRUD & Filtering ---
 
 type SKU struct {
-	ID        int64     `json:"id"`
-	TenantID  int64     `json:"tenant_id"`
-	SellerID  int64     `json:"seller_id"`
-	SKUCode   string    `json:"sku_code"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             int64     `json:"id"`
+	TenantID       int64     `json:"tenant_id"`
+	SellerID       int64     `json:"seller_id"`
+	SKUCode        string    `json:"sku_code"`
+	Name           string    `json:"name"`
+	LengthCm       float64   `json:"length_cm" binding:"gte=0"`
+	WidthCm        float64   `json:"width_cm" binding:"gte=0"`
+	HeightCm       float64   `json:"height_cm" binding:"gte=0"`
+	UnitsPerPallet int64     `json:"units_per_pallet" binding:"gte=0"`
+	Version        int64     `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+const skuColumns = `id, tenant_id, seller_id, sku_code, name, length_cm, width_cm, height_cm, units_per_pallet,
+	created_at, updated_at, version`
+
+func scanSKU(row rowScanner) (*SKU, error) {
+	s := &SKU{}
+	err := row.Scan(&s.ID, &s.TenantID, &s.SellerID, &s.SKUCode, &s.Name, &s.LengthCm, &s.WidthCm, &s.HeightCm,
+		&s.UnitsPerPallet, &s.CreatedAt, &s.UpdatedAt, &s.Version)
+	return s, err
 }
 
 func CreateSKU(ctx context.Context, sku *SKU) (int64, error) {
 	db := pg.GetClient().DB
-	query := `INSERT INTO skus (tenant_id, seller_id, sku_code, name) VALUES ($1, $2, $3, $4) RETURNING id`
-	err := db.QueryRowContext(ctx, query, sku.TenantID, sku.SellerID, sku.SKUCode, sku.Name).Scan(&sku.ID)
-	return sku.ID, err
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sku.TenantID = tenantID
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO skus (tenant_id, seller_id, sku_code, name, length_cm, width_cm, height_cm, units_per_pallet)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + skuColumns
+	created, err := scanSKU(tx.QueryRowContext(ctx, query, sku.TenantID, sku.SellerID, sku.SKUCode, sku.Name,
+		sku.LengthCm, sku.WidthCm, sku.HeightCm, sku.UnitsPerPallet))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return 0, ErrDuplicateSKUCode
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err = recordSKU(ctx, tx, audit.ActionCreate, nil, created); err != nil {
+		return 0, err
+	}
+	sku.ID = created.ID
+	return sku.ID, tx.Commit()
 }
 
+// GetSKU returns the tenant's SKU, or nil when the tenant has no such SKU.
 func GetSKU(ctx context.Context, id int64) (*SKU, error) {
 	db := pg.GetClient().DB
-	query := `SELECT id, tenant_id, seller_id, sku_code, name, created_at, updated_at FROM skus WHERE id = $1`
-	s := &SKU{}
-	err := db.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.TenantID, &s.SellerID, &s.SKUCode, &s.Name, &s.CreatedAt, &s.UpdatedAt)
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + skuColumns + ` FROM skus WHERE id = $1 AND tenant_id = $2`
+	s, err := scanSKU(db.QueryRowContext(ctx, query, id, tenantID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return s, err
 }
 
-func UpdateSKU(ctx context.Context, sku *SKU) error {
+// UpdateSKU updates a SKU's name and dimensions. A non-zero expectedVersion makes the
+// update conditional on the SKU's current version. On success sku.Version is the new
+// version.
+func UpdateSKU(ctx context.Context, sku *SKU, expectedVersion int64) error {
 	db := pg.GetClient().DB
-	query := `UPDATE skus SET name = $1, updated_at = NOW() WHERE id = $2`
-	_, err := db.ExecContext(ctx, query, sku.Name, sku.ID)
-	return err
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockSKU(ctx, tx, tenantID, sku.ID)
+	if err != nil {
+		return err
+	}
+	if err = checkVersion(before.Version, expectedVersion); err != nil {
+		return err
+	}
+	query := `UPDATE skus SET name = $1, length_cm = $2, width_cm = $3, height_cm = $4, units_per_pallet = $5,
+		version = version + 1, updated_at = NOW() WHERE id = $6 AND tenant_id = $7 RETURNING ` + skuColumns
+	after, err := scanSKU(tx.QueryRowContext(ctx, query, sku.Name, sku.LengthCm, sku.WidthCm, sku.HeightCm, sku.UnitsPerPallet,
+		sku.ID, tenantID))
+	if err != nil {
+		return err
+	}
+	if err = recordSKU(ctx, tx, audit.ActionUpdate, before, after); err != nil {
+		return err
+	}
+	sku.Version = after.Version
+	return tx.Commit()
 }
 
-func DeleteSKU(ctx context.Context, id int64) error {
-	db := pg.GetClient().DB
-	query := `DELETE FROM skus WHERE id = $1`
-	_, err := db.ExecContext(ctx, query, id)
-	return err
+// lockSKU loads the tenant's SKU for update, or fails with ErrSKUNotFound.
+func lockSKU(ctx context.Context, tx *sql.Tx, tenantID, id int64) (*SKU, error) {
+	s, err := scanSKU(tx.QueryRowContext(ctx, `SELECT `+skuColumns+` FROM skus WHERE id = $1 AND tenant_id = $2 FOR UPDATE`,
+		id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrSKUNotFound
+	}
+	return s, err
 }
 
-func ListSKUs(ctx context.Context, tenantID, sellerID *int64, skuCodes []string) ([]*SKU, error) {
+// DeleteSKU deletes a SKU and its stock. A non-zero expectedVersion makes the delete
+// conditional on the SKU's current version.
+func DeleteSKU(ctx context.Context, id int64, expectedVersion int64) error {
 	db := pg.GetClient().DB
-	var (
-		conds  []string
-		args   []interface{}
-		argIdx = 1
-	)
-	if tenantID != nil {
-		conds = append(conds, fmt.Sprintf("tenant_id = $%d", argIdx))
-		args = append(args, *tenantID)
-		argIdx++
-	}
-	if sellerID != nil {
-		conds = append(conds, fmt.Sprintf("seller_id = $%d", argIdx))
-		args = append(args, *sellerID)
-		argIdx++
-	}
-	if len(skuCodes) > 0 {
-		placeholders := []string{}
-		for i := range skuCodes {
-			placeholders = append(placeholders, fmt.Sprintf("$%d", argIdx))
-			args = append(args, skuCodes[i])
-			argIdx++
-		}
-		conds = append(conds, fmt.Sprintf("sku_code IN (%s)", strings.Join(placeholders, ",")))
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
 	}
-	query := `SELECT id, tenant_id, seller_id, sku_code, name, created_at, updated_at FROM skus`
-	if len(conds) > 0 {
-		query += " WHERE " + strings.Join(conds, " AND ")
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	rows, err := db.QueryContext(ctx, query, args...)
+	defer tx.Rollback()
+
+	before, err := lockSKU(ctx, tx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err = checkVersion(before.Version, expectedVersion); err != nil {
+		return err
+	}
+	// The SKU's inventory rows go with it; note where it was stocked to drop those balances
+	// from the cache.
+	stockedHubIDs, err := skuHubIDs(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM skus WHERE id = $1 AND tenant_id = $2`, id, tenantID); err != nil {
+		return err
+	}
+	if err = recordSKU(ctx, tx, audit.ActionDelete, before, nil); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	forgetBalances(ctx, before.TenantID, before.ID, stockedHubIDs)
+	return nil
+}
+
+func skuHubIDs(ctx context.Context, tx *sql.Tx, skuID int64) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT hub_id FROM inventory WHERE sku_id = $1`, skuID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var skus []*SKU
+
+	var hubIDs []int64
 	for rows.Next() {
-		s := &SKU{}
-		if err := rows.Scan(&s.ID, &s.TenantID, &s.SellerID, &s.SKUCode, &s.Name, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		var hubID int64
+		if err := rows.Scan(&hubID); err != nil {
 			return nil, err
 		}
+		hubIDs = append(hubIDs, hubID)
+	}
+	return hubIDs, rows.Err()
+}
+
+// SKUFilter narrows ListSKUs. SellerID is optional.
+type SKUFilter struct {
+	SellerID   *int64
+	SKUCodes   []string
+	NamePrefix string
+	Created    TimeRange
+	Updated    TimeRange
+}
+
+var skuSortFields = map[string]sortField{
+	"id":         {"id", sortInt},
+	"name":       {"name", sortText},
+	"sku_code":   {"sku_code", sortText},
+	"created_at": {"created_at", sortTime},
+	"updated_at": {"updated_at", sortTime},
+}
+
+func skuSortValue(s *SKU, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return s.Name
+	case "sku_code":
+		return s.SKUCode
+	case "created_at":
+		return s.CreatedAt
+	case "updated_at":
+		return s.UpdatedAt
+	default:
+		return s.ID
+	}
+}
+
+// ListSKUs returns a page of the caller's tenant's SKUs matching the filter, limited to the
+// sellers the caller may access.
+func ListSKUs(ctx context.Context, filter SKUFilter, page Page) ([]*SKU, *PageMeta, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	field, afterValue, afterID, err := pageParams(&page, skuSortFields, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := &listQuery{}
+	q.where(`tenant_id = $%d`, tenantID)
+	if filter.SellerID != nil {
+		q.where(`seller_id = $%d`, *filter.SellerID)
+	}
+	if sellerIDs, restricted := permittedSellerIDs(ctx); restricted {
+		q.where(`seller_id = ANY($%d)`, pq.Array(sellerIDs))
+	}
+	if len(filter.SKUCodes) > 0 {
+		q.where(`sku_code = ANY($%d)`, pq.Array(filter.SKUCodes))
+	}
+	q.namePrefix(`name`, filter.NamePrefix)
+	q.timeRange(`created_at`, filter.Created)
+	q.timeRange(`updated_at`, filter.Updated)
+
+	meta := newPageMeta(page)
+	if afterValue == nil {
+		var total int64
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM skus`+q.whereClause(), q.args...).Scan(&total); err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	clause, args := q.page(page, field, "id", afterValue, afterID)
+	rows, err := db.QueryContext(ctx, `SELECT `+skuColumns+` FROM skus`+clause, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var skus []*SKU
+	for rows.Next() {
+		s, err := scanSKU(rows)
+		if err != nil {
+			return nil, nil, err
+		}
 		skus = append(skus, s)
 	}
-	return skus, nil
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(skus) > page.Limit {
+		skus = skus[:page.Limit]
+		last := skus[page.Limit-1]
+		meta.NextCursor = pageCursor(page, field, skuSortValue(last, page.SortBy), last.ID)
+	}
+	return skus, meta, nil
 }
 
 // --- Inventory APIs ---
 
 type Inventory struct {
-	HubID int64 `json:"hub_id"`
-	SKUID int64 `json:"sku_id"`
-	Qty   int64 `json:"quantity"`
+	HubID    int64 `json:"hub_id"`
+	SKUID    int64 `json:"sku_id"`
+	Qty      int64 `json:"quantity"`
+	Reserved int64 `json:"reserved"`
+	// Version is 0 while the SKU has never been stocked at the hub.
+	Version int64 `json:"version"`
 }
 
-func UpsertInventory(ctx context.Context, hubID, skuID, qty int64) error {
+// UpsertResult carries the new version of the written stock and non-fatal outcomes of
+// the write, such as capacity warnings.
+type UpsertResult struct {
+	Version  int64    `json:"version"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// UpsertInventory adds qty, which may be negative, to the SKU's stock at the hub. A non-nil
+// expectedVersion makes the write conditional on the stock's current version, 0 meaning
+// the SKU was never stocked there.
+func UpsertInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error) {
 	const query = `
 	INSERT INTO inventory (hub_id, sku_id, quantity, updated_at)
 	VALUES ($1, $2, $3, NOW())
 	ON CONFLICT (hub_id, sku_id)
 	DO UPDATE 
 	SET quantity = inventory.quantity + EXCLUDED.quantity,
-	    updated_at = NOW();
+	    version = inventory.version + 1,
+	    updated_at = NOW()
+	RETURNING quantity, reserved, version;
 	`
 	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	defer func() {
@@ -192,49 +737,112 @@ func UpsertInventory(ctx context.Context, hubID, skuID, qty int64) error {
 		}
 	}()
 
-	_, err = tx.ExecContext(ctx, query, hubID, skuID, qty)
+	hub, err := lockStockHub(ctx, tx, tenantID, hubID)
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("upsert inventory failed: %w", err)
+		return nil, err
 	}
-	return nil
+
+	if err = ensureSKUWritable(ctx, tx, tenantID, skuID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	result := &UpsertResult{}
+	if qty > 0 {
+		warning, err := checkHubCapacity(ctx, tx, hub, hubID, skuID, qty)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	var after stockLevel
+	err = tx.QueryRowContext(ctx, query, hubID, skuID, qty).Scan(&after.qty, &after.reserved, &result.Version)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("upsert inventory failed: %w", err)
+	}
+	if err = checkWrittenVersion(result.Version, expectedVersion); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	before := stockLevel{qty: after.qty - qty, reserved: after.reserved}
+	if _, err = reservedOutcome(hubID, skuID, before, after, false); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = recordInventory(ctx, tx, tenantID, hubID, skuID, before, after); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	refreshBalances(ctx, tenantID, hubID, skuID)
+	return result, nil
 }
 
-// View inventory for a hub and list of SKUs. Missing entries default to 0.
+// ViewInventory returns the stock of SKUs at a hub, with SKUs never stocked there at 0.
+// ListInventory pages through all of a hub's stock instead.
+// The hub and SKUs must belong to the caller's tenant. With features.enable_cache on,
+// balances are read through the balance cache.
 func ViewInventory(ctx context.Context, hubID int64, skuIDs []int64) ([]*Inventory, error) {
-	db := pg.GetClient().DB
 	var (
-		rows *sql.Rows
-		err  error
-		invs []*Inventory
+		invs     []*Inventory
+		cached   []*Inventory
+		useCache = balance.Enabled(ctx)
 	)
+	// Reads go to a replica, except those populating the cache: a lagging replica could
+	// pin an old balance there until the SKU is next written.
+	db := pg.GetClient().Reader()
+	if useCache {
+		db = pg.GetClient().DB
+	}
 
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureHubInTenant(ctx, db, tenantID, hubID); err != nil {
+		return nil, err
+	}
+	if skuIDs, err = permittedSKUs(ctx, db, tenantID, skuIDs); err != nil {
+		return nil, err
+	}
 	if len(skuIDs) == 0 {
-		// Return all inventory for the hub
-		const query = `SELECT sku_id, quantity FROM inventory WHERE hub_id = $1`
-		rows, err = db.QueryContext(ctx, query, hubID)
-	} else {
-		// Return inventory for specific SKUs, defaulting to 0 if missing
-		placeholders := make([]string, len(skuIDs))
-		args := make([]interface{}, 0, len(skuIDs)+1)
-		args = append(args, hubID)
-
-		for i, id := range skuIDs {
-			placeholders[i] = fmt.Sprintf("$%d", i+2)
-			args = append(args, id)
+		return nil, nil
+	}
+
+	if useCache {
+		cached, skuIDs = cachedInventory(ctx, tenantID, hubID, skuIDs)
+		if len(skuIDs) == 0 {
+			return cached, nil
 		}
+	}
 
-		query := fmt.Sprintf(`
-			SELECT s.id, COALESCE(i.quantity, 0)
-			FROM skus s
-			LEFT JOIN inventory i 
-				ON i.sku_id = s.id AND i.hub_id = $1
-			WHERE s.id IN (%s)
-		`, strings.Join(placeholders, ","))
+	// Return inventory for specific SKUs, defaulting to 0 if missing
+	placeholders := make([]string, len(skuIDs))
+	args := make([]interface{}, 0, len(skuIDs)+2)
+	args = append(args, hubID, tenantID)
 
-		rows, err = db.QueryContext(ctx, query, args...)
+	for i, id := range skuIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args = append(args, id)
 	}
 
+	query := fmt.Sprintf(`
+		SELECT s.id, COALESCE(i.quantity, 0), COALESCE(i.reserved, 0), COALESCE(i.version, 0)
+		FROM skus s
+		LEFT JOIN inventory i 
+			ON i.sku_id = s.id AND i.hub_id = $1
+		WHERE s.tenant_id = $2 AND s.id IN (%s)
+	`, strings.Join(placeholders, ","))
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -242,7 +850,7 @@ func ViewInventory(ctx context.Context, hubID int64, skuIDs []int64) ([]*Invento
 
 	for rows.Next() {
 		inv := &Inventory{HubID: hubID}
-		if err := rows.Scan(&inv.SKUID, &inv.Qty); err != nil {
+		if err := rows.Scan(&inv.SKUID, &inv.Qty, &inv.Reserved, &inv.Version); err != nil {
 			return nil, err
 		}
 		invs = append(invs, inv)
@@ -252,25 +860,145 @@ func ViewInventory(ctx context.Context, hubID int64, skuIDs []int64) ([]*Invento
 		return nil, err
 	}
 
+	if useCache {
+		cacheInventory(ctx, tenantID, invs)
+		invs = append(cached, invs...)
+	}
 	return invs, nil
 }
 
-// CheckSKUsExistence checks which SKUs from the given list exist in the database.
-// Returns a map of skuID to bool (true if exists), and a slice of invalid (non-existent) skuIDs.
+// ensureHubInTenant fails with ErrHubNotFound unless the hub belongs to the tenant.
+func ensureHubInTenant(ctx context.Context, db *sql.DB, tenantID, hubID int64) error {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM hubs WHERE id = $1 AND tenant_id = $2)`, hubID, tenantID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrHubNotFound
+	}
+	return nil
+}
+
+// InventoryFilter narrows ListInventory. Nil bounds are open.
+type InventoryFilter struct {
+	MinQty  *int64
+	MaxQty  *int64
+	Updated TimeRange
+}
+
+var inventorySortFields = map[string]sortField{
+	"sku_id":     {"i.sku_id", sortInt},
+	"quantity":   {"i.quantity", sortInt},
+	"updated_at": {"i.updated_at", sortTime},
+}
+
+// ListInventory returns a page of the stock held at a hub of the caller's tenant.
+func ListInventory(ctx context.Context, hubID int64, filter InventoryFilter, page Page) ([]*Inventory, *PageMeta, error) {
+	db := pg.GetClient().Reader()
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	field, afterValue, afterID, err := pageParams(&page, inventorySortFields, "sku_id")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ensureHubInTenant(ctx, db, tenantID, hubID); err != nil {
+		return nil, nil, err
+	}
+
+	q := &listQuery{}
+	q.where(`i.hub_id = $%d`, hubID)
+	q.where(`s.tenant_id = $%d`, tenantID)
+	if sellerIDs, restricted := permittedSellerIDs(ctx); restricted {
+		q.where(`s.seller_id = ANY($%d)`, pq.Array(sellerIDs))
+	}
+	if filter.MinQty != nil {
+		q.where(`i.quantity >= $%d`, *filter.MinQty)
+	}
+	if filter.MaxQty != nil {
+		q.where(`i.quantity <= $%d`, *filter.MaxQty)
+	}
+	q.timeRange(`i.updated_at`, filter.Updated)
+
+	const from = ` FROM inventory i JOIN skus s ON s.id = i.sku_id`
+	meta := newPageMeta(page)
+	if afterValue == nil {
+		var total int64
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*)`+from+q.whereClause(), q.args...).Scan(&total); err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	clause, args := q.page(page, field, "i.sku_id", afterValue, afterID)
+	rows, err := db.QueryContext(ctx, `SELECT i.sku_id, i.quantity, i.reserved, i.version, i.updated_at`+from+clause, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var (
+		invs      []*Inventory
+		updatedAt []time.Time
+	)
+	for rows.Next() {
+		inv := &Inventory{HubID: hubID}
+		var updated time.Time
+		if err := rows.Scan(&inv.SKUID, &inv.Qty, &inv.Reserved, &inv.Version, &updated); err != nil {
+			return nil, nil, err
+		}
+		invs = append(invs, inv)
+		updatedAt = append(updatedAt, updated)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(invs) > page.Limit {
+		invs = invs[:page.Limit]
+		last := invs[page.Limit-1]
+		meta.NextCursor = pageCursor(page, field, inventorySortValue(last, updatedAt[page.Limit-1], page.SortBy), last.SKUID)
+	}
+	return invs, meta, nil
+}
+
+func inventorySortValue(inv *Inventory, updatedAt time.Time, sortBy string) interface{} {
+	switch sortBy {
+	case "quantity":
+		return inv.Qty
+	case "updated_at":
+		return updatedAt
+	default:
+		return inv.SKUID
+	}
+}
+
+// CheckSKUsExistence checks which SKUs from the given list exist in the caller's tenant.
+// Returns a map of skuID to bool (true if exists), and a slice of invalid skuIDs: those
+// that do not exist, belong to another tenant or to a seller the caller may not access.
 func CheckSKUsExistence(ctx context.Context, skuIDs []int64) (map[int64]bool, []int64, error) {
 	db := pg.GetClient().DB
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 	if len(skuIDs) == 0 {
 		return map[int64]bool{}, nil, nil
 	}
 
-	// Build query: SELECT id FROM skus WHERE id IN (...)
+	// Build query: SELECT id FROM skus WHERE tenant_id = $1 AND id IN (...)
 	placeholders := make([]string, len(skuIDs))
-	args := make([]interface{}, len(skuIDs))
+	args := make([]interface{}, 0, len(skuIDs)+1)
+	args = append(args, tenantID)
 	for i, id := range skuIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("SELECT id FROM skus WHERE tenant_id = $1 AND id IN (%s)", strings.Join(placeholders, ","))
+	if sellerIDs, restricted := permittedSellerIDs(ctx); restricted {
+		args = append(args, pq.Array(sellerIDs))
+		query += fmt.Sprintf(" AND seller_id = ANY($%d)", len(args))
 	}
-	query := fmt.Sprintf("SELECT id FROM skus WHERE id IN (%s)", strings.Join(placeholders, ","))
 
 	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
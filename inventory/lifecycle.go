@@ -0,0 +1,91 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+type SellerStatus string
+
+const (
+	SellerStatusActive   SellerStatus = "active"
+	SellerStatusInactive SellerStatus = "inactive"
+)
+
+var ErrSellerInactive = errors.New("seller is inactive")
+
+// SetSellerStatus records a seller's status as reported by seller lifecycle events.
+// Sellers without a recorded status are active.
+func SetSellerStatus(ctx context.Context, tenantID, sellerID int64, status SellerStatus) error {
+	db := pg.GetClient().DB
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO seller_statuses (tenant_id, seller_id, status, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (tenant_id, seller_id)
+		DO UPDATE SET status = EXCLUDED.status, updated_at = NOW()`, tenantID, sellerID, status)
+	return err
+}
+
+// ensureSKUWritable is ensureSKUInTenant for stock writes: it also fails with
+// ErrSellerInactive when the SKU's seller has been inactivated.
+func ensureSKUWritable(ctx context.Context, tx *sql.Tx, tenantID, skuID int64) error {
+	var (
+		sellerID int64
+		status   SellerStatus
+	)
+	err := tx.QueryRowContext(ctx, `
+		SELECT s.seller_id, COALESCE(ss.status, $3)
+		FROM skus s
+		LEFT JOIN seller_statuses ss ON ss.tenant_id = s.tenant_id AND ss.seller_id = s.seller_id
+		WHERE s.id = $1 AND s.tenant_id = $2`, skuID, tenantID, SellerStatusActive).Scan(&sellerID, &status)
+	if err == sql.ErrNoRows {
+		return ErrSKUNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !sellerPermitted(ctx, sellerID) {
+		return ErrSKUNotFound
+	}
+	if status != SellerStatusActive {
+		return fmt.Errorf("%w: seller %d of sku %d", ErrSellerInactive, sellerID, skuID)
+	}
+	return nil
+}
+
+// DecommissionHub marks a hub deleted upstream as decommissioned, whatever its current
+// status. Hubs already decommissioned or unknown to this service are left alone.
+func DecommissionHub(ctx context.Context, tenantID, hubID int64) error {
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockHub(ctx, tx, tenantID, hubID)
+	if errors.Is(err, ErrHubNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if before.Status == HubStatusDecommissioned {
+		return nil
+	}
+
+	after, err := scanHub(tx.QueryRowContext(ctx, `UPDATE hubs SET status = $1, version = version + 1, updated_at = NOW() WHERE id = $2 RETURNING `+hubColumns,
+		HubStatusDecommissioned, hubID))
+	if err != nil {
+		return err
+	}
+	if err = recordHub(ctx, tx, audit.ActionUpdate, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,36 @@
+package inventory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryInactiveSellersStockIsFrozen(t *testing.T) {
+	repo, ctx, hubID, skuID := stockedRepository(t)
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, 5, nil); err != nil {
+		t.Fatalf("UpsertInventory: %v", err)
+	}
+
+	// The same seller ID in another tenant is another seller.
+	repo.SetSellerStatus(2, 7, SellerStatusInactive)
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, 1, nil); err != nil {
+		t.Fatalf("another tenant's seller was inactivated: %v", err)
+	}
+
+	repo.SetSellerStatus(1, 7, SellerStatusInactive)
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, -1, nil); !errors.Is(err, ErrSellerInactive) {
+		t.Fatalf("upsert: got %v, want ErrSellerInactive", err)
+	}
+	if _, err := repo.SetInventory(ctx, hubID, skuID, 0, nil); !errors.Is(err, ErrSellerInactive) {
+		t.Fatalf("set: got %v, want ErrSellerInactive", err)
+	}
+	invs, err := repo.ViewInventory(ctx, hubID, []int64{skuID})
+	if err != nil || len(invs) != 1 || invs[0].Qty != 6 {
+		t.Fatalf("view: got %+v, %v, want the frozen stock of 6", invs, err)
+	}
+
+	repo.SetSellerStatus(1, 7, SellerStatusActive)
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, -1, nil); err != nil {
+		t.Fatalf("upsert after reactivation: %v", err)
+	}
+}
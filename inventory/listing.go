@@ -0,0 +1,275 @@
+package inventory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omniful/ims_rohit/pkg/keyset"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+var (
+	ErrInvalidCursor = keyset.ErrInvalidCursor
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// Page asks for one page of a listing. Listings are ordered by SortBy, then by ID, and
+// paged with keyset cursors: Cursor is the NextCursor of the previous page, and must be
+// used with the same sort.
+type Page struct {
+	Limit  int
+	Cursor string
+	SortBy string
+	Desc   bool
+}
+
+// PageMeta describes a returned page. NextCursor is empty on the last page. Total counts
+// every match of the filter; it is only computed for the first page.
+type PageMeta struct {
+	Limit      int    `json:"limit"`
+	SortBy     string `json:"sort,omitempty"`
+	Order      string `json:"order,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// TimeRange bounds a timestamp; a nil end is open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+func (r TimeRange) contains(t time.Time) bool {
+	return (r.From == nil || !t.Before(*r.From)) && (r.To == nil || !t.After(*r.To))
+}
+
+type sortKind int
+
+const (
+	sortInt sortKind = iota
+	sortText
+	sortTime
+)
+
+// sortField is a column listings can be ordered by.
+type sortField struct {
+	column string
+	kind   sortKind
+}
+
+func (k sortKind) cast() string {
+	switch k {
+	case sortText:
+		return "text"
+	case sortTime:
+		return "timestamp"
+	default:
+		return "bigint"
+	}
+}
+
+func (k sortKind) format(v interface{}) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (k sortKind) parse(s string) (interface{}, error) {
+	switch k {
+	case sortText:
+		return s, nil
+	case sortTime:
+		return time.Parse(time.RFC3339Nano, s)
+	default:
+		return strconv.ParseInt(s, 10, 64)
+	}
+}
+
+// compareSortValues orders two values of the same sortKind.
+func compareSortValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case int64:
+		switch b := b.(int64); {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	default:
+		return strings.Compare(a.(string), b.(string))
+	}
+}
+
+// cursor is the position after the last row of a page, tied to the sort it was made for.
+type cursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v"`
+	ID     int64  `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// pageLimit defaults an unset limit and caps it at MaxPageLimit.
+func pageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageLimit
+	case limit > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// pageParams validates a page against the sortable fields of a listing, defaulting the
+// limit and sort, and decodes its cursor. The returned cursor value is nil on the first
+// page.
+func pageParams(page *Page, fields map[string]sortField, defaultSort string) (sortField, interface{}, int64, error) {
+	page.Limit = pageLimit(page.Limit)
+	if page.SortBy == "" {
+		page.SortBy = defaultSort
+	}
+	field, ok := fields[page.SortBy]
+	if !ok {
+		return sortField{}, nil, 0, fmt.Errorf("%w: %s", ErrInvalidSort, page.SortBy)
+	}
+	if page.Cursor == "" {
+		return field, nil, 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(page.Cursor)
+	if err != nil {
+		return sortField{}, nil, 0, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return sortField{}, nil, 0, ErrInvalidCursor
+	}
+	if c.SortBy != page.SortBy || c.Desc != page.Desc {
+		return sortField{}, nil, 0, fmt.Errorf("%w: it was made for another sort", ErrInvalidCursor)
+	}
+	value, err := field.kind.parse(c.Value)
+	if err != nil {
+		return sortField{}, nil, 0, ErrInvalidCursor
+	}
+	return field, value, c.ID, nil
+}
+
+func newPageMeta(page Page) *PageMeta {
+	meta := &PageMeta{Limit: page.Limit, SortBy: page.SortBy, Order: "asc"}
+	if page.Desc {
+		meta.Order = "desc"
+	}
+	return meta
+}
+
+// pageCursor points after the row sorting at (value, id), the last of a page. Listings
+// fetch one row more than the limit, so they know whether there is a next page.
+func pageCursor(page Page, field sortField, value interface{}, id int64) string {
+	return encodeCursor(cursor{SortBy: page.SortBy, Desc: page.Desc, Value: field.kind.format(value), ID: id})
+}
+
+// listQuery builds the WHERE clause of a listing with numbered placeholders.
+type listQuery struct {
+	conds []string
+	args  []interface{}
+}
+
+// where adds a condition on one argument, written as a format string taking its
+// placeholder number.
+func (q *listQuery) where(cond string, arg interface{}) {
+	q.args = append(q.args, arg)
+	q.conds = append(q.conds, fmt.Sprintf(cond, len(q.args)))
+}
+
+func (q *listQuery) timeRange(column string, r TimeRange) {
+	if r.From != nil {
+		q.where(column+` >= $%d`, *r.From)
+	}
+	if r.To != nil {
+		q.where(column+` <= $%d`, *r.To)
+	}
+}
+
+// namePrefix matches names starting with prefix, ignoring case.
+func (q *listQuery) namePrefix(column, prefix string) {
+	if prefix == "" {
+		return
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+	q.where(column+` ILIKE $%d`, escaped+"%")
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(q.conds, " AND ")
+}
+
+// page returns the WHERE, ORDER BY and LIMIT clauses for one page ordered by field then
+// idColumn, and the arguments of the whole query.
+func (q *listQuery) page(page Page, field sortField, idColumn string, afterValue interface{}, afterID int64) (string, []interface{}) {
+	conds := q.conds
+	args := q.args
+	dir, cmp := "ASC", ">"
+	if page.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if afterValue != nil {
+		args = append(args[:len(args):len(args)], afterValue, afterID)
+		conds = append(conds[:len(conds):len(conds)], fmt.Sprintf(`(%s, %s) %s ($%d::%s, $%d)`,
+			field.column, idColumn, cmp, len(args)-1, field.kind.cast(), len(args)))
+	}
+	clause := ""
+	if len(conds) > 0 {
+		clause = ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	clause += fmt.Sprintf(` ORDER BY %s %s, %s %s LIMIT %d`, field.column, dir, idColumn, dir, page.Limit+1)
+	return clause, args
+}
+
+// afterCursor reports whether a row sorting at (value, id) comes after the cursor
+// position, for listings paged in memory.
+func afterCursor(page Page, value interface{}, id int64, afterValue interface{}, afterID int64) bool {
+	c := compareSortValues(value, afterValue)
+	if c == 0 {
+		c = compareSortValues(id, afterID)
+	}
+	if page.Desc {
+		return c < 0
+	}
+	return c > 0
+}
+
+// sortLess orders rows of an in-memory listing like the SQL ORDER BY of page.
+func sortLess(page Page, a, b interface{}, aID, bID int64) bool {
+	c := compareSortValues(a, b)
+	if c == 0 {
+		c = compareSortValues(aID, bID)
+	}
+	if page.Desc {
+		return c > 0
+	}
+	return c < 0
+}
@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageParams(t *testing.T) {
+	page := Page{}
+	field, after, afterID, err := pageParams(&page, skuSortFields, "id")
+	if err != nil || after != nil || afterID != 0 || page.Limit != DefaultPageLimit || page.SortBy != "id" || field.column == "" {
+		t.Fatalf("first page: got %+v, %v, %d, %v", page, after, afterID, err)
+	}
+
+	if _, _, _, err := pageParams(&Page{SortBy: "secret"}, skuSortFields, "id"); !errors.Is(err, ErrInvalidSort) {
+		t.Errorf("unknown sort: got %v, want ErrInvalidSort", err)
+	}
+	if _, _, _, err := pageParams(&Page{Cursor: "garbage!"}, skuSortFields, "id"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("garbage cursor: got %v, want ErrInvalidCursor", err)
+	}
+
+	byName := Page{SortBy: "name"}
+	nameField, _, _, _ := pageParams(&byName, skuSortFields, "id")
+	cursor := pageCursor(byName, nameField, "Mug", 42)
+	if _, _, _, err := pageParams(&Page{SortBy: "name", Desc: true, Cursor: cursor}, skuSortFields, "id"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("cursor of another order: got %v, want ErrInvalidCursor", err)
+	}
+	resumed := Page{SortBy: "name", Cursor: cursor}
+	if _, after, afterID, err := pageParams(&resumed, skuSortFields, "id"); err != nil || after != "Mug" || afterID != 42 {
+		t.Errorf("resumed page: got %v, %d, %v, want after (Mug, 42)", after, afterID, err)
+	}
+}
+
+func TestPageCursorRoundTripsTimes(t *testing.T) {
+	page := Page{SortBy: "updated_at"}
+	field, _, _, err := pageParams(&page, skuSortFields, "id")
+	if err != nil {
+		t.Fatalf("pageParams: %v", err)
+	}
+	at := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	resumed := Page{SortBy: "updated_at", Cursor: pageCursor(page, field, at, 7)}
+	_, after, afterID, err := pageParams(&resumed, skuSortFields, "id")
+	if err != nil || !after.(time.Time).Equal(at) || afterID != 7 {
+		t.Fatalf("got %v, %d, %v, want after (%v, 7)", after, afterID, err, at)
+	}
+}
+
+func TestListQueryPage(t *testing.T) {
+	q := &listQuery{}
+	q.where(`tenant_id = $%d`, int64(1))
+	field := sortField{column: "name", kind: sortText}
+
+	clause, args := q.page(Page{Limit: 10}, field, "id", nil, 0)
+	if clause != ` WHERE tenant_id = $1 ORDER BY name ASC, id ASC LIMIT 11` || len(args) != 1 {
+		t.Errorf("first page: got %q with %d args", clause, len(args))
+	}
+	clause, args = q.page(Page{Limit: 10, Desc: true}, field, "id", "Mug", 42)
+	if !strings.Contains(clause, `(name, id) < ($2::text, $3)`) || !strings.Contains(clause, `ORDER BY name DESC, id DESC`) || len(args) != 3 {
+		t.Errorf("next page: got %q with %v", clause, args)
+	}
+	if len(q.args) != 1 {
+		t.Errorf("paging changed the query's own arguments: %v", q.args)
+	}
+}
+
+func TestMemoryListingPagesWithCursors(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+	for _, name := range []string{"Cap", "Mug", "Bag", "Pen", "Hat"} {
+		if _, err := repo.CreateSKU(ctx, &SKU{SellerID: 7, SKUCode: strings.ToUpper(name), Name: name}); err != nil {
+			t.Fatalf("CreateSKU: %v", err)
+		}
+	}
+
+	var names []string
+	page := Page{Limit: 2, SortBy: "name"}
+	for i := 0; ; i++ {
+		skus, meta, err := repo.ListSKUs(ctx, SKUFilter{}, page)
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		if (i == 0) != (meta.Total != nil) {
+			t.Errorf("page %d: got total %v, want it on the first page only", i, meta.Total)
+		}
+		for _, s := range skus {
+			names = append(names, s.Name)
+		}
+		if meta.NextCursor == "" {
+			break
+		}
+		page.Cursor = meta.NextCursor
+	}
+	if got := strings.Join(names, ","); got != "Bag,Cap,Hat,Mug,Pen" {
+		t.Fatalf("got %s, want every SKU once in name order", got)
+	}
+}
@@ -0,0 +1,653 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type stockKey struct {
+	hubID int64
+	skuID int64
+}
+
+type sellerKey struct {
+	tenantID int64
+	sellerID int64
+}
+
+// MemoryRepository keeps hubs, SKUs and stock in memory with the same semantics as
+// PostgresRepository, for running handlers and services without external services. It
+// records no audit trail or events and does not use the balance cache.
+type MemoryRepository struct {
+	mu             sync.Mutex
+	hubs           map[int64]*Hub
+	skus           map[int64]*SKU
+	stock          map[stockKey]*Inventory
+	stockUpdatedAt map[stockKey]time.Time
+	sellerStatuses map[sellerKey]SellerStatus
+	lastHubID      int64
+	lastSKUID      int64
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		hubs:           map[int64]*Hub{},
+		skus:           map[int64]*SKU{},
+		stock:          map[stockKey]*Inventory{},
+		stockUpdatedAt: map[stockKey]time.Time{},
+		sellerStatuses: map[sellerKey]SellerStatus{},
+	}
+}
+
+// SetSellerStatus is the counterpart of the package-level SetSellerStatus.
+func (r *MemoryRepository) SetSellerStatus(tenantID, sellerID int64, status SellerStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sellerStatuses[sellerKey{tenantID, sellerID}] = status
+}
+
+func copyHub(h *Hub) *Hub {
+	c := *h
+	return &c
+}
+
+func copySKU(s *SKU) *SKU {
+	c := *s
+	return &c
+}
+
+// tenantHub returns the tenant's hub, or ErrHubNotFound.
+func (r *MemoryRepository) tenantHub(tenantID, id int64) (*Hub, error) {
+	h, ok := r.hubs[id]
+	if !ok || h.TenantID != tenantID {
+		return nil, ErrHubNotFound
+	}
+	return h, nil
+}
+
+// tenantSKU returns the tenant's SKU, or ErrSKUNotFound.
+func (r *MemoryRepository) tenantSKU(tenantID, id int64) (*SKU, error) {
+	s, ok := r.skus[id]
+	if !ok || s.TenantID != tenantID {
+		return nil, ErrSKUNotFound
+	}
+	return s, nil
+}
+
+func (r *MemoryRepository) CreateHub(ctx context.Context, hub *Hub) (int64, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	hub.TenantID = tenantID
+	applyHubDefaults(hub)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastHubID++
+	hub.ID = r.lastHubID
+	hub.Version = 1
+	stored := copyHub(hub)
+	stored.CreatedAt = time.Now()
+	stored.UpdatedAt = stored.CreatedAt
+	r.hubs[hub.ID] = stored
+	*hub = *copyHub(stored)
+	return hub.ID, nil
+}
+
+func (r *MemoryRepository) GetHub(ctx context.Context, id int64) (*Hub, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, err := r.tenantHub(tenantID, id)
+	if err != nil {
+		return nil, nil
+	}
+	return copyHub(h), nil
+}
+
+func (r *MemoryRepository) GetHubs(ctx context.Context, ids []int64) ([]*Hub, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var hubs []*Hub
+	for id := range idSet(ids) {
+		if h, err := r.tenantHub(tenantID, id); err == nil {
+			hubs = append(hubs, copyHub(h))
+		}
+	}
+	return hubs, nil
+}
+
+func (r *MemoryRepository) UpdateHub(ctx context.Context, hub *Hub, expectedVersion int64) error {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	hub.TenantID = tenantID
+	if hub.Type == "" {
+		hub.Type = HubTypeWarehouse
+	}
+	if hub.Timezone == "" {
+		hub.Timezone = defaultHubTimezone
+	}
+	applyCapacityDefaults(hub)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	before, err := r.tenantHub(tenantID, hub.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(before.Version, expectedVersion); err != nil {
+		return err
+	}
+	hub.Version = before.Version + 1
+	after := copyHub(hub)
+	after.Status = before.Status
+	after.CreatedAt = before.CreatedAt
+	after.UpdatedAt = time.Now()
+	r.hubs[hub.ID] = after
+	*hub = *copyHub(after)
+	return nil
+}
+
+func (r *MemoryRepository) UpdateHubStatus(ctx context.Context, id int64, status HubStatus, expectedVersion int64) error {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, err := r.tenantHub(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(h.Version, expectedVersion); err != nil {
+		return err
+	}
+	if h.Status == status {
+		return nil
+	}
+	if !h.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidHubTransition, h.Status, status)
+	}
+	h.Status = status
+	h.Version++
+	h.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *MemoryRepository) DeleteHub(ctx context.Context, id int64, expectedVersion int64) error {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, err := r.tenantHub(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(h.Version, expectedVersion); err != nil {
+		return err
+	}
+	delete(r.hubs, id)
+	for k := range r.stock {
+		if k.hubID == id {
+			delete(r.stock, k)
+			delete(r.stockUpdatedAt, k)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) ListHubs(ctx context.Context, filter HubFilter, page Page) ([]*Hub, *PageMeta, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	field, afterValue, afterID, err := pageParams(&page, hubSortFields, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+	hubIDs, restricted := permittedHubIDs(ctx)
+	permitted := idSet(hubIDs)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var hubs []*Hub
+	for _, h := range r.hubs {
+		switch {
+		case h.TenantID != tenantID, restricted && !permitted[h.ID],
+			len(filter.Statuses) == 0 && h.Status == HubStatusDecommissioned,
+			len(filter.Statuses) > 0 && !containsStatus(filter.Statuses, h.Status),
+			!hasNamePrefix(h.Name, filter.NamePrefix),
+			!filter.Created.contains(h.CreatedAt), !filter.Updated.contains(h.UpdatedAt):
+			continue
+		}
+		hubs = append(hubs, copyHub(h))
+	}
+	hubs, meta := pageInMemory(hubs, page, field, afterValue, afterID, func(h *Hub) (interface{}, int64) {
+		return hubSortValue(h, page.SortBy), h.ID
+	})
+	return hubs, meta, nil
+}
+
+// pageInMemory sorts rows the way the SQL listings do and cuts out the page after the
+// cursor position, if any.
+func pageInMemory[T any](rows []T, page Page, field sortField, afterValue interface{}, afterID int64,
+	key func(T) (interface{}, int64)) ([]T, *PageMeta) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, aID := key(rows[i])
+		b, bID := key(rows[j])
+		return sortLess(page, a, b, aID, bID)
+	})
+
+	meta := newPageMeta(page)
+	if afterValue == nil {
+		total := int64(len(rows))
+		meta.Total = &total
+	} else {
+		start := sort.Search(len(rows), func(i int) bool {
+			value, id := key(rows[i])
+			return afterCursor(page, value, id, afterValue, afterID)
+		})
+		rows = rows[start:]
+	}
+	if len(rows) > page.Limit {
+		rows = rows[:page.Limit]
+		value, id := key(rows[page.Limit-1])
+		meta.NextCursor = pageCursor(page, field, value, id)
+	}
+	return rows, meta
+}
+
+func hasNamePrefix(name, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix))
+}
+
+func containsStatus(statuses []HubStatus, status HubStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func idSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func (r *MemoryRepository) CreateSKU(ctx context.Context, sku *SKU) (int64, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sku.TenantID = tenantID
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.skus {
+		if s.TenantID == sku.TenantID && s.SellerID == sku.SellerID && s.SKUCode == sku.SKUCode {
+			return 0, ErrDuplicateSKUCode
+		}
+	}
+	r.lastSKUID++
+	sku.ID = r.lastSKUID
+	sku.Version = 1
+	stored := copySKU(sku)
+	stored.CreatedAt = time.Now()
+	stored.UpdatedAt = stored.CreatedAt
+	r.skus[sku.ID] = stored
+	return sku.ID, nil
+}
+
+func (r *MemoryRepository) GetSKU(ctx context.Context, id int64) (*SKU, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, err := r.tenantSKU(tenantID, id)
+	if err != nil {
+		return nil, nil
+	}
+	return copySKU(s), nil
+}
+
+func (r *MemoryRepository) GetSKUs(ctx context.Context, ids []int64) ([]*SKU, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var skus []*SKU
+	for id := range idSet(ids) {
+		if s, ok := r.skus[id]; ok && s.TenantID == tenantID {
+			skus = append(skus, copySKU(s))
+		}
+	}
+	return skus, nil
+}
+
+func (r *MemoryRepository) UpdateSKU(ctx context.Context, sku *SKU, expectedVersion int64) error {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, err := r.tenantSKU(tenantID, sku.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(s.Version, expectedVersion); err != nil {
+		return err
+	}
+	s.Name = sku.Name
+	s.LengthCm = sku.LengthCm
+	s.WidthCm = sku.WidthCm
+	s.HeightCm = sku.HeightCm
+	s.UnitsPerPallet = sku.UnitsPerPallet
+	s.Version++
+	s.UpdatedAt = time.Now()
+	sku.Version = s.Version
+	return nil
+}
+
+func (r *MemoryRepository) DeleteSKU(ctx context.Context, id int64, expectedVersion int64) error {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, err := r.tenantSKU(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(s.Version, expectedVersion); err != nil {
+		return err
+	}
+	delete(r.skus, id)
+	for k := range r.stock {
+		if k.skuID == id {
+			delete(r.stock, k)
+			delete(r.stockUpdatedAt, k)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) ListSKUs(ctx context.Context, filter SKUFilter, page Page) ([]*SKU, *PageMeta, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	field, afterValue, afterID, err := pageParams(&page, skuSortFields, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+	sellerIDs, restricted := permittedSellerIDs(ctx)
+	permitted := idSet(sellerIDs)
+	codes := make(map[string]bool, len(filter.SKUCodes))
+	for _, code := range filter.SKUCodes {
+		codes[code] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var skus []*SKU
+	for _, s := range r.skus {
+		switch {
+		case s.TenantID != tenantID,
+			filter.SellerID != nil && s.SellerID != *filter.SellerID,
+			restricted && !permitted[s.SellerID],
+			len(codes) > 0 && !codes[s.SKUCode],
+			!hasNamePrefix(s.Name, filter.NamePrefix),
+			!filter.Created.contains(s.CreatedAt), !filter.Updated.contains(s.UpdatedAt):
+			continue
+		}
+		skus = append(skus, copySKU(s))
+	}
+	skus, meta := pageInMemory(skus, page, field, afterValue, afterID, func(s *SKU) (interface{}, int64) {
+		return skuSortValue(s, page.SortBy), s.ID
+	})
+	return skus, meta, nil
+}
+
+func (r *MemoryRepository) CheckSKUsExistence(ctx context.Context, skuIDs []int64) (map[int64]bool, []int64, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existence := make(map[int64]bool, len(skuIDs))
+	var invalid []int64
+	for _, id := range skuIDs {
+		s, err := r.tenantSKU(tenantID, id)
+		ok := err == nil && sellerPermitted(ctx, s.SellerID)
+		existence[id] = ok
+		if !ok {
+			invalid = append(invalid, id)
+		}
+	}
+	return existence, invalid, nil
+}
+
+// ensureStockWritable is ensureHubActive followed by ensureSKUWritable.
+func (r *MemoryRepository) ensureStockWritable(ctx context.Context, tenantID, hubID, skuID int64) (*Hub, error) {
+	h, err := r.tenantHub(tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	if h.Status != HubStatusActive {
+		return nil, fmt.Errorf("%w: hub %d is %s", ErrHubNotActive, hubID, h.Status)
+	}
+	s, ok := r.skus[skuID]
+	if !ok || s.TenantID != tenantID || !sellerPermitted(ctx, s.SellerID) {
+		return nil, ErrSKUNotFound
+	}
+	if status, ok := r.sellerStatuses[sellerKey{tenantID, s.SellerID}]; ok && status != SellerStatusActive {
+		return nil, fmt.Errorf("%w: seller %d of sku %d", ErrSellerInactive, s.SellerID, skuID)
+	}
+	return h, nil
+}
+
+// checkHubCapacity is the in-memory counterpart of the package-level checkHubCapacity.
+func (r *MemoryRepository) checkHubCapacity(h *Hub, skuID, qty int64) (string, error) {
+	if h.Capacity == nil {
+		return "", nil
+	}
+	var used float64
+	for k, inv := range r.stock {
+		if k.hubID == h.ID {
+			used += skuUsage(h.CapacityUnit, r.skus[k.skuID], inv.Qty)
+		}
+	}
+	sku := r.skus[skuID]
+	var current int64
+	if inv, ok := r.stock[stockKey{h.ID, skuID}]; ok {
+		current = inv.Qty
+	}
+	projected := used - skuUsage(h.CapacityUnit, sku, current) + skuUsage(h.CapacityUnit, sku, current+qty)
+	return capacityOutcome(h.ID, projected, *h.Capacity, h.CapacityUnit, h.CapacityPolicy)
+}
+
+// writeStock sets the SKU's stock at the hub to the quantity computed by next from the
+// current one, after the same checks as the Postgres writes.
+func (r *MemoryRepository) writeStock(ctx context.Context, hubID, skuID int64, expectedVersion *int64,
+	next func(current int64) int64) (*UpsertResult, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, err := r.ensureStockWritable(ctx, tenantID, hubID, skuID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := stockKey{hubID, skuID}
+	inv, ok := r.stock[key]
+	if !ok {
+		inv = &Inventory{HubID: hubID, SKUID: skuID}
+	}
+	if err := checkWrittenVersion(inv.Version+1, expectedVersion); err != nil {
+		return nil, err
+	}
+	qty := next(inv.Qty)
+	before, after := stockLevel{qty: inv.Qty, reserved: inv.Reserved}, stockLevel{qty: qty, reserved: inv.Reserved}
+	if _, err := reservedOutcome(hubID, skuID, before, after, false); err != nil {
+		return nil, err
+	}
+
+	result := &UpsertResult{Version: inv.Version + 1}
+	if delta := qty - inv.Qty; delta > 0 {
+		warning, err := r.checkHubCapacity(h, skuID, delta)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+	inv.Qty = qty
+	inv.Version = result.Version
+	r.stock[key] = inv
+	r.stockUpdatedAt[key] = time.Now()
+	return result, nil
+}
+
+func (r *MemoryRepository) UpsertInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error) {
+	return r.writeStock(ctx, hubID, skuID, expectedVersion, func(current int64) int64 { return current + qty })
+}
+
+func (r *MemoryRepository) SetInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error) {
+	return r.writeStock(ctx, hubID, skuID, expectedVersion, func(int64) int64 { return qty })
+}
+
+func (r *MemoryRepository) ViewInventory(ctx context.Context, hubID int64, skuIDs []int64) ([]*Inventory, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.tenantHub(tenantID, hubID); err != nil {
+		return nil, err
+	}
+
+	var invs []*Inventory
+	for id := range idSet(skuIDs) {
+		s, ok := r.skus[id]
+		if !ok || s.TenantID != tenantID || !sellerPermitted(ctx, s.SellerID) {
+			continue
+		}
+		inv := &Inventory{HubID: hubID, SKUID: id}
+		if stored, ok := r.stock[stockKey{hubID, id}]; ok {
+			*inv = *stored
+		}
+		invs = append(invs, inv)
+	}
+	sort.Slice(invs, func(i, j int) bool { return invs[i].SKUID < invs[j].SKUID })
+	return invs, nil
+}
+
+func (r *MemoryRepository) ListInventory(ctx context.Context, hubID int64, filter InventoryFilter, page Page) ([]*Inventory, *PageMeta, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	field, afterValue, afterID, err := pageParams(&page, inventorySortFields, "sku_id")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.tenantHub(tenantID, hubID); err != nil {
+		return nil, nil, err
+	}
+
+	var invs []*Inventory
+	for k, inv := range r.stock {
+		switch {
+		case k.hubID != hubID, r.skus[k.skuID].TenantID != tenantID,
+			!sellerPermitted(ctx, r.skus[k.skuID].SellerID),
+			filter.MinQty != nil && inv.Qty < *filter.MinQty,
+			filter.MaxQty != nil && inv.Qty > *filter.MaxQty,
+			!filter.Updated.contains(r.stockUpdatedAt[k]):
+			continue
+		}
+		c := *inv
+		invs = append(invs, &c)
+	}
+	invs, meta := pageInMemory(invs, page, field, afterValue, afterID, func(inv *Inventory) (interface{}, int64) {
+		return inventorySortValue(inv, r.stockUpdatedAt[stockKey{hubID, inv.SKUID}], page.SortBy), inv.SKUID
+	})
+	return invs, meta, nil
+}
+
+func (r *MemoryRepository) ListHubsInventory(ctx context.Context, hubIDs []int64, filter HubStockFilter, limit int) (map[int64][]*Inventory, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit = pageLimit(limit)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hubs, skus := idSet(hubIDs), idSet(filter.SKUIDs)
+	stock := make(map[int64][]*Inventory, len(hubIDs))
+	for k, inv := range r.stock {
+		switch {
+		case !hubs[k.hubID], r.hubs[k.hubID] == nil, r.hubs[k.hubID].TenantID != tenantID,
+			len(skus) > 0 && !skus[k.skuID],
+			r.skus[k.skuID] == nil || !sellerPermitted(ctx, r.skus[k.skuID].SellerID),
+			filter.MinQty != nil && inv.Qty < *filter.MinQty,
+			filter.MaxQty != nil && inv.Qty > *filter.MaxQty:
+			continue
+		}
+		c := *inv
+		stock[k.hubID] = append(stock[k.hubID], &c)
+	}
+	for hubID, invs := range stock {
+		sort.Slice(invs, func(i, j int) bool { return invs[i].SKUID < invs[j].SKUID })
+		if len(invs) > limit {
+			stock[hubID] = invs[:limit]
+		}
+	}
+	return stock, nil
+}
@@ -0,0 +1,245 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func tenantContext(tenantID int64) context.Context {
+	return WithTenantID(context.Background(), tenantID)
+}
+
+// stockedRepository returns a repository holding one active hub and one SKU of tenant 1.
+func stockedRepository(t *testing.T) (*MemoryRepository, context.Context, int64, int64) {
+	t.Helper()
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+	hubID, err := repo.CreateHub(ctx, &Hub{Name: "Riyadh DC"})
+	if err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+	skuID, err := repo.CreateSKU(ctx, &SKU{SellerID: 7, SKUCode: "TSHIRT-M", Name: "T-shirt M"})
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+	return repo, ctx, hubID, skuID
+}
+
+func TestMemoryUpsertInventoryAccumulates(t *testing.T) {
+	repo, ctx, hubID, skuID := stockedRepository(t)
+
+	for _, qty := range []int64{5, 3, -2} {
+		if _, err := repo.UpsertInventory(ctx, hubID, skuID, qty, nil); err != nil {
+			t.Fatalf("UpsertInventory(%d): %v", qty, err)
+		}
+	}
+
+	invs, err := repo.ViewInventory(ctx, hubID, []int64{skuID})
+	if err != nil {
+		t.Fatalf("ViewInventory: %v", err)
+	}
+	if len(invs) != 1 || invs[0].Qty != 6 || invs[0].Version != 3 {
+		t.Fatalf("got %+v, want quantity 6 at version 3", invs[0])
+	}
+}
+
+func TestMemoryUpsertInventoryChecksVersion(t *testing.T) {
+	repo, ctx, hubID, skuID := stockedRepository(t)
+
+	never := int64(0)
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, 4, &never); err != nil {
+		t.Fatalf("UpsertInventory at version 0: %v", err)
+	}
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, 4, &never); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("got %v, want ErrVersionMismatch", err)
+	}
+}
+
+func TestMemoryUpsertInventoryRejectsPausedHub(t *testing.T) {
+	repo, ctx, hubID, skuID := stockedRepository(t)
+
+	if err := repo.UpdateHubStatus(ctx, hubID, HubStatusPaused, 0); err != nil {
+		t.Fatalf("UpdateHubStatus: %v", err)
+	}
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, 1, nil); !errors.Is(err, ErrHubNotActive) {
+		t.Fatalf("got %v, want ErrHubNotActive", err)
+	}
+}
+
+func TestMemoryViewInventoryReturnsMissingSKUsAtZero(t *testing.T) {
+	repo, ctx, hubID, stocked := stockedRepository(t)
+	unstocked, err := repo.CreateSKU(ctx, &SKU{SellerID: 7, SKUCode: "TSHIRT-L", Name: "T-shirt L"})
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+	if _, err := repo.SetInventory(ctx, hubID, stocked, 10, nil); err != nil {
+		t.Fatalf("SetInventory: %v", err)
+	}
+
+	invs, err := repo.ViewInventory(ctx, hubID, []int64{unstocked, stocked})
+	if err != nil {
+		t.Fatalf("ViewInventory: %v", err)
+	}
+	if len(invs) != 2 {
+		t.Fatalf("got %d rows, want 2", len(invs))
+	}
+	if invs[0].SKUID != stocked || invs[0].Qty != 10 {
+		t.Errorf("got %+v, want sku %d at 10", invs[0], stocked)
+	}
+	if invs[1].SKUID != unstocked || invs[1].Qty != 0 || invs[1].Version != 0 {
+		t.Errorf("got %+v, want sku %d at 0, version 0", invs[1], unstocked)
+	}
+}
+
+func TestMemoryHubCRUD(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+
+	id, err := repo.CreateHub(ctx, &Hub{Name: "Jeddah DC", Address: "Industrial Area"})
+	if err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+	hub, err := repo.GetHub(ctx, id)
+	if err != nil || hub == nil {
+		t.Fatalf("GetHub: %v, %v", hub, err)
+	}
+	if hub.TenantID != 1 || hub.Status != HubStatusActive || hub.Type != HubTypeWarehouse || hub.Version != 1 {
+		t.Fatalf("got %+v, want an active warehouse of tenant 1 at version 1", hub)
+	}
+
+	hub.Name = "Jeddah South DC"
+	if err := repo.UpdateHub(ctx, hub, 1); err != nil {
+		t.Fatalf("UpdateHub: %v", err)
+	}
+	if err := repo.UpdateHub(ctx, hub, 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("UpdateHub at a stale version: got %v, want ErrVersionMismatch", err)
+	}
+	if hub, _ = repo.GetHub(ctx, id); hub.Name != "Jeddah South DC" || hub.Version != 2 {
+		t.Fatalf("got %+v, want the new name at version 2", hub)
+	}
+
+	if err := repo.DeleteHub(ctx, id, 2); err != nil {
+		t.Fatalf("DeleteHub: %v", err)
+	}
+	if hub, err = repo.GetHub(ctx, id); hub != nil || err != nil {
+		t.Fatalf("GetHub after delete: got %v, %v, want nil", hub, err)
+	}
+	if err := repo.DeleteHub(ctx, id, 0); !errors.Is(err, ErrHubNotFound) {
+		t.Fatalf("DeleteHub twice: got %v, want ErrHubNotFound", err)
+	}
+}
+
+func TestMemoryHubWritesReturnTheStoredHub(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+
+	created := &Hub{Name: "Dammam DC"}
+	if _, err := repo.CreateHub(ctx, created); err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+	if created.Status != HubStatusActive || created.Version != 1 || created.CreatedAt.IsZero() {
+		t.Fatalf("created: got %+v, want the stored hub", created)
+	}
+	if err := repo.UpdateHubStatus(ctx, created.ID, HubStatusPaused, 0); err != nil {
+		t.Fatalf("UpdateHubStatus: %v", err)
+	}
+
+	updated := &Hub{ID: created.ID, Name: "Dammam North DC"}
+	if err := repo.UpdateHub(ctx, updated, 0); err != nil {
+		t.Fatalf("UpdateHub: %v", err)
+	}
+	if updated.Status != HubStatusPaused || updated.Version != 3 || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("updated: got %+v, want the stored hub, still paused, at version 3", updated)
+	}
+}
+
+func TestMemoryHubsAreScopedToTenant(t *testing.T) {
+	repo := NewMemoryRepository()
+	id, err := repo.CreateHub(tenantContext(1), &Hub{Name: "Dammam DC"})
+	if err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+
+	other := tenantContext(2)
+	if hub, err := repo.GetHub(other, id); hub != nil || err != nil {
+		t.Fatalf("GetHub of another tenant: got %v, %v, want nil", hub, err)
+	}
+	if err := repo.DeleteHub(other, id, 0); !errors.Is(err, ErrHubNotFound) {
+		t.Fatalf("DeleteHub of another tenant: got %v, want ErrHubNotFound", err)
+	}
+	hubs, _, err := repo.ListHubs(other, HubFilter{}, Page{})
+	if err != nil || len(hubs) != 0 {
+		t.Fatalf("ListHubs of another tenant: got %v, %v, want none", hubs, err)
+	}
+}
+
+func TestMemorySKUCRUD(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+
+	id, err := repo.CreateSKU(ctx, &SKU{SellerID: 7, SKUCode: "MUG-01", Name: "Mug"})
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+	if _, err := repo.CreateSKU(ctx, &SKU{SellerID: 7, SKUCode: "MUG-01", Name: "Mug again"}); !errors.Is(err, ErrDuplicateSKUCode) {
+		t.Fatalf("CreateSKU with a taken code: got %v, want ErrDuplicateSKUCode", err)
+	}
+
+	sku, err := repo.GetSKU(ctx, id)
+	if err != nil || sku == nil {
+		t.Fatalf("GetSKU: %v, %v", sku, err)
+	}
+	sku.Name = "Large mug"
+	if err := repo.UpdateSKU(ctx, sku, 1); err != nil {
+		t.Fatalf("UpdateSKU: %v", err)
+	}
+	if sku, _ = repo.GetSKU(ctx, id); sku.Name != "Large mug" || sku.Version != 2 {
+		t.Fatalf("got %+v, want the new name at version 2", sku)
+	}
+
+	existence, invalid, err := repo.CheckSKUsExistence(ctx, []int64{id, id + 1})
+	if err != nil {
+		t.Fatalf("CheckSKUsExistence: %v", err)
+	}
+	if !existence[id] || existence[id+1] || len(invalid) != 1 || invalid[0] != id+1 {
+		t.Fatalf("got %v and invalid %v, want only %d to exist", existence, invalid, id)
+	}
+
+	if err := repo.DeleteSKU(ctx, id, 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("DeleteSKU at a stale version: got %v, want ErrVersionMismatch", err)
+	}
+	if err := repo.DeleteSKU(ctx, id, 2); err != nil {
+		t.Fatalf("DeleteSKU: %v", err)
+	}
+	if sku, err = repo.GetSKU(ctx, id); sku != nil || err != nil {
+		t.Fatalf("GetSKU after delete: got %v, %v, want nil", sku, err)
+	}
+}
+
+func TestMemorySKUsAreScopedToTenant(t *testing.T) {
+	repo := NewMemoryRepository()
+	id, err := repo.CreateSKU(tenantContext(1), &SKU{SellerID: 7, SKUCode: "CAP-01", Name: "Cap"})
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+
+	other := tenantContext(2)
+	if sku, err := repo.GetSKU(other, id); sku != nil || err != nil {
+		t.Fatalf("GetSKU of another tenant: got %v, %v, want nil", sku, err)
+	}
+	if err := repo.UpdateSKU(other, &SKU{ID: id, Name: "Stolen"}, 0); !errors.Is(err, ErrSKUNotFound) {
+		t.Fatalf("UpdateSKU of another tenant: got %v, want ErrSKUNotFound", err)
+	}
+	skus, _, err := repo.ListSKUs(other, SKUFilter{}, Page{})
+	if err != nil || len(skus) != 0 {
+		t.Fatalf("ListSKUs of another tenant: got %v, %v, want none", skus, err)
+	}
+}
+
+func TestMemoryRequiresTenant(t *testing.T) {
+	repo := NewMemoryRepository()
+	if _, err := repo.CreateHub(context.Background(), &Hub{Name: "Nowhere"}); !errors.Is(err, ErrTenantMissing) {
+		t.Fatalf("got %v, want ErrTenantMissing", err)
+	}
+}
@@ -0,0 +1,144 @@
+package inventory
+
+import "context"
+
+// HubRepository stores hubs. Reads and writes are scoped to the tenant in ctx.
+type HubRepository interface {
+	// CreateHub and UpdateHub leave the stored hub in hub, with its defaults, status,
+	// version and timestamps.
+	CreateHub(ctx context.Context, hub *Hub) (int64, error)
+	// GetHub returns nil, without an error, when the tenant has no such hub.
+	GetHub(ctx context.Context, id int64) (*Hub, error)
+	// GetHubs returns the tenant's hubs among ids, leaving out the others.
+	GetHubs(ctx context.Context, ids []int64) ([]*Hub, error)
+	// UpdateHub, UpdateHubStatus and DeleteHub fail with ErrVersionMismatch unless
+	// expectedVersion is 0 or the hub's current version.
+	UpdateHub(ctx context.Context, hub *Hub, expectedVersion int64) error
+	UpdateHubStatus(ctx context.Context, id int64, status HubStatus, expectedVersion int64) error
+	DeleteHub(ctx context.Context, id int64, expectedVersion int64) error
+	ListHubs(ctx context.Context, filter HubFilter, page Page) ([]*Hub, *PageMeta, error)
+}
+
+// SKURepository stores SKUs.
+type SKURepository interface {
+	CreateSKU(ctx context.Context, sku *SKU) (int64, error)
+	// GetSKU returns nil, without an error, when the tenant has no such SKU.
+	GetSKU(ctx context.Context, id int64) (*SKU, error)
+	// GetSKUs returns the tenant's SKUs among ids, leaving out the others.
+	GetSKUs(ctx context.Context, ids []int64) ([]*SKU, error)
+	// UpdateSKU and DeleteSKU fail with ErrVersionMismatch unless expectedVersion is 0 or
+	// the SKU's current version.
+	UpdateSKU(ctx context.Context, sku *SKU, expectedVersion int64) error
+	DeleteSKU(ctx context.Context, id int64, expectedVersion int64) error
+	ListSKUs(ctx context.Context, filter SKUFilter, page Page) ([]*SKU, *PageMeta, error)
+	CheckSKUsExistence(ctx context.Context, skuIDs []int64) (map[int64]bool, []int64, error)
+}
+
+// InventoryRepository stores stock levels of SKUs at hubs.
+type InventoryRepository interface {
+	// UpsertInventory adds qty, which may be negative, to the SKU's stock at the hub. Like
+	// SetInventory, it fails with ErrVersionMismatch unless expectedVersion is nil or the
+	// stock's current version, 0 when the SKU was never stocked at the hub.
+	UpsertInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error)
+	// SetInventory overwrites the SKU's stock at the hub.
+	SetInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error)
+	// ViewInventory returns the stock of the given SKUs at the hub, with missing ones at 0.
+	ViewInventory(ctx context.Context, hubID int64, skuIDs []int64) ([]*Inventory, error)
+	// ListInventory pages through the stock held at the hub.
+	ListInventory(ctx context.Context, hubID int64, filter InventoryFilter, page Page) ([]*Inventory, *PageMeta, error)
+	// ListHubsInventory returns the first limit rows of the stock held at each hub, by hub.
+	ListHubsInventory(ctx context.Context, hubIDs []int64, filter HubStockFilter, limit int) (map[int64][]*Inventory, error)
+}
+
+// PostgresRepository is the Postgres-backed repository used by the service. Writes also
+// record the audit trail and outbox events and refresh the balance cache.
+type PostgresRepository struct{}
+
+func NewPostgresRepository() *PostgresRepository {
+	return &PostgresRepository{}
+}
+
+func (PostgresRepository) CreateHub(ctx context.Context, hub *Hub) (int64, error) {
+	return CreateHub(ctx, hub)
+}
+
+func (PostgresRepository) GetHub(ctx context.Context, id int64) (*Hub, error) {
+	return GetHub(ctx, id)
+}
+
+func (PostgresRepository) GetHubs(ctx context.Context, ids []int64) ([]*Hub, error) {
+	return GetHubs(ctx, ids)
+}
+
+func (PostgresRepository) UpdateHub(ctx context.Context, hub *Hub, expectedVersion int64) error {
+	return UpdateHub(ctx, hub, expectedVersion)
+}
+
+func (PostgresRepository) UpdateHubStatus(ctx context.Context, id int64, status HubStatus, expectedVersion int64) error {
+	return UpdateHubStatus(ctx, id, status, expectedVersion)
+}
+
+func (PostgresRepository) DeleteHub(ctx context.Context, id int64, expectedVersion int64) error {
+	return DeleteHub(ctx, id, expectedVersion)
+}
+
+func (PostgresRepository) ListHubs(ctx context.Context, filter HubFilter, page Page) ([]*Hub, *PageMeta, error) {
+	return ListHubs(ctx, filter, page)
+}
+
+func (PostgresRepository) CreateSKU(ctx context.Context, sku *SKU) (int64, error) {
+	return CreateSKU(ctx, sku)
+}
+
+func (PostgresRepository) GetSKU(ctx context.Context, id int64) (*SKU, error) {
+	return GetSKU(ctx, id)
+}
+
+func (PostgresRepository) GetSKUs(ctx context.Context, ids []int64) ([]*SKU, error) {
+	return GetSKUs(ctx, ids)
+}
+
+func (PostgresRepository) UpdateSKU(ctx context.Context, sku *SKU, expectedVersion int64) error {
+	return UpdateSKU(ctx, sku, expectedVersion)
+}
+
+func (PostgresRepository) DeleteSKU(ctx context.Context, id int64, expectedVersion int64) error {
+	return DeleteSKU(ctx, id, expectedVersion)
+}
+
+func (PostgresRepository) ListSKUs(ctx context.Context, filter SKUFilter, page Page) ([]*SKU, *PageMeta, error) {
+	return ListSKUs(ctx, filter, page)
+}
+
+func (PostgresRepository) CheckSKUsExistence(ctx context.Context, skuIDs []int64) (map[int64]bool, []int64, error) {
+	return CheckSKUsExistence(ctx, skuIDs)
+}
+
+func (PostgresRepository) UpsertInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error) {
+	return UpsertInventory(ctx, hubID, skuID, qty, expectedVersion)
+}
+
+func (PostgresRepository) SetInventory(ctx context.Context, hubID, skuID, qty int64, expectedVersion *int64) (*UpsertResult, error) {
+	return SetInventory(ctx, hubID, skuID, qty, expectedVersion)
+}
+
+func (PostgresRepository) ViewInventory(ctx context.Context, hubID int64, skuIDs []int64) ([]*Inventory, error) {
+	return ViewInventory(ctx, hubID, skuIDs)
+}
+
+func (PostgresRepository) ListInventory(ctx context.Context, hubID int64, filter InventoryFilter, page Page) ([]*Inventory, *PageMeta, error) {
+	return ListInventory(ctx, hubID, filter, page)
+}
+
+func (PostgresRepository) ListHubsInventory(ctx context.Context, hubIDs []int64, filter HubStockFilter, limit int) (map[int64][]*Inventory, error) {
+	return ListHubsInventory(ctx, hubIDs, filter, limit)
+}
+
+var (
+	_ HubRepository       = PostgresRepository{}
+	_ SKURepository       = PostgresRepository{}
+	_ InventoryRepository = PostgresRepository{}
+	_ HubRepository       = (*MemoryRepository)(nil)
+	_ SKURepository       = (*MemoryRepository)(nil)
+	_ InventoryRepository = (*MemoryRepository)(nil)
+)
@@ -0,0 +1,346 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationReserved ReservationStatus = "reserved"
+	ReservationRejected ReservationStatus = "rejected"
+	ReservationReleased ReservationStatus = "released"
+	ReservationShipped  ReservationStatus = "shipped"
+)
+
+var (
+	ErrReservationNotFound = errors.New("order reservation not found")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	// ErrBelowReserved is returned for writes lowering stock below the quantity reserved
+	// for orders, which could then not be shipped.
+	ErrBelowReserved = errors.New("quantity below the reserved quantity")
+)
+
+type OrderItem struct {
+	SKUID int64 `json:"sku_id"`
+	Qty   int64 `json:"quantity"`
+}
+
+// Order is what the order events carry. One order is fulfilled from a single hub. Cancel
+// and ship events only need the order and tenant IDs.
+type Order struct {
+	ID       string      `json:"order_id"`
+	TenantID int64       `json:"tenant_id"`
+	HubID    int64       `json:"hub_id"`
+	Items    []OrderItem `json:"items"`
+}
+
+// reservedOutcome checks a write lowering stock from before to after leaves enough for the
+// reserved quantity. Physical counts are the truth about stock and are applied anyway,
+// with a warning; other writes are refused.
+func reservedOutcome(hubID, skuID int64, before, after stockLevel, counted bool) (string, error) {
+	if after.qty >= after.reserved || after.qty >= before.qty {
+		return "", nil
+	}
+
+	msg := fmt.Sprintf("sku %d at hub %d would have %d in stock for %d reserved", skuID, hubID, after.qty, after.reserved)
+	if counted {
+		return msg, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrBelowReserved, msg)
+}
+
+func (o *Order) Validate() error {
+	if o.ID == "" || o.TenantID == 0 {
+		return errors.New("order_id and tenant_id are required")
+	}
+	for _, item := range o.Items {
+		if item.SKUID == 0 || item.Qty <= 0 {
+			return fmt.Errorf("order %s has an invalid item", o.ID)
+		}
+	}
+	return nil
+}
+
+// lockReservation returns the order's reservation status and hub, locking it for the rest
+// of the transaction, or "" when the order has not been seen. Orders cancelled before they
+// were created have no hub, and 0 is returned for it.
+func lockReservation(ctx context.Context, tx *sql.Tx, tenantID int64, orderID string) (ReservationStatus, int64, error) {
+	var (
+		status ReservationStatus
+		hubID  sql.NullInt64
+	)
+	err := tx.QueryRowContext(ctx, `SELECT status, hub_id FROM order_reservations WHERE tenant_id = $1 AND order_id = $2 FOR UPDATE`,
+		tenantID, orderID).Scan(&status, &hubID)
+	if err == sql.ErrNoRows {
+		return "", 0, nil
+	}
+	return status, hubID.Int64, err
+}
+
+func setReservationStatus(ctx context.Context, tx *sql.Tx, tenantID int64, orderID string, status ReservationStatus, reason string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE order_reservations SET status = $1, reason = $2, updated_at = NOW() WHERE tenant_id = $3 AND order_id = $4`,
+		status, reason, tenantID, orderID)
+	return err
+}
+
+func reservationItems(ctx context.Context, tx *sql.Tx, tenantID int64, orderID string) ([]OrderItem, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT sku_id, quantity FROM order_reservation_items WHERE tenant_id = $1 AND order_id = $2 ORDER BY sku_id`,
+		tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []OrderItem
+	for rows.Next() {
+		var item OrderItem
+		if err := rows.Scan(&item.SKUID, &item.Qty); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ReserveOrder reserves stock for every item of a new order. Processing is idempotent by
+// order ID: an order already seen, including one cancelled before it was created, is left
+// alone. When the hub cannot fulfil the order the reservation is stored as rejected with
+// the reason, rather than failing, because retrying would not change the outcome.
+func ReserveOrder(ctx context.Context, order *Order) (ReservationStatus, error) {
+	if order.HubID == 0 || len(order.Items) == 0 {
+		return "", fmt.Errorf("order %s needs a hub_id and items to be reserved", order.ID)
+	}
+
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The insert claims the order ID; a concurrent or redelivered create waits here and
+	// then sees the row.
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO order_reservations (tenant_id, order_id, hub_id, status)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, order_id) DO NOTHING`, order.TenantID, order.ID, order.HubID, ReservationReserved)
+	if err != nil {
+		return "", err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		status, _, err := lockReservation(ctx, tx, order.TenantID, order.ID)
+		return status, err
+	}
+
+	if err = reserveItems(ctx, tx, order); err != nil {
+		if !errors.Is(err, ErrHubNotFound) && !errors.Is(err, ErrHubNotActive) &&
+			!errors.Is(err, ErrSKUNotFound) && !errors.Is(err, ErrSellerInactive) && !errors.Is(err, ErrInsufficientStock) {
+			return "", err
+		}
+		// Undo partial reservations but keep the order row so redeliveries stay no-ops.
+		tx.Rollback()
+		return rejectOrder(ctx, order, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	refreshBalances(ctx, order.TenantID, order.HubID, orderSKUIDs(order.Items)...)
+	return ReservationReserved, nil
+}
+
+func reserveItems(ctx context.Context, tx *sql.Tx, order *Order) error {
+	if err := ensureHubActive(ctx, tx, order.TenantID, order.HubID); err != nil {
+		return err
+	}
+
+	// Lock inventory rows in SKU order so concurrent orders cannot deadlock.
+	items := mergeOrderItems(order.Items)
+	for _, item := range items {
+		if err := ensureSKUWritable(ctx, tx, order.TenantID, item.SKUID); err != nil {
+			return err
+		}
+		var after stockLevel
+		err := tx.QueryRowContext(ctx, `
+			UPDATE inventory SET reserved = reserved + $1, version = version + 1, updated_at = NOW()
+			WHERE hub_id = $2 AND sku_id = $3 AND quantity - reserved >= $1
+			RETURNING quantity, reserved`, item.Qty, order.HubID, item.SKUID).Scan(&after.qty, &after.reserved)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: sku %d at hub %d", ErrInsufficientStock, item.SKUID, order.HubID)
+		}
+		if err != nil {
+			return err
+		}
+		before := stockLevel{qty: after.qty, reserved: after.reserved - item.Qty}
+		if err = recordInventory(ctx, tx, order.TenantID, order.HubID, item.SKUID, before, after); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_reservation_items (tenant_id, order_id, sku_id, quantity) VALUES ($1, $2, $3, $4)`,
+			order.TenantID, order.ID, item.SKUID, item.Qty)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeOrderItems sums duplicate SKU lines and sorts the result by SKU.
+func mergeOrderItems(items []OrderItem) []OrderItem {
+	qtyBySKU := map[int64]int64{}
+	for _, item := range items {
+		qtyBySKU[item.SKUID] += item.Qty
+	}
+	merged := make([]OrderItem, 0, len(qtyBySKU))
+	for skuID, qty := range qtyBySKU {
+		merged = append(merged, OrderItem{SKUID: skuID, Qty: qty})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].SKUID < merged[j].SKUID })
+	return merged
+}
+
+func orderSKUIDs(items []OrderItem) []int64 {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.SKUID
+	}
+	return ids
+}
+
+func rejectOrder(ctx context.Context, order *Order, reason error) (ReservationStatus, error) {
+	db := pg.GetClient().DB
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO order_reservations (tenant_id, order_id, hub_id, status, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, order_id) DO NOTHING`,
+		order.TenantID, order.ID, order.HubID, ReservationRejected, reason.Error())
+	if err != nil {
+		return "", err
+	}
+	log.Warnf("Rejected reservation for order %s of tenant %d: %s", order.ID, order.TenantID, reason)
+	return ReservationRejected, nil
+}
+
+// ReleaseOrder releases the stock reserved for a cancelled order. Cancelling an order not
+// yet seen records it as released, without a hub unless the event carries one, so a
+// create arriving late reserves nothing.
+func ReleaseOrder(ctx context.Context, order *Order) (ReservationStatus, error) {
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, hubID, err := lockReservation(ctx, tx, order.TenantID, order.ID)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case "":
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_reservations (tenant_id, order_id, hub_id, status, reason)
+			VALUES ($1, $2, NULLIF($3, 0), $4, 'cancelled before created')`, order.TenantID, order.ID, order.HubID, ReservationReleased)
+		if err != nil {
+			return "", err
+		}
+		return ReservationReleased, tx.Commit()
+	case ReservationReserved:
+	default:
+		return status, nil
+	}
+
+	items, err := reservationItems(ctx, tx, order.TenantID, order.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		var after stockLevel
+		err = tx.QueryRowContext(ctx, `
+			UPDATE inventory SET reserved = reserved - $1, version = version + 1, updated_at = NOW()
+			WHERE hub_id = $2 AND sku_id = $3
+			RETURNING quantity, reserved`, item.Qty, hubID, item.SKUID).Scan(&after.qty, &after.reserved)
+		if err != nil {
+			return "", err
+		}
+		before := stockLevel{qty: after.qty, reserved: after.reserved + item.Qty}
+		if err = recordInventory(ctx, tx, order.TenantID, hubID, item.SKUID, before, after); err != nil {
+			return "", err
+		}
+	}
+	if err = setReservationStatus(ctx, tx, order.TenantID, order.ID, ReservationReleased, ""); err != nil {
+		return "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	refreshBalances(ctx, order.TenantID, hubID, orderSKUIDs(items)...)
+	return ReservationReleased, nil
+}
+
+// ShipOrder turns an order's reservations into deductions from stock. Shipping an order
+// that was never reserved fails with ErrReservationNotFound so the message is retried
+// once the create has been processed.
+func ShipOrder(ctx context.Context, order *Order) (ReservationStatus, error) {
+	db := pg.GetClient().DB
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, hubID, err := lockReservation(ctx, tx, order.TenantID, order.ID)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case "":
+		return "", fmt.Errorf("%w: order %s", ErrReservationNotFound, order.ID)
+	case ReservationReserved:
+	default:
+		return status, nil
+	}
+
+	items, err := reservationItems(ctx, tx, order.TenantID, order.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		var after stockLevel
+		// Writes keep stock at or above the reserved quantity, except physical counts; an
+		// order counted short fails here rather than drive stock negative.
+		err = tx.QueryRowContext(ctx, `
+			UPDATE inventory SET quantity = quantity - $1, reserved = reserved - $1, version = version + 1, updated_at = NOW()
+			WHERE hub_id = $2 AND sku_id = $3 AND quantity >= $1 AND reserved >= $1
+			RETURNING quantity, reserved`, item.Qty, hubID, item.SKUID).Scan(&after.qty, &after.reserved)
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: sku %d at hub %d", ErrInsufficientStock, item.SKUID, hubID)
+		}
+		if err != nil {
+			return "", err
+		}
+		before := stockLevel{qty: after.qty + item.Qty, reserved: after.reserved + item.Qty}
+		if err = recordInventory(ctx, tx, order.TenantID, hubID, item.SKUID, before, after); err != nil {
+			return "", err
+		}
+	}
+	if err = setReservationStatus(ctx, tx, order.TenantID, order.ID, ReservationShipped, ""); err != nil {
+		return "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	refreshBalances(ctx, order.TenantID, hubID, orderSKUIDs(items)...)
+	return ReservationShipped, nil
+}
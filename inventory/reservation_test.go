@@ -0,0 +1,79 @@
+package inventory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReservedOutcome(t *testing.T) {
+	cases := []struct {
+		name          string
+		before, after stockLevel
+		counted       bool
+		wantWarning   bool
+		wantErr       error
+	}{
+		{"above reserved", stockLevel{10, 4}, stockLevel{6, 4}, false, false, nil},
+		{"down to reserved", stockLevel{10, 4}, stockLevel{4, 4}, false, false, nil},
+		{"below reserved", stockLevel{10, 4}, stockLevel{3, 4}, false, false, ErrBelowReserved},
+		{"counted below reserved", stockLevel{10, 4}, stockLevel{3, 4}, true, true, nil},
+		{"raised while still below", stockLevel{1, 4}, stockLevel{2, 4}, false, false, nil},
+	}
+	for _, c := range cases {
+		warning, err := reservedOutcome(1, 2, c.before, c.after, c.counted)
+		if !errors.Is(err, c.wantErr) || (err == nil) != (c.wantErr == nil) {
+			t.Errorf("%s: got error %v, want %v", c.name, err, c.wantErr)
+		}
+		if (warning != "") != c.wantWarning {
+			t.Errorf("%s: got warning %q, want one: %v", c.name, warning, c.wantWarning)
+		}
+	}
+}
+
+func TestMemoryWritesKeepReservedStock(t *testing.T) {
+	repo, ctx, hubID, skuID := stockedRepository(t)
+	if _, err := repo.SetInventory(ctx, hubID, skuID, 10, nil); err != nil {
+		t.Fatalf("SetInventory: %v", err)
+	}
+	repo.stock[stockKey{hubID, skuID}].Reserved = 6
+
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, -5, nil); !errors.Is(err, ErrBelowReserved) {
+		t.Fatalf("decrement below reserved: got %v, want ErrBelowReserved", err)
+	}
+	if _, err := repo.SetInventory(ctx, hubID, skuID, 5, nil); !errors.Is(err, ErrBelowReserved) {
+		t.Fatalf("set below reserved: got %v, want ErrBelowReserved", err)
+	}
+	if _, err := repo.UpsertInventory(ctx, hubID, skuID, -4, nil); err != nil {
+		t.Fatalf("decrement down to reserved: %v", err)
+	}
+}
+
+func TestMergeOrderItems(t *testing.T) {
+	merged := mergeOrderItems([]OrderItem{{SKUID: 9, Qty: 1}, {SKUID: 3, Qty: 2}, {SKUID: 9, Qty: 4}})
+	want := []OrderItem{{SKUID: 3, Qty: 2}, {SKUID: 9, Qty: 5}}
+	if len(merged) != len(want) {
+		t.Fatalf("got %v, want %v", merged, want)
+	}
+	for i := range want {
+		if merged[i] != want[i] {
+			t.Fatalf("got %v, want %v", merged, want)
+		}
+	}
+}
+
+func TestOrderValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		order Order
+		valid bool
+	}{
+		{"valid", Order{ID: "o-1", TenantID: 1, Items: []OrderItem{{SKUID: 1, Qty: 1}}}, true},
+		{"no tenant", Order{ID: "o-1"}, false},
+		{"no quantity", Order{ID: "o-1", TenantID: 1, Items: []OrderItem{{SKUID: 1}}}, false},
+	}
+	for _, c := range cases {
+		if err := c.order.Validate(); (err == nil) != c.valid {
+			t.Errorf("%s: got %v, want valid %v", c.name, err, c.valid)
+		}
+	}
+}
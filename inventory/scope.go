@@ -0,0 +1,113 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/omniful/api-gateway/constants"
+	"github.com/omniful/ims_rohit/pkg/pg"
+)
+
+// permittedIDs returns the hub or seller IDs the access-control middleware left in the
+// context for this request. The caller is restricted to ids, which may be empty, unless
+// restricted is false: the middleware leaves the key unset for callers with access to all.
+func permittedIDs(ctx context.Context, key string) (ids []int64, restricted bool) {
+	values, ok := ctx.Value(key).([]string)
+	if !ok {
+		return nil, false
+	}
+	ids = make([]int64, 0, len(values))
+	for _, v := range values {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, true
+}
+
+func permittedHubIDs(ctx context.Context) ([]int64, bool) {
+	return permittedIDs(ctx, constants.HubIDs)
+}
+
+func permittedSellerIDs(ctx context.Context) ([]int64, bool) {
+	return permittedIDs(ctx, constants.SellerIDs)
+}
+
+// sellerPermitted reports whether the caller may access the SKUs of the seller.
+func sellerPermitted(ctx context.Context, sellerID int64) bool {
+	sellerIDs, restricted := permittedSellerIDs(ctx)
+	if !restricted {
+		return true
+	}
+	for _, id := range sellerIDs {
+		if id == sellerID {
+			return true
+		}
+	}
+	return false
+}
+
+// permittedSKUs narrows skuIDs to the tenant's SKUs of sellers the caller may access,
+// keeping their order. Callers not restricted to some sellers get skuIDs back as they are.
+func permittedSKUs(ctx context.Context, db *sql.DB, tenantID int64, skuIDs []int64) ([]int64, error) {
+	sellerIDs, restricted := permittedSellerIDs(ctx)
+	if !restricted || len(skuIDs) == 0 {
+		return skuIDs, nil
+	}
+	rows, err := db.QueryContext(ctx, `SELECT id FROM skus WHERE tenant_id = $1 AND id = ANY($2) AND seller_id = ANY($3)`,
+		tenantID, pq.Array(skuIDs), pq.Array(sellerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	found := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	permitted := make([]int64, 0, len(found))
+	for _, id := range skuIDs {
+		if found[id] {
+			permitted = append(permitted, id)
+		}
+	}
+	return permitted, nil
+}
+
+// PermittedSKUs returns those of skuIDs the caller may access, for handlers filtering what
+// the repositories do not, such as the stock stream.
+func PermittedSKUs(ctx context.Context, skuIDs []int64) ([]int64, error) {
+	tenantID, err := TenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return permittedSKUs(ctx, pg.GetClient().Reader(), tenantID, skuIDs)
+}
+
+// GetHubIDs and GetSellerIDs let responses carrying a hub or SKU be checked by the
+// access-control response handler.
+
+func (h *Hub) GetHubIDs() []string {
+	return []string{strconv.FormatInt(h.ID, 10)}
+}
+
+func (h *Hub) GetSellerIDs() []string {
+	return nil
+}
+
+func (s *SKU) GetHubIDs() []string {
+	return nil
+}
+
+func (s *SKU) GetSellerIDs() []string {
+	return []string{strconv.FormatInt(s.SellerID, 10)}
+}
@@ -0,0 +1,146 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/omniful/api-gateway/constants"
+)
+
+// scopedContext restricts ctx to ids under key, as the access-control middleware does.
+func scopedContext(ctx context.Context, key string, ids ...int64) context.Context {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+	return context.WithValue(ctx, key, values)
+}
+
+func TestListHubsFollowsHubScope(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+	first, err := repo.CreateHub(ctx, &Hub{Name: "Riyadh DC"})
+	if err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+	if _, err := repo.CreateHub(ctx, &Hub{Name: "Jeddah DC"}); err != nil {
+		t.Fatalf("CreateHub: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{"all hubs", ctx, 2},
+		{"one hub", scopedContext(ctx, constants.HubIDs, first), 1},
+		{"no hub", scopedContext(ctx, constants.HubIDs), 0},
+	}
+	for _, c := range cases {
+		hubs, _, err := repo.ListHubs(c.ctx, HubFilter{}, Page{})
+		if err != nil {
+			t.Fatalf("%s: ListHubs: %v", c.name, err)
+		}
+		if len(hubs) != c.want {
+			t.Errorf("%s: got %d hubs, want %d", c.name, len(hubs), c.want)
+		}
+	}
+}
+
+func TestListSKUsFollowsSellerScope(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := tenantContext(1)
+	for _, sku := range []*SKU{
+		{SellerID: 7, SKUCode: "MUG-01", Name: "Mug"},
+		{SellerID: 8, SKUCode: "CAP-01", Name: "Cap"},
+	} {
+		if _, err := repo.CreateSKU(ctx, sku); err != nil {
+			t.Fatalf("CreateSKU: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{"all sellers", ctx, 2},
+		{"one seller", scopedContext(ctx, constants.SellerIDs, 8), 1},
+		{"no seller", scopedContext(ctx, constants.SellerIDs), 0},
+	}
+	for _, c := range cases {
+		skus, _, err := repo.ListSKUs(c.ctx, SKUFilter{}, Page{})
+		if err != nil {
+			t.Fatalf("%s: ListSKUs: %v", c.name, err)
+		}
+		if len(skus) != c.want {
+			t.Errorf("%s: got %d SKUs, want %d", c.name, len(skus), c.want)
+		}
+	}
+}
+
+func TestStockFollowsSellerScope(t *testing.T) {
+	repo, ctx, hubID, skuID := stockedRepository(t)
+	if _, err := repo.SetInventory(ctx, hubID, skuID, 10, nil); err != nil {
+		t.Fatalf("SetInventory: %v", err)
+	}
+	ownSeller := scopedContext(ctx, constants.SellerIDs, 7)
+	otherSeller := scopedContext(ctx, constants.SellerIDs, 8)
+
+	if _, err := repo.UpsertInventory(ownSeller, hubID, skuID, 1, nil); err != nil {
+		t.Fatalf("write by the SKU's seller: %v", err)
+	}
+	if _, err := repo.UpsertInventory(otherSeller, hubID, skuID, 1, nil); !errors.Is(err, ErrSKUNotFound) {
+		t.Fatalf("write by another seller: got %v, want ErrSKUNotFound", err)
+	}
+	if _, err := repo.SetInventory(otherSeller, hubID, skuID, 0, nil); !errors.Is(err, ErrSKUNotFound) {
+		t.Fatalf("set by another seller: got %v, want ErrSKUNotFound", err)
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{"all sellers", ctx, 1},
+		{"the SKU's seller", ownSeller, 1},
+		{"another seller", otherSeller, 0},
+	}
+	for _, c := range cases {
+		viewed, err := repo.ViewInventory(c.ctx, hubID, []int64{skuID})
+		if err != nil {
+			t.Fatalf("%s: ViewInventory: %v", c.name, err)
+		}
+		listed, _, err := repo.ListInventory(c.ctx, hubID, InventoryFilter{}, Page{})
+		if err != nil {
+			t.Fatalf("%s: ListInventory: %v", c.name, err)
+		}
+		byHub, err := repo.ListHubsInventory(c.ctx, []int64{hubID}, HubStockFilter{}, 10)
+		if err != nil {
+			t.Fatalf("%s: ListHubsInventory: %v", c.name, err)
+		}
+		existence, _, err := repo.CheckSKUsExistence(c.ctx, []int64{skuID})
+		if err != nil {
+			t.Fatalf("%s: CheckSKUsExistence: %v", c.name, err)
+		}
+		if len(viewed) != c.want || len(listed) != c.want || len(byHub[hubID]) != c.want || existence[skuID] != (c.want == 1) {
+			t.Errorf("%s: got %d viewed, %d listed, %d by hub and existence %v, want %d",
+				c.name, len(viewed), len(listed), len(byHub[hubID]), existence[skuID], c.want)
+		}
+	}
+}
+
+func TestSellerPermitted(t *testing.T) {
+	ctx := tenantContext(1)
+	if !sellerPermitted(ctx, 7) {
+		t.Error("unrestricted caller: got seller 7 refused")
+	}
+	if !sellerPermitted(scopedContext(ctx, constants.SellerIDs, 7), 7) {
+		t.Error("caller scoped to seller 7: got seller 7 refused")
+	}
+	if sellerPermitted(scopedContext(ctx, constants.SellerIDs), 7) {
+		t.Error("caller scoped to no seller: got seller 7 permitted")
+	}
+}
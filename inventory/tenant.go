@@ -0,0 +1,55 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/omniful/go_commons/jwt/public"
+)
+
+var (
+	ErrTenantMissing = errors.New("tenant not found in context")
+	ErrSKUNotFound   = errors.New("sku not found")
+	// ErrDuplicateSKUCode is returned when the seller already has a SKU with the code.
+	ErrDuplicateSKUCode = errors.New("sku code already exists for the seller")
+)
+
+type tenantKey struct{}
+
+// WithTenantID returns a context acting for the tenant, for callers without a JWT such as
+// workers and tests. It takes precedence over the tenant of the JWT.
+func WithTenantID(ctx context.Context, tenantID int64) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantIDFromContext reads the caller's tenant from WithTenantID or else from the JWT
+// details in ctx.
+func TenantIDFromContext(ctx context.Context) (int64, error) {
+	if id, ok := ctx.Value(tenantKey{}).(int64); ok {
+		return id, nil
+	}
+	tenantID, err := public.GetTenantID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrTenantMissing, err)
+	}
+
+	id, err := strconv.ParseInt(tenantID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid tenant id %q", ErrTenantMissing, tenantID)
+	}
+	return id, nil
+}
+
+// ensureSKUInTenant fails with ErrSKUNotFound when the SKU does not exist for the tenant,
+// or belongs to a seller the caller may not access.
+func ensureSKUInTenant(ctx context.Context, tx *sql.Tx, tenantID, skuID int64) error {
+	var sellerID int64
+	err := tx.QueryRowContext(ctx, `SELECT seller_id FROM skus WHERE id = $1 AND tenant_id = $2`, skuID, tenantID).Scan(&sellerID)
+	if err == sql.ErrNoRows || err == nil && !sellerPermitted(ctx, sellerID) {
+		return ErrSKUNotFound
+	}
+	return err
+}
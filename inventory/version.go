@@ -0,0 +1,28 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionMismatch is returned by conditional writes when the row changed since the
+// caller read it.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// checkVersion fails unless expected is 0, meaning unconditional, or the current version.
+func checkVersion(current, expected int64) error {
+	if expected != 0 && expected != current {
+		return fmt.Errorf("%w: expected version %d, current version is %d", ErrVersionMismatch, expected, current)
+	}
+	return nil
+}
+
+// checkWrittenVersion fails unless a write that bumped a row to version written started
+// from the expected version. A nil expected means unconditional. Rows are locked by the
+// write itself, so comparing afterwards and rolling back is race free.
+func checkWrittenVersion(written int64, expected *int64) error {
+	if expected != nil && written != *expected+1 {
+		return fmt.Errorf("%w: expected version %d, current version is %d", ErrVersionMismatch, *expected, written-1)
+	}
+	return nil
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata" // hub timezones must resolve on minimal base images
 
 	"github.com/omniful/go_commons/config"
 	"github.com/omniful/go_commons/http"
@@ -12,13 +15,18 @@ import (
 	"github.com/omniful/go_commons/shutdown"
 	"github.com/omniful/go_commons/worker/configs"
 	appinit "github.com/omniful/ims_rohit/init"
+	"github.com/omniful/ims_rohit/internal/grpcapi"
+	"github.com/omniful/ims_rohit/internal/stockstream"
+	"github.com/omniful/ims_rohit/pkg/pg"
 	"github.com/omniful/ims_rohit/router"
 	"github.com/omniful/ims_rohit/workers"
 )
 
 const (
-	modeWorker = "worker"
-	modeHttp   = "http"
+	modeWorker  = "worker"
+	modeHttp    = "http"
+	modeMigrate = "migrate"
+	modeGRPC    = "grpc"
 )
 
 func main() {
@@ -42,7 +50,7 @@ func main() {
 		&mode,
 		"mode",
 		modeHttp,
-		"Pass the flag to run in different modes (worker or http)",
+		"Pass the flag to run in different modes (worker, http, grpc or migrate). Migrate takes up, down N or status as arguments",
 	)
 
 	flag.StringVar(
@@ -68,6 +76,11 @@ func main() {
 
 	flag.Parse()
 
+	if strings.ToLower(mode) == modeMigrate {
+		runMigrations(ctx, flag.Args())
+		return
+	}
+
 	server := http.InitializeServer(":8090",
 		35*time.Second,
 		35*time.Second,
@@ -78,6 +91,9 @@ func main() {
 	switch strings.ToLower(mode) {
 	case modeHttp:
 		runHttpServer(ctx, server)
+	case modeGRPC:
+		runGRPCServer(ctx)
+		<-shutdown.GetWaitChannel()
 	case modeWorker:
 		serverConfig := configs.ServerConfig{
 			IncludeGroupsArg: includeGroupArg,
@@ -92,9 +108,18 @@ func main() {
 
 func runHttpServer(ctx context.Context, server *http.Server) {
 	// Initialize middlewares and routes
-	engine := router.SetupRouter()
+	engine := router.SetupRouter(ctx)
+	// The gRPC API can share the HTTP server's process, on its own port.
+	if config.GetBool(ctx, "grpc.cohost") {
+		go runGRPCServer(ctx)
+	}
 	// Since server embeds *gin.Engine, we can directly use the engine
 	*server.Engine = *engine
+	// Stock streams never finish on their own; end them as soon as shutdown starts so it
+	// does not wait out its timeout on them.
+	server.RegisterOnShutdown(func() {
+		stockstream.GetStream(ctx).Close()
+	})
 
 	log.Infof("Starting server on port" + config.GetString(ctx, "server.port"))
 
@@ -105,3 +130,63 @@ func runHttpServer(ctx context.Context, server *http.Server) {
 
 	<-shutdown.GetWaitChannel()
 }
+
+func runGRPCServer(ctx context.Context) {
+	err := grpcapi.NewServer(ctx).StartServer("gRPC API")
+	if err != nil {
+		log.Errorf(err.Error())
+	}
+}
+
+// runMigrations runs the schema migration command in args: "up" (the default), "down N"
+// or "status".
+func runMigrations(ctx context.Context, args []string) {
+	db := pg.GetClient().DB
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := pg.MigrateUp(ctx, db)
+		for _, m := range applied {
+			log.Infof("Applied migration %d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Panicf("Migrating up failed, err: %v", err)
+		}
+		if len(applied) == 0 {
+			log.Infof("Schema is up to date")
+		}
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+				log.Panicf("down takes a positive number of migrations to revert, got %q", args[1])
+			}
+		}
+		reverted, err := pg.MigrateDown(ctx, db, n)
+		for _, m := range reverted {
+			log.Infof("Reverted migration %d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Panicf("Migrating down failed, err: %v", err)
+		}
+	case "status":
+		statuses, err := pg.MigrationStatuses(ctx, db)
+		if err != nil {
+			log.Panicf("Reading migration status failed, err: %v", err)
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+	default:
+		log.Panicf("Unknown migrate command %q, expected up, down N or status", command)
+	}
+}
@@ -0,0 +1,19 @@
+package datetime
+
+import (
+	"context"
+	"time"
+
+	"github.com/omniful/api-gateway/constants"
+)
+
+// WithTimeZone returns a context carrying the given IANA timezone as the location used by
+// the formatting helpers in this package, e.g. to render times in a hub's local time.
+func WithTimeZone(ctx context.Context, timezone string) (context.Context, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, constants.UserTimeZoneLocation, location), nil
+}
@@ -4,11 +4,62 @@ import (
 	oerror "github.com/omniful/go_commons/error"
 	"github.com/omniful/go_commons/http"
 	"github.com/omniful/go_commons/response"
+	"google.golang.org/grpc/codes"
+)
+
+// Error codes returned by the API, in the code field of the error envelope.
+const (
+	NotFound           oerror.Code = "NOT_FOUND"
+	Conflict           oerror.Code = "CONFLICT"
+	UniqueViolation    oerror.Code = "UNIQUE_VIOLATION"
+	ValidationFailed   oerror.Code = "VALIDATION_FAILED"
+	PreconditionFailed oerror.Code = "PRECONDITION_FAILED"
+	Unauthenticated    oerror.Code = "UNAUTHENTICATED"
+	Forbidden          oerror.Code = "FORBIDDEN"
+	InternalError      oerror.Code = "INTERNAL_ERROR"
 )
 
 var CustomCodeToHttpCodeMapping = map[oerror.Code]http.StatusCode{
 	oerror.RateLimitError: http.StatusTooManyRequests,
 	oerror.RequestInvalid: http.StatusBadRequest,
+	NotFound:              http.StatusNotFound,
+	Conflict:              http.StatusConflict,
+	UniqueViolation:       http.StatusConflict,
+	ValidationFailed:      http.StatusUnprocessableEntity,
+	PreconditionFailed:    http.StatusPreconditionFailed,
+	Unauthenticated:       http.StatusUnauthorized,
+	Forbidden:             http.StatusForbidden,
+	InternalError:         http.StatusInternalServerError,
+}
+
+// HttpStatus is the HTTP status of an error code; unknown codes are internal errors.
+func HttpStatus(code oerror.Code) http.StatusCode {
+	if status, ok := CustomCodeToHttpCodeMapping[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// CustomCodeToGRPCCodeMapping is the gRPC counterpart of CustomCodeToHttpCodeMapping.
+var CustomCodeToGRPCCodeMapping = map[oerror.Code]codes.Code{
+	oerror.RateLimitError: codes.ResourceExhausted,
+	oerror.RequestInvalid: codes.InvalidArgument,
+	NotFound:              codes.NotFound,
+	Conflict:              codes.FailedPrecondition,
+	UniqueViolation:       codes.AlreadyExists,
+	ValidationFailed:      codes.FailedPrecondition,
+	PreconditionFailed:    codes.Aborted,
+	Unauthenticated:       codes.Unauthenticated,
+	Forbidden:             codes.PermissionDenied,
+	InternalError:         codes.Internal,
+}
+
+// GRPCCode is the gRPC status code of an error code; unknown codes are internal errors.
+func GRPCCode(code oerror.Code) codes.Code {
+	if c, ok := CustomCodeToGRPCCodeMapping[code]; ok {
+		return c
+	}
+	return codes.Internal
 }
 
 func Initialize() {
@@ -0,0 +1,72 @@
+// Package keyset pages listings ordered by ID, newest first, with keyset cursors, for the
+// logs and queues that are not sorted otherwise: audit entries, cycle counts, webhook
+// subscriptions and deliveries, and dead letters. The cursors and meta block look like
+// those of inventory listings.
+package keyset
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// ErrInvalidCursor is returned for cursors that are not the next_cursor of a listing.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page asks for up to Limit rows after Cursor, the NextCursor of the previous page.
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// Meta describes a returned page. NextCursor is empty on the last page. Total counts
+// every match of the filter; it is only computed for the first page.
+type Meta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+type cursor struct {
+	Before int64 `json:"before"`
+}
+
+// Before defaults an unset limit, caps it at MaxLimit and returns the ID rows of the page
+// are below, 0 on the first page.
+func (p *Page) Before() (int64, error) {
+	switch {
+	case p.Limit <= 0:
+		p.Limit = DefaultLimit
+	case p.Limit > MaxLimit:
+		p.Limit = MaxLimit
+	}
+	if p.Cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Before <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return c.Before, nil
+}
+
+// Trim cuts rows, fetched with a limit of p.Limit+1, down to the page and returns its meta.
+// id returns the ID of a row.
+func Trim[T any](p Page, rows []T, id func(T) int64) ([]T, *Meta) {
+	meta := &Meta{Limit: p.Limit}
+	if len(rows) > p.Limit {
+		rows = rows[:p.Limit]
+		raw, _ := json.Marshal(cursor{Before: id(rows[len(rows)-1])})
+		meta.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	return rows, meta
+}
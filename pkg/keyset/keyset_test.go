@@ -0,0 +1,43 @@
+package keyset
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPageLimits(t *testing.T) {
+	cases := map[int]int{0: DefaultLimit, -1: DefaultLimit, 10: 10, MaxLimit + 1: MaxLimit}
+	for limit, want := range cases {
+		p := Page{Limit: limit}
+		if before, err := p.Before(); err != nil || before != 0 || p.Limit != want {
+			t.Errorf("limit %d: got %d before %d, %v, want limit %d on the first page", limit, p.Limit, before, err, want)
+		}
+	}
+}
+
+func TestTrimAndResume(t *testing.T) {
+	ids := []int64{9, 8, 7, 6}
+	p := Page{Limit: 3}
+	page, meta := Trim(p, ids, func(id int64) int64 { return id })
+	if len(page) != 3 || meta.NextCursor == "" || meta.Limit != 3 {
+		t.Fatalf("got %v, %+v, want 3 rows and a next cursor", page, meta)
+	}
+
+	next := Page{Limit: 3, Cursor: meta.NextCursor}
+	before, err := next.Before()
+	if err != nil || before != 7 {
+		t.Fatalf("got before %d, %v, want rows below 7", before, err)
+	}
+	if _, meta := Trim(next, ids[3:], func(id int64) int64 { return id }); meta.NextCursor != "" {
+		t.Fatalf("last page: got next cursor %q", meta.NextCursor)
+	}
+}
+
+func TestInvalidCursors(t *testing.T) {
+	for _, c := range []string{"not base64!", "bm90IGpzb24", "eyJiZWZvcmUiOjB9"} {
+		p := Page{Cursor: c}
+		if _, err := p.Before(); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q: got %v, want ErrInvalidCursor", c, err)
+		}
+	}
+}
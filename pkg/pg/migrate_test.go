@@ -0,0 +1,37 @@
+package pg
+
+import "testing"
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 || migrations[0].Name != "baseline" {
+		t.Fatalf("got %d migrations, want the baseline first", len(migrations))
+	}
+	for i, m := range migrations {
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d_%s is out of order", m.Version, m.Name)
+		}
+		if m.Up == "" || m.Down == "" {
+			t.Errorf("migration %d_%s is missing a file", m.Version, m.Name)
+		}
+	}
+	if migrations[0].Reversible() {
+		t.Error("the baseline is reversible")
+	}
+}
+
+func TestReversible(t *testing.T) {
+	cases := map[string]bool{
+		"":                                       false,
+		"-- Cannot be reverted.\n\n  -- Really.": false,
+		"-- Drop the index.\nDROP INDEX hubs_name_idx;": true,
+	}
+	for down, want := range cases {
+		if got := (&Migration{Down: down}).Reversible(); got != want {
+			t.Errorf("Reversible(%q) = %v, want %v", down, got, want)
+		}
+	}
+}
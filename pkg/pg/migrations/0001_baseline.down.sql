@@ -0,0 +1,2 @@
+-- The baseline cannot be reverted: it would drop every table and the data in them.
+-- Restore a backup, or drop the schema by hand, to start over.
@@ -0,0 +1,211 @@
+-- Schema as created by initIMSTables before versioned migrations. Every statement is
+-- idempotent so databases created that way can be migrated from here.
+
+CREATE TABLE IF NOT EXISTS hubs (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    address TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE hubs
+    ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'warehouse',
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS operating_hours JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS contact_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS contact_phone VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS contact_email VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_hubs_status ON hubs (status);
+
+ALTER TABLE hubs ADD COLUMN IF NOT EXISTS tenant_id INT;
+
+CREATE INDEX IF NOT EXISTS idx_hubs_tenant_id ON hubs (tenant_id);
+
+ALTER TABLE hubs ADD COLUMN IF NOT EXISTS service_area JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_hubs_coordinates ON hubs (latitude, longitude);
+
+ALTER TABLE hubs
+    ADD COLUMN IF NOT EXISTS capacity DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS capacity_unit VARCHAR(20) NOT NULL DEFAULT 'units',
+    ADD COLUMN IF NOT EXISTS capacity_policy VARCHAR(10) NOT NULL DEFAULT 'reject';
+
+CREATE TABLE IF NOT EXISTS postcodes (
+    postcode VARCHAR(20) PRIMARY KEY,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS skus (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    seller_id INT NOT NULL,
+    sku_code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, seller_id, sku_code)
+);
+
+ALTER TABLE skus
+    ADD COLUMN IF NOT EXISTS length_cm DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS width_cm DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS height_cm DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS units_per_pallet INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS inventory (
+    id SERIAL PRIMARY KEY,
+    hub_id INT NOT NULL REFERENCES hubs(id) ON DELETE CASCADE,
+    sku_id INT NOT NULL REFERENCES skus(id) ON DELETE CASCADE,
+    quantity INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hub_id, sku_id)
+);
+
+CREATE TABLE IF NOT EXISTS inventory_counts (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    hub_id INT NOT NULL REFERENCES hubs(id) ON DELETE CASCADE,
+    sku_id INT NOT NULL REFERENCES skus(id) ON DELETE CASCADE,
+    counted_qty INT NOT NULL,
+    system_qty INT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    counted_by VARCHAR(64) NOT NULL DEFAULT '',
+    resolved_by VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_counts_hub_status ON inventory_counts (hub_id, status);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    actor_id VARCHAR(64) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    before JSONB,
+    after JSONB,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_tenant_entity ON audit_logs (tenant_id, entity_type, entity_id);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_tenant_created_at ON audit_logs (tenant_id, created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    tenant_id INT NOT NULL,
+    aggregate_type VARCHAR(20) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    topic VARCHAR(255) NOT NULL,
+    partition_key VARCHAR(64) NOT NULL,
+    data JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (id) WHERE published_at IS NULL;
+
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS webhooks_enqueued_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_webhooks_pending ON outbox_events (id) WHERE webhooks_enqueued_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    low_stock_threshold INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_tenant_id ON webhook_subscriptions (tenant_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    tenant_id INT NOT NULL,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INT,
+    last_error TEXT NOT NULL DEFAULT '',
+    replay_of BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id);
+
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS reserved INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS order_reservations (
+    tenant_id INT NOT NULL,
+    order_id VARCHAR(64) NOT NULL,
+    hub_id INT NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, order_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_reservation_items (
+    tenant_id INT NOT NULL,
+    order_id VARCHAR(64) NOT NULL,
+    sku_id INT NOT NULL,
+    quantity INT NOT NULL,
+    PRIMARY KEY (tenant_id, order_id, sku_id),
+    FOREIGN KEY (tenant_id, order_id) REFERENCES order_reservations (tenant_id, order_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    handler VARCHAR(64) NOT NULL,
+    topic VARCHAR(255) NOT NULL,
+    message_key TEXT NOT NULL DEFAULT '',
+    headers JSONB NOT NULL DEFAULT '{}',
+    payload BYTEA NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    replayed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_status ON dead_letters (status, handler);
+
+CREATE TABLE IF NOT EXISTS seller_statuses (
+    tenant_id INT NOT NULL,
+    seller_id INT NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, seller_id)
+);
+
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
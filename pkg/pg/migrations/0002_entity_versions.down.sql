@@ -0,0 +1,3 @@
+ALTER TABLE skus DROP COLUMN IF EXISTS version;
+
+ALTER TABLE hubs DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+-- Versions for optimistic concurrency. Inventory rows have had one since the balance cache.
+ALTER TABLE hubs ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE skus ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
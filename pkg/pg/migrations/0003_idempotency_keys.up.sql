@@ -0,0 +1,15 @@
+-- Idempotency-Key records of mutating API requests and the responses they produced.
+-- A row without response_status belongs to a request still being handled.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    tenant_id INT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_status INT,
+    response_content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
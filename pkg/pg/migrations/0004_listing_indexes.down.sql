@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_inventory_hub_updated_at;
+DROP INDEX IF EXISTS idx_inventory_hub_quantity;
+
+DROP INDEX IF EXISTS idx_skus_tenant_updated_at;
+DROP INDEX IF EXISTS idx_skus_tenant_created_at;
+DROP INDEX IF EXISTS idx_skus_tenant_name;
+
+DROP INDEX IF EXISTS idx_hubs_tenant_updated_at;
+DROP INDEX IF EXISTS idx_hubs_tenant_created_at;
+DROP INDEX IF EXISTS idx_hubs_tenant_name;
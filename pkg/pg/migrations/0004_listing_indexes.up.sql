@@ -0,0 +1,11 @@
+-- Keyset pagination of hub, SKU and hub stock listings by their sortable fields.
+CREATE INDEX IF NOT EXISTS idx_hubs_tenant_name ON hubs (tenant_id, name, id);
+CREATE INDEX IF NOT EXISTS idx_hubs_tenant_created_at ON hubs (tenant_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_hubs_tenant_updated_at ON hubs (tenant_id, updated_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_skus_tenant_name ON skus (tenant_id, name, id);
+CREATE INDEX IF NOT EXISTS idx_skus_tenant_created_at ON skus (tenant_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_skus_tenant_updated_at ON skus (tenant_id, updated_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_hub_quantity ON inventory (hub_id, quantity, sku_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_hub_updated_at ON inventory (hub_id, updated_at, sku_id);
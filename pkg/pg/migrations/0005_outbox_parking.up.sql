@@ -0,0 +1,8 @@
+-- Outbox relays claim events for a while before publishing them outside the claiming
+-- transaction, and park events that keep failing so they stop holding back their key.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP;
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS parked_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (id) WHERE published_at IS NULL AND parked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_parked ON outbox_events (id) WHERE parked_at IS NOT NULL;
@@ -0,0 +1,2 @@
+UPDATE order_reservations SET hub_id = 0 WHERE hub_id IS NULL;
+ALTER TABLE order_reservations ALTER COLUMN hub_id SET NOT NULL;
@@ -0,0 +1,3 @@
+-- Orders cancelled before they were created are recorded without a hub.
+ALTER TABLE order_reservations ALTER COLUMN hub_id DROP NOT NULL;
+UPDATE order_reservations SET hub_id = NULL WHERE hub_id = 0;
@@ -0,0 +1 @@
+-- The backfill cannot be reverted: which hubs it assigned is not recorded.
@@ -0,0 +1,19 @@
+-- Assign a tenant to hubs created before hubs were tenant scoped. A hub takes the tenant
+-- of the SKUs it stocks when they all belong to one tenant.
+UPDATE hubs h
+SET tenant_id = owner.tenant_id
+FROM (
+    SELECT i.hub_id, MIN(s.tenant_id) AS tenant_id
+    FROM inventory i
+    JOIN skus s ON s.id = i.sku_id
+    GROUP BY i.hub_id
+    HAVING COUNT(DISTINCT s.tenant_id) = 1
+) owner
+WHERE h.id = owner.hub_id AND h.tenant_id IS NULL;
+
+-- The remaining hubs go to the tenant in the ims.default_hub_tenant_id setting, set with
+-- ALTER DATABASE ... SET ims.default_hub_tenant_id = '<id>' before migrating. Without it
+-- they stay unassigned, invisible to every tenant, until fixed by hand.
+UPDATE hubs
+SET tenant_id = NULLIF(current_setting('ims.default_hub_tenant_id', true), '')::INT
+WHERE tenant_id IS NULL;
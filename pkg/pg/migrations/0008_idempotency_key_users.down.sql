@@ -0,0 +1,6 @@
+-- Keys of different users may clash once the user is dropped; stored responses are only
+-- kept for the idempotency TTL, so they are dropped rather than merged.
+DELETE FROM idempotency_keys;
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS user_id;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, key);
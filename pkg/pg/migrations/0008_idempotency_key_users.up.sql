@@ -0,0 +1,5 @@
+-- Idempotency keys are scoped to the user as well as the tenant, so a stored response is
+-- only replayed to the user whose request produced it.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS user_id VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, user_id, key);
@@ -0,0 +1,11 @@
+-- Tenants may disagree on a postcode once the table is shared again; the most recently
+-- updated centroid is kept.
+DELETE FROM postcodes
+WHERE ctid NOT IN (
+    SELECT DISTINCT ON (postcode) ctid
+    FROM postcodes
+    ORDER BY postcode, updated_at DESC NULLS LAST
+);
+ALTER TABLE postcodes DROP CONSTRAINT IF EXISTS postcodes_pkey;
+ALTER TABLE postcodes DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE postcodes ADD PRIMARY KEY (postcode);
@@ -0,0 +1,15 @@
+-- Postcode centroids belong to the tenant that loaded them, so one tenant's admins cannot
+-- move the centroids another tenant's nearest-hub lookups rely on. The rows loaded while
+-- the table was shared are copied to every tenant with hubs.
+ALTER TABLE postcodes ADD COLUMN IF NOT EXISTS tenant_id INT;
+ALTER TABLE postcodes DROP CONSTRAINT IF EXISTS postcodes_pkey;
+
+INSERT INTO postcodes (tenant_id, postcode, latitude, longitude, updated_at)
+SELECT t.tenant_id, p.postcode, p.latitude, p.longitude, p.updated_at
+FROM postcodes p
+CROSS JOIN (SELECT DISTINCT tenant_id FROM hubs WHERE tenant_id IS NOT NULL) t
+WHERE p.tenant_id IS NULL;
+
+DELETE FROM postcodes WHERE tenant_id IS NULL;
+ALTER TABLE postcodes ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE postcodes ADD PRIMARY KEY (tenant_id, postcode);
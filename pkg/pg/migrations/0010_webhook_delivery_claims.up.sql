@@ -0,0 +1,4 @@
+-- Counts the claims of a delivery. A dispatcher only records the outcome of an attempt
+-- while the delivery's claim count is the one it claimed it with, so a dispatcher whose
+-- claim expired cannot overwrite the outcome of the one that claimed the delivery next.
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS claims INT NOT NULL DEFAULT 0;
@@ -5,13 +5,19 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/lib/pq"
+	"github.com/omniful/go_commons/config"
 )
 
 type Postgres struct {
 	*sql.DB
+	replicas []*sql.DB
+	next     atomic.Uint64
 }
 
 var postgresInstance *Postgres
@@ -22,21 +28,68 @@ func GetClient() *Postgres {
 }
 
 // SetClient sets the Postgres singleton instance
-func SetClient(client *sql.DB) {
-	postgresInstance = &Postgres{client}
+func SetClient(client *sql.DB, replicas ...*sql.DB) {
+	postgresInstance = &Postgres{DB: client, replicas: replicas}
 }
 
-// GetPostgresConfigFromEnv retrieves Postgres connection parameters from environment variables.
-func getPostgresConfigFromEnv() (host, port, user, password, dbname, sslmode string) {
-	host = getenvOrDefault("DB_HOST", "localhost")
-	port = getenvOrDefault("DB_PORT", "5432")
-	user = getenvOrDefault("DB_USER", "postgres")
-	password = getenvOrDefault("DB_PASSWORD", "postgres")
-	dbname = getenvOrDefault("DB_NAME", "postgres")
-	sslmode = getenvOrDefault("DB_SSLMODE", "disable") // default for local/dev
+// Reader returns a read replica, in turn, or the primary when there are none. Replicas may
+// lag the primary, so use it only for reads that need not see the caller's own writes.
+func (p *Postgres) Reader() *sql.DB {
+	if len(p.replicas) == 0 {
+		return p.DB
+	}
+	return p.replicas[p.next.Add(1)%uint64(len(p.replicas))]
+}
+
+type poolConfig struct {
+	maxOpen     int
+	maxIdle     int
+	maxLifetime time.Duration
+}
+
+// getPostgresConfig reads connection parameters from databases.postgres in config.yaml.
+// DB_* environment variables override the file.
+func getPostgresConfig(ctx context.Context) (dsn string, pool poolConfig) {
+	const prefix = "databases.postgres."
+	dsn = fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		getenvOrDefault("DB_HOST", configOrDefault(ctx, prefix+"host", "localhost")),
+		getenvOrDefault("DB_PORT", configOrDefault(ctx, prefix+"port", "5432")),
+		getenvOrDefault("DB_USER", configOrDefault(ctx, prefix+"user", "postgres")),
+		getenvOrDefault("DB_PASSWORD", config.GetString(ctx, prefix+"password")),
+		getenvOrDefault("DB_NAME", configOrDefault(ctx, prefix+"dbname", "postgres")),
+		getenvOrDefault("DB_SSLMODE", configOrDefault(ctx, prefix+"sslmode", "disable")), // default for local/dev
+	)
+
+	pool = poolConfig{maxOpen: 10, maxIdle: 5, maxLifetime: time.Hour}
+	if v, err := strconv.Atoi(getenvOrDefault("DB_MAX_CONNECTIONS", config.GetString(ctx, prefix+"pool.max_connections"))); err == nil && v > 0 {
+		pool.maxOpen = v
+	}
+	if v, err := strconv.Atoi(getenvOrDefault("DB_MAX_IDLE_CONNECTIONS", config.GetString(ctx, prefix+"pool.max_idle_connections"))); err == nil && v >= 0 {
+		pool.maxIdle = v
+	}
+	if v, err := time.ParseDuration(getenvOrDefault("DB_MAX_LIFETIME", config.GetString(ctx, prefix+"pool.max_lifetime"))); err == nil && v > 0 {
+		pool.maxLifetime = v
+	}
 	return
 }
 
+// getReplicaDSNs returns the read replica DSNs from databases.postgres.replicas, or from
+// DB_REPLICA_DSNS, comma separated, when set.
+func getReplicaDSNs(ctx context.Context) []string {
+	v, ok := os.LookupEnv("DB_REPLICA_DSNS")
+	if !ok {
+		return config.GetStringSlice(ctx, "databases.postgres.replicas")
+	}
+	var dsns []string
+	for _, dsn := range strings.Split(v, ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+	return dsns
+}
+
 // getenvOrDefault returns the value of the environment variable if set, otherwise returns the default.
 func getenvOrDefault(key, def string) string {
 	if v, ok := os.LookupEnv(key); ok {
@@ -45,73 +98,53 @@ func getenvOrDefault(key, def string) string {
 	return def
 }
 
-// ConnectAndInit connects to Postgres and initializes IMS tables if not present.
+func configOrDefault(ctx context.Context, key, def string) string {
+	if v := config.GetString(ctx, key); v != "" {
+		return v
+	}
+	return def
+}
+
+// PgConnect connects to the Postgres primary. The schema is managed separately, see
+// MigrateUp.
 func PgConnect(ctx context.Context) (*sql.DB, error) {
-	host, port, user, password, dbname, sslmode := getPostgresConfigFromEnv()
+	dsn, pool := getPostgresConfig(ctx)
+	return open(ctx, dsn, pool)
+}
 
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode,
-	)
+// ConnectReplicas connects to the configured read replicas, which share the primary's
+// pool settings.
+func ConnectReplicas(ctx context.Context) ([]*sql.DB, error) {
+	_, pool := getPostgresConfig(ctx)
+	var replicas []*sql.DB
+	for i, dsn := range getReplicaDSNs(ctx) {
+		db, err := open(ctx, dsn, pool)
+		if err != nil {
+			for _, r := range replicas {
+				r.Close()
+			}
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		replicas = append(replicas, db)
+	}
+	return replicas, nil
+}
 
+func open(ctx context.Context, dsn string, pool poolConfig) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open DB connection: %w", err)
 	}
 
-	// Optional: Set connection pool configs
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(time.Hour)
+	db.SetMaxOpenConns(pool.maxOpen)
+	db.SetMaxIdleConns(pool.maxIdle)
+	db.SetConnMaxLifetime(pool.maxLifetime)
 
 	// Verify connection
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping DB: %w", err)
 	}
 
-	// Initialize IMS tables
-	if err := initIMSTables(db); err != nil {
-		return nil, fmt.Errorf("failed to initialize IMS tables: %w", err)
-	}
-
 	return db, nil
 }
-
-// initIMSTables creates tables if they do not exist
-func initIMSTables(db *sql.DB) error {
-	stmts := []string{
-		`CREATE TABLE IF NOT EXISTS hubs (
-			id SERIAL PRIMARY KEY,
-			name VARCHAR(100) NOT NULL,
-			address TEXT,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		);`,
-		`CREATE TABLE IF NOT EXISTS skus (
-			id SERIAL PRIMARY KEY,
-			tenant_id INT NOT NULL,
-			seller_id INT NOT NULL,
-			sku_code VARCHAR(50) NOT NULL,
-			name VARCHAR(255) NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE (tenant_id, seller_id, sku_code)
-		);`,
-		`CREATE TABLE IF NOT EXISTS inventory (
-			id SERIAL PRIMARY KEY,
-			hub_id INT NOT NULL REFERENCES hubs(id) ON DELETE CASCADE,
-			sku_id INT NOT NULL REFERENCES skus(id) ON DELETE CASCADE,
-			quantity INT NOT NULL DEFAULT 0,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE (hub_id, sku_id)
-		);`,
-	}
-
-	for _, stmt := range stmts {
-		if _, err := db.Exec(stmt); err != nil {
-			return fmt.Errorf("failed executing statement: %v, error: %w", stmt, err)
-		}
-	}
-
-	return nil
-}
@@ -0,0 +1,46 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestReaderRotatesReplicas(t *testing.T) {
+	open := func() *sql.DB {
+		// sql.Open does not connect, so no database is needed.
+		db, err := sql.Open("postgres", "host=localhost")
+		if err != nil {
+			t.Fatalf("sql.Open: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+	primary, first, second := open(), open(), open()
+
+	if got := (&Postgres{DB: primary}).Reader(); got != primary {
+		t.Fatal("without replicas, reads did not go to the primary")
+	}
+	p := &Postgres{DB: primary, replicas: []*sql.DB{first, second}}
+	seen := map[*sql.DB]int{}
+	for i := 0; i < 4; i++ {
+		seen[p.Reader()]++
+	}
+	if seen[first] != 2 || seen[second] != 2 {
+		t.Fatalf("got %d and %d reads, want them spread evenly over the replicas", seen[first], seen[second])
+	}
+}
+
+func TestReplicaDSNsFromTheEnvironment(t *testing.T) {
+	t.Setenv("DB_REPLICA_DSNS", " host=replica-1 , ,host=replica-2")
+	want := []string{"host=replica-1", "host=replica-2"}
+	if got := getReplicaDSNs(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	t.Setenv("DB_REPLICA_DSNS", "")
+	if got := getReplicaDSNs(context.Background()); len(got) != 0 {
+		t.Fatalf("an empty DB_REPLICA_DSNS: got %q, want no replicas", got)
+	}
+}
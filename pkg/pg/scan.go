@@ -0,0 +1,22 @@
+package pg
+
+// RowScanner is satisfied by *sql.Row and *sql.Rows.
+type RowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// extraColumnsScanner scans columns selected after an entity's own columns into extra.
+type extraColumnsScanner struct {
+	RowScanner
+	extra []interface{}
+}
+
+func (s extraColumnsScanner) Scan(dest ...interface{}) error {
+	return s.RowScanner.Scan(append(dest, s.extra...)...)
+}
+
+// WithExtraColumns lets an entity's scan function read a row that selects more columns
+// after the entity's own, scanning those into extra.
+func WithExtraColumns(row RowScanner, extra ...interface{}) RowScanner {
+	return extraColumnsScanner{RowScanner: row, extra: extra}
+}
@@ -0,0 +1,54 @@
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+// Error is the error part of ErrorResponse. Code is one of the codes in pkg/error and
+// Errors carries details such as field-level validation errors.
+type Error struct {
+	Code    oerror.Code `json:"code"`
+	Message string      `json:"message"`
+	Errors  interface{} `json:"errors,omitempty"`
+}
+
+// ErrorResponse is the error counterpart of AccessControlSuccessResponse.
+type ErrorResponse struct {
+	IsSuccess  bool  `json:"is_success"`
+	StatusCode int   `json:"status_code"`
+	Error      Error `json:"error"`
+}
+
+// NewSuccessResponse writes data in the AccessControlSuccessResponse envelope. Use the
+// Handler methods instead for data that must be checked against the caller's access.
+func NewSuccessResponse(ctx *gin.Context, statusCode int, data interface{}) {
+	NewSuccessResponseWithMeta(ctx, statusCode, data, nil)
+}
+
+// NewSuccessResponseWithMeta writes data and meta, such as paging details, in the
+// AccessControlSuccessResponse envelope.
+func NewSuccessResponseWithMeta(ctx *gin.Context, statusCode int, data interface{}, meta interface{}) {
+	ctx.AbortWithStatusJSON(statusCode, &AccessControlSuccessResponse{
+		IsSuccess:  true,
+		StatusCode: statusCode,
+		Data:       data,
+		Meta:       meta,
+	})
+}
+
+// NewErrorResponse writes cusErr in the error envelope, with the HTTP status its code maps
+// to in pkg/error.CustomCodeToHttpCodeMapping. details may be nil.
+func NewErrorResponse(ctx *gin.Context, cusErr oerror.CustomError, details interface{}) {
+	statusCode := pkgerror.HttpStatus(cusErr.ErrorCode()).Code()
+	ctx.AbortWithStatusJSON(statusCode, &ErrorResponse{
+		IsSuccess:  false,
+		StatusCode: statusCode,
+		Error: Error{
+			Code:    cusErr.ErrorCode(),
+			Message: cusErr.ErrorMessage(),
+			Errors:  details,
+		},
+	})
+}
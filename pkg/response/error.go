@@ -2,9 +2,11 @@ package response
 
 import (
 	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
 	"github.com/omniful/go_commons/http"
 	interservice_client "github.com/omniful/go_commons/interservice-client"
 	"github.com/omniful/go_commons/response"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
 )
 
 func (r *Handler) NewErrorResponseByInterServiceError(ctx *gin.Context, error *interservice_client.Error) {
@@ -35,14 +37,38 @@ func (r *Handler) NewErrorWithDataResponseByInterServiceError(ctx *gin.Context,
 }
 
 func (r *Handler) NewErrorResponseByStatusCode(ctx *gin.Context, statusCode http.StatusCode) {
-	res := &response.ErrorResponse{
+	res := &ErrorResponse{
 		IsSuccess:  false,
 		StatusCode: statusCode.Code(),
-		Error: response.Error{
+		Error: Error{
+			Code:    codeForStatus(statusCode),
 			Message: statusCode.String(),
 		},
 	}
 
 	ctx.AbortWithStatusJSON(statusCode.Code(), res)
-	ctx.AbortWithStatusJSON(statusCode.Code(), res)
+}
+
+// codeForStatus is the error code of responses written by HTTP status alone.
+func codeForStatus(statusCode http.StatusCode) oerror.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return oerror.RequestInvalid
+	case http.StatusUnauthorized:
+		return pkgerror.Unauthenticated
+	case http.StatusForbidden:
+		return pkgerror.Forbidden
+	case http.StatusNotFound:
+		return pkgerror.NotFound
+	case http.StatusConflict:
+		return pkgerror.Conflict
+	case http.StatusPreconditionFailed:
+		return pkgerror.PreconditionFailed
+	case http.StatusUnprocessableEntity:
+		return pkgerror.ValidationFailed
+	case http.StatusTooManyRequests:
+		return oerror.RateLimitError
+	default:
+		return pkgerror.InternalError
+	}
 }
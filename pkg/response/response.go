@@ -1,15 +1,13 @@
 package response
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/omniful/go_commons/http"
 	"github.com/omniful/go_commons/jwt/public"
-	"github.com/omniful/go_commons/response"
 	"github.com/omniful/go_commons/util"
-	"github.com/omniful/ims_rohit/internal/access_control"
-	"github.com/omniful/ims_rohit/internal/hub"
-	"github.com/omniful/ims_rohit/internal/seller"
 )
 
 type AccessControlData interface {
@@ -24,51 +22,50 @@ type AccessControlSuccessResponse struct {
 	Meta       interface{} `json:"meta"`
 }
 
-// Handler holds the caches needed for access control validation
+// AccessValidator checks hub and seller IDs against the caller's access. It is
+// implemented by access_control.AccessControl, whose middlewares answer with the error
+// envelope of this package.
+type AccessValidator interface {
+	ValidateHubIDs(ctx context.Context, tenantID string, hubIDs []string) (bool, error)
+	ValidateSellerIDs(ctx context.Context, tenantID string, sellerIDs []string) (bool, error)
+}
+
+// Handler checks the hubs and sellers of response data against the caller's access.
 type Handler struct {
-	accessControl *access_control.AccessControl
+	accessControl AccessValidator
 }
 
-// NewResponseHandler creates a new ResponseHandler with initialized caches
-func NewResponseHandler(hubCache *hub.Cache, sellerCache *seller.Cache) *Handler {
-	return &Handler{
-		accessControl: access_control.NewAccessControl(hubCache, sellerCache),
-	}
+func NewResponseHandler(accessControl AccessValidator) *Handler {
+	return &Handler{accessControl: accessControl}
 }
 
+// NewAccessControlSuccessResponse writes data in the AccessControlSuccessResponse envelope
+// once the caller is found to have access to its hubs and sellers.
 func (r *Handler) NewAccessControlSuccessResponse(ctx *gin.Context, data AccessControlData) {
-	isValid, err := r.validateResponse(ctx, data)
-	if err != nil || !isValid {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError.Code(), err)
-		return
-	}
+	r.NewAccessControlSuccessResponseWithMeta(ctx, data, nil)
+}
 
-	res := &response.SuccessResponse{
-		IsSuccess:  true,
-		StatusCode: http.StatusOK.Code(),
-		Data:       data,
+func (r *Handler) NewAccessControlSuccessResponseWithMeta(ctx *gin.Context, data AccessControlData, meta interface{}) {
+	if !r.Authorize(ctx, data) {
+		return
 	}
 
-	ctx.AbortWithStatusJSON(http.StatusOK.Code(), res)
-	return
+	NewSuccessResponseWithMeta(ctx, http.StatusOK.Code(), data, meta)
 }
 
-func (r *Handler) NewAccessControlSuccessResponseWithMeta(ctx *gin.Context, data AccessControlData, meta interface{}) {
+// Authorize checks the hubs and sellers referenced by data against the caller's access. On
+// failure it writes a 403 (or a 500 if validation itself failed) and returns false.
+func (r *Handler) Authorize(ctx *gin.Context, data AccessControlData) bool {
 	isValid, err := r.validateResponse(ctx, data)
-	if err != nil || !isValid {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError.Code(), err)
-		return
+	if err != nil {
+		r.NewErrorResponseByStatusCode(ctx, http.StatusInternalServerError)
+		return false
 	}
-
-	res := &response.SuccessResponse{
-		IsSuccess:  true,
-		StatusCode: http.StatusOK.Code(),
-		Data:       data,
-		Meta:       meta,
+	if !isValid {
+		r.NewErrorResponseByStatusCode(ctx, http.StatusForbidden)
+		return false
 	}
-
-	ctx.AbortWithStatusJSON(http.StatusOK.Code(), res)
-	return
+	return true
 }
 
 func (r *Handler) validateResponse(ctx *gin.Context, data AccessControlData) (bool, error) {
@@ -0,0 +1,66 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	oerror "github.com/omniful/go_commons/error"
+	pkgerror "github.com/omniful/ims_rohit/pkg/error"
+)
+
+// record runs write on a test context and decodes the body it wrote.
+func record(t *testing.T, write func(c *gin.Context)) (int, map[string]json.RawMessage) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	write(c)
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+	return w.Code, body
+}
+
+func TestSuccessEnvelopeAlwaysCarriesMeta(t *testing.T) {
+	status, body := record(t, func(c *gin.Context) { NewSuccessResponse(c, http.StatusCreated, map[string]int{"id": 1}) })
+	if status != http.StatusCreated || string(body["status_code"]) != "201" || string(body["is_success"]) != "true" {
+		t.Fatalf("got %d, %v", status, body)
+	}
+	if meta, ok := body["meta"]; !ok || string(meta) != "null" {
+		t.Errorf("got meta %s, want null", meta)
+	}
+
+	_, body = record(t, func(c *gin.Context) {
+		NewSuccessResponseWithMeta(c, http.StatusOK, []int{1}, map[string]int{"limit": 1})
+	})
+	if string(body["meta"]) != `{"limit":1}` || string(body["data"]) != `[1]` {
+		t.Errorf("got %v, want data and meta", body)
+	}
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	status, body := record(t, func(c *gin.Context) {
+		NewErrorResponse(c, oerror.NewCustomError(pkgerror.NotFound, "hub not found"), []string{"hub_id"})
+	})
+	if status != http.StatusNotFound || string(body["is_success"]) != "false" {
+		t.Fatalf("got %d, %v", status, body)
+	}
+	var e Error
+	if err := json.Unmarshal(body["error"], &e); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if e.Code != pkgerror.NotFound || e.Message != "hub not found" || e.Errors == nil {
+		t.Errorf("got %+v", e)
+	}
+
+	status, _ = record(t, func(c *gin.Context) {
+		NewErrorResponse(c, oerror.NewCustomError("SOMETHING_NEW", "unmapped"), nil)
+	})
+	if status != http.StatusInternalServerError {
+		t.Errorf("unmapped code: got %d, want 500", status)
+	}
+}
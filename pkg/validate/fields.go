@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	validatorPkg "github.com/go-playground/validator/v10"
+)
+
+// FieldError is one request field that failed binding or validation. Field is the field's
+// JSON, or query, name.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// FieldErrors describes a binding error of req field by field. It returns nil for errors
+// not tied to a field, such as malformed JSON.
+func FieldErrors(err error, req interface{}) []FieldError {
+	var validationErrs validatorPkg.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			name := fieldName(reflect.TypeOf(req), fe.StructNamespace())
+			fields[i] = FieldError{
+				Field:   name,
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: ruleMessage(name, fe.Tag(), fe.Param()),
+			}
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: typeErr.Field + " must be a " + typeErr.Type.String(),
+		}}
+	}
+	return nil
+}
+
+// NewFieldError describes field failing a validation rule, such as one of the validator's
+// tags or "type" with the expected JSON type as param.
+func NewFieldError(field, rule, param string) FieldError {
+	return FieldError{Field: field, Rule: rule, Param: param, Message: ruleMessage(field, rule, param)}
+}
+
+// fieldName turns a struct namespace such as Request.Page.Limit into the field's name in
+// the request, following json and form tags. Embedded structs add nothing to the name.
+func fieldName(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	var names []string
+	for _, part := range parts[1:] {
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		// Slice elements show up as Field[0].
+		fieldPart, index := part, ""
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			fieldPart, index = part[:i], part[i:]
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			names = append(names, part)
+			continue
+		}
+		f, ok := t.FieldByName(fieldPart)
+		if !ok {
+			names = append(names, part)
+			t = nil
+			continue
+		}
+		t = f.Type
+		if f.Anonymous {
+			continue
+		}
+		names = append(names, tagName(f)+index)
+	}
+	return strings.Join(names, ".")
+}
+
+func tagName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name := strings.Split(f.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+func ruleMessage(field, rule, param string) string {
+	switch rule {
+	case "required":
+		return field + " is required"
+	case "min", "gte":
+		return field + " must be at least " + param
+	case "max", "lte":
+		return field + " must be at most " + param
+	case "gt":
+		return field + " must be greater than " + param
+	case "lt":
+		return field + " must be less than " + param
+	case "oneof":
+		return field + " must be one of: " + strings.ReplaceAll(param, " ", ", ")
+	case "url":
+		return field + " must be a valid URL"
+	case "email":
+		return field + " must be a valid email address"
+	case "type":
+		return field + " must be of type " + param
+	case "format":
+		return field + " must be a valid " + param
+	case "latitude", "longitude":
+		return field + " must be a valid " + rule
+	default:
+		return field + " failed the " + rule + " rule"
+	}
+}
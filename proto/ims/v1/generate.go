@@ -0,0 +1,5 @@
+// Package imsv1 holds the messages and gRPC stubs generated from ims.proto. Servers and
+// clients of the gRPC API use them.
+package imsv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative ims/v1/ims.proto
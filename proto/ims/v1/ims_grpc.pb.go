@@ -0,0 +1,554 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: ims/v1/ims.proto
+
+// The hub, SKU and inventory services of the gRPC API, served by internal/grpcapi over the
+// same inventory package as the HTTP API. Calls are authenticated like API requests, from
+// the authorization and other headers in their metadata.
+
+package imsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Hubs_GetHub_FullMethodName   = "/ims.v1.Hubs/GetHub"
+	Hubs_ListHubs_FullMethodName = "/ims.v1.Hubs/ListHubs"
+)
+
+// HubsClient is the client API for Hubs service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HubsClient interface {
+	GetHub(ctx context.Context, in *GetHubRequest, opts ...grpc.CallOption) (*Hub, error)
+	ListHubs(ctx context.Context, in *ListHubsRequest, opts ...grpc.CallOption) (*ListHubsResponse, error)
+}
+
+type hubsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHubsClient(cc grpc.ClientConnInterface) HubsClient {
+	return &hubsClient{cc}
+}
+
+func (c *hubsClient) GetHub(ctx context.Context, in *GetHubRequest, opts ...grpc.CallOption) (*Hub, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Hub)
+	err := c.cc.Invoke(ctx, Hubs_GetHub_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubsClient) ListHubs(ctx context.Context, in *ListHubsRequest, opts ...grpc.CallOption) (*ListHubsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHubsResponse)
+	err := c.cc.Invoke(ctx, Hubs_ListHubs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HubsServer is the server API for Hubs service.
+// All implementations must embed UnimplementedHubsServer
+// for forward compatibility.
+type HubsServer interface {
+	GetHub(context.Context, *GetHubRequest) (*Hub, error)
+	ListHubs(context.Context, *ListHubsRequest) (*ListHubsResponse, error)
+	mustEmbedUnimplementedHubsServer()
+}
+
+// UnimplementedHubsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHubsServer struct{}
+
+func (UnimplementedHubsServer) GetHub(context.Context, *GetHubRequest) (*Hub, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHub not implemented")
+}
+func (UnimplementedHubsServer) ListHubs(context.Context, *ListHubsRequest) (*ListHubsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHubs not implemented")
+}
+func (UnimplementedHubsServer) mustEmbedUnimplementedHubsServer() {}
+func (UnimplementedHubsServer) testEmbeddedByValue()              {}
+
+// UnsafeHubsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HubsServer will
+// result in compilation errors.
+type UnsafeHubsServer interface {
+	mustEmbedUnimplementedHubsServer()
+}
+
+func RegisterHubsServer(s grpc.ServiceRegistrar, srv HubsServer) {
+	// If the following call pancis, it indicates UnimplementedHubsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Hubs_ServiceDesc, srv)
+}
+
+func _Hubs_GetHub_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubsServer).GetHub(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hubs_GetHub_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubsServer).GetHub(ctx, req.(*GetHubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hubs_ListHubs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHubsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubsServer).ListHubs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hubs_ListHubs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubsServer).ListHubs(ctx, req.(*ListHubsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Hubs_ServiceDesc is the grpc.ServiceDesc for Hubs service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Hubs_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ims.v1.Hubs",
+	HandlerType: (*HubsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHub",
+			Handler:    _Hubs_GetHub_Handler,
+		},
+		{
+			MethodName: "ListHubs",
+			Handler:    _Hubs_ListHubs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ims/v1/ims.proto",
+}
+
+const (
+	SKUs_GetSKU_FullMethodName             = "/ims.v1.SKUs/GetSKU"
+	SKUs_ListSKUs_FullMethodName           = "/ims.v1.SKUs/ListSKUs"
+	SKUs_CheckSKUsExistence_FullMethodName = "/ims.v1.SKUs/CheckSKUsExistence"
+)
+
+// SKUsClient is the client API for SKUs service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SKUsClient interface {
+	GetSKU(ctx context.Context, in *GetSKURequest, opts ...grpc.CallOption) (*SKU, error)
+	ListSKUs(ctx context.Context, in *ListSKUsRequest, opts ...grpc.CallOption) (*ListSKUsResponse, error)
+	CheckSKUsExistence(ctx context.Context, in *CheckSKUsExistenceRequest, opts ...grpc.CallOption) (*CheckSKUsExistenceResponse, error)
+}
+
+type sKUsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSKUsClient(cc grpc.ClientConnInterface) SKUsClient {
+	return &sKUsClient{cc}
+}
+
+func (c *sKUsClient) GetSKU(ctx context.Context, in *GetSKURequest, opts ...grpc.CallOption) (*SKU, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SKU)
+	err := c.cc.Invoke(ctx, SKUs_GetSKU_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sKUsClient) ListSKUs(ctx context.Context, in *ListSKUsRequest, opts ...grpc.CallOption) (*ListSKUsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSKUsResponse)
+	err := c.cc.Invoke(ctx, SKUs_ListSKUs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sKUsClient) CheckSKUsExistence(ctx context.Context, in *CheckSKUsExistenceRequest, opts ...grpc.CallOption) (*CheckSKUsExistenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckSKUsExistenceResponse)
+	err := c.cc.Invoke(ctx, SKUs_CheckSKUsExistence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SKUsServer is the server API for SKUs service.
+// All implementations must embed UnimplementedSKUsServer
+// for forward compatibility.
+type SKUsServer interface {
+	GetSKU(context.Context, *GetSKURequest) (*SKU, error)
+	ListSKUs(context.Context, *ListSKUsRequest) (*ListSKUsResponse, error)
+	CheckSKUsExistence(context.Context, *CheckSKUsExistenceRequest) (*CheckSKUsExistenceResponse, error)
+	mustEmbedUnimplementedSKUsServer()
+}
+
+// UnimplementedSKUsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSKUsServer struct{}
+
+func (UnimplementedSKUsServer) GetSKU(context.Context, *GetSKURequest) (*SKU, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSKU not implemented")
+}
+func (UnimplementedSKUsServer) ListSKUs(context.Context, *ListSKUsRequest) (*ListSKUsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSKUs not implemented")
+}
+func (UnimplementedSKUsServer) CheckSKUsExistence(context.Context, *CheckSKUsExistenceRequest) (*CheckSKUsExistenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSKUsExistence not implemented")
+}
+func (UnimplementedSKUsServer) mustEmbedUnimplementedSKUsServer() {}
+func (UnimplementedSKUsServer) testEmbeddedByValue()              {}
+
+// UnsafeSKUsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SKUsServer will
+// result in compilation errors.
+type UnsafeSKUsServer interface {
+	mustEmbedUnimplementedSKUsServer()
+}
+
+func RegisterSKUsServer(s grpc.ServiceRegistrar, srv SKUsServer) {
+	// If the following call pancis, it indicates UnimplementedSKUsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SKUs_ServiceDesc, srv)
+}
+
+func _SKUs_GetSKU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSKURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SKUsServer).GetSKU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SKUs_GetSKU_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SKUsServer).GetSKU(ctx, req.(*GetSKURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SKUs_ListSKUs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSKUsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SKUsServer).ListSKUs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SKUs_ListSKUs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SKUsServer).ListSKUs(ctx, req.(*ListSKUsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SKUs_CheckSKUsExistence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckSKUsExistenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SKUsServer).CheckSKUsExistence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SKUs_CheckSKUsExistence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SKUsServer).CheckSKUsExistence(ctx, req.(*CheckSKUsExistenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SKUs_ServiceDesc is the grpc.ServiceDesc for SKUs service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SKUs_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ims.v1.SKUs",
+	HandlerType: (*SKUsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSKU",
+			Handler:    _SKUs_GetSKU_Handler,
+		},
+		{
+			MethodName: "ListSKUs",
+			Handler:    _SKUs_ListSKUs_Handler,
+		},
+		{
+			MethodName: "CheckSKUsExistence",
+			Handler:    _SKUs_CheckSKUsExistence_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ims/v1/ims.proto",
+}
+
+const (
+	Inventory_ViewInventory_FullMethodName      = "/ims.v1.Inventory/ViewInventory"
+	Inventory_AdjustOne_FullMethodName          = "/ims.v1.Inventory/AdjustOne"
+	Inventory_ViewInventoryBatch_FullMethodName = "/ims.v1.Inventory/ViewInventoryBatch"
+	Inventory_AdjustInventory_FullMethodName    = "/ims.v1.Inventory/AdjustInventory"
+)
+
+// InventoryClient is the client API for Inventory service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InventoryClient interface {
+	ViewInventory(ctx context.Context, in *ViewInventoryRequest, opts ...grpc.CallOption) (*ViewInventoryResponse, error)
+	AdjustOne(ctx context.Context, in *Adjustment, opts ...grpc.CallOption) (*AdjustmentResult, error)
+	// ViewInventoryBatch answers each request of the stream in order. A request that fails
+	// gets a response carrying the error; the stream goes on.
+	ViewInventoryBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ViewInventoryRequest, ViewInventoryResponse], error)
+	// AdjustInventory applies each adjustment of the stream in order, each in its own
+	// transaction, and answers with its result. A failed adjustment does not end the stream.
+	AdjustInventory(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Adjustment, AdjustmentResult], error)
+}
+
+type inventoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryClient(cc grpc.ClientConnInterface) InventoryClient {
+	return &inventoryClient{cc}
+}
+
+func (c *inventoryClient) ViewInventory(ctx context.Context, in *ViewInventoryRequest, opts ...grpc.CallOption) (*ViewInventoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ViewInventoryResponse)
+	err := c.cc.Invoke(ctx, Inventory_ViewInventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryClient) AdjustOne(ctx context.Context, in *Adjustment, opts ...grpc.CallOption) (*AdjustmentResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdjustmentResult)
+	err := c.cc.Invoke(ctx, Inventory_AdjustOne_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryClient) ViewInventoryBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ViewInventoryRequest, ViewInventoryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Inventory_ServiceDesc.Streams[0], Inventory_ViewInventoryBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ViewInventoryRequest, ViewInventoryResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Inventory_ViewInventoryBatchClient = grpc.BidiStreamingClient[ViewInventoryRequest, ViewInventoryResponse]
+
+func (c *inventoryClient) AdjustInventory(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Adjustment, AdjustmentResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Inventory_ServiceDesc.Streams[1], Inventory_AdjustInventory_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Adjustment, AdjustmentResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Inventory_AdjustInventoryClient = grpc.BidiStreamingClient[Adjustment, AdjustmentResult]
+
+// InventoryServer is the server API for Inventory service.
+// All implementations must embed UnimplementedInventoryServer
+// for forward compatibility.
+type InventoryServer interface {
+	ViewInventory(context.Context, *ViewInventoryRequest) (*ViewInventoryResponse, error)
+	AdjustOne(context.Context, *Adjustment) (*AdjustmentResult, error)
+	// ViewInventoryBatch answers each request of the stream in order. A request that fails
+	// gets a response carrying the error; the stream goes on.
+	ViewInventoryBatch(grpc.BidiStreamingServer[ViewInventoryRequest, ViewInventoryResponse]) error
+	// AdjustInventory applies each adjustment of the stream in order, each in its own
+	// transaction, and answers with its result. A failed adjustment does not end the stream.
+	AdjustInventory(grpc.BidiStreamingServer[Adjustment, AdjustmentResult]) error
+	mustEmbedUnimplementedInventoryServer()
+}
+
+// UnimplementedInventoryServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInventoryServer struct{}
+
+func (UnimplementedInventoryServer) ViewInventory(context.Context, *ViewInventoryRequest) (*ViewInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ViewInventory not implemented")
+}
+func (UnimplementedInventoryServer) AdjustOne(context.Context, *Adjustment) (*AdjustmentResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustOne not implemented")
+}
+func (UnimplementedInventoryServer) ViewInventoryBatch(grpc.BidiStreamingServer[ViewInventoryRequest, ViewInventoryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ViewInventoryBatch not implemented")
+}
+func (UnimplementedInventoryServer) AdjustInventory(grpc.BidiStreamingServer[Adjustment, AdjustmentResult]) error {
+	return status.Errorf(codes.Unimplemented, "method AdjustInventory not implemented")
+}
+func (UnimplementedInventoryServer) mustEmbedUnimplementedInventoryServer() {}
+func (UnimplementedInventoryServer) testEmbeddedByValue()                   {}
+
+// UnsafeInventoryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryServer will
+// result in compilation errors.
+type UnsafeInventoryServer interface {
+	mustEmbedUnimplementedInventoryServer()
+}
+
+func RegisterInventoryServer(s grpc.ServiceRegistrar, srv InventoryServer) {
+	// If the following call pancis, it indicates UnimplementedInventoryServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Inventory_ServiceDesc, srv)
+}
+
+func _Inventory_ViewInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ViewInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServer).ViewInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Inventory_ViewInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServer).ViewInventory(ctx, req.(*ViewInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Inventory_AdjustOne_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Adjustment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServer).AdjustOne(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Inventory_AdjustOne_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServer).AdjustOne(ctx, req.(*Adjustment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Inventory_ViewInventoryBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InventoryServer).ViewInventoryBatch(&grpc.GenericServerStream[ViewInventoryRequest, ViewInventoryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Inventory_ViewInventoryBatchServer = grpc.BidiStreamingServer[ViewInventoryRequest, ViewInventoryResponse]
+
+func _Inventory_AdjustInventory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InventoryServer).AdjustInventory(&grpc.GenericServerStream[Adjustment, AdjustmentResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Inventory_AdjustInventoryServer = grpc.BidiStreamingServer[Adjustment, AdjustmentResult]
+
+// Inventory_ServiceDesc is the grpc.ServiceDesc for Inventory service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Inventory_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ims.v1.Inventory",
+	HandlerType: (*InventoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ViewInventory",
+			Handler:    _Inventory_ViewInventory_Handler,
+		},
+		{
+			MethodName: "AdjustOne",
+			Handler:    _Inventory_AdjustOne_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ViewInventoryBatch",
+			Handler:       _Inventory_ViewInventoryBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AdjustInventory",
+			Handler:       _Inventory_AdjustInventory_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ims/v1/ims.proto",
+}
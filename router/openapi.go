@@ -0,0 +1,162 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/omniful/ims_rohit/handlers"
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/internal/balance"
+	"github.com/omniful/ims_rohit/internal/consumer"
+	"github.com/omniful/ims_rohit/internal/gqlapi"
+	"github.com/omniful/ims_rohit/internal/idempotency"
+	"github.com/omniful/ims_rohit/internal/openapi"
+	"github.com/omniful/ims_rohit/internal/stockstream"
+	"github.com/omniful/ims_rohit/internal/webhook"
+	"github.com/omniful/ims_rohit/inventory"
+	"github.com/omniful/ims_rohit/pkg/keyset"
+)
+
+const apiDescription = `Inventory management: hubs, SKUs and their stock.
+
+Responses come in an envelope: {is_success, status_code, data, meta} on success and
+{is_success, status_code, error: {code, message, errors}} on failure, where errors lists
+the fields failing validation. Fields a request schema does not list are ignored.`
+
+// hubScopeQuery narrows hub listings to some of the caller's hubs, see access_control.
+type hubScopeQuery struct {
+	HubIDs string `form:"hub_ids"`
+}
+
+type listHubsQuery struct {
+	handlers.ListHubsRequest
+	hubScopeQuery
+}
+
+type nearestHubsQuery struct {
+	handlers.NearestHubsRequest
+	hubScopeQuery
+}
+
+var lastEventID = openapi.Header{
+	Name:        "Last-Event-ID",
+	Description: "ID of the last event received, to resume after it. Takes precedence over last_event_id.",
+}
+
+var ifMatch = openapi.Header{
+	Name:        "If-Match",
+	Description: "ETag of the version the change is based on. Without it, or with *, the change applies to any version.",
+}
+
+// apiSpec describes every route of newRouter. SetupRouter refuses to start when a route
+// is missing, so add new routes here as well.
+func apiSpec() *openapi.Spec {
+	routes := []openapi.Route{
+		{Method: http.MethodGet, Path: "/metrics/inventory-cache", Tag: "metrics", Summary: "Inventory balance cache hit and miss counters",
+			Response: balance.MetricsSnapshot{}, Plain: true, Public: true},
+
+		{Method: http.MethodPost, Path: "/api/v1/hubs/", Tag: "hubs", Summary: "Create a hub",
+			Description: "The ETag header carries the hub's version for If-Match.",
+			Body:        inventory.Hub{}, Status: http.StatusCreated, Response: handlers.HubResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/hubs/", Tag: "hubs", Summary: "List hubs",
+			Description: "Sorts by id, name, created_at or updated_at. status takes a comma-separated list.",
+			Query:       listHubsQuery{}, Response: []*inventory.Hub{}, Meta: inventory.PageMeta{}},
+		{Method: http.MethodGet, Path: "/api/v1/hubs/nearest", Tag: "hubs", Summary: "Hubs closest to a point, optionally with a SKU available",
+			Description: "The point is given as lat and lng or as a postcode. With sku_id, only hubs with qty of the SKU available, " +
+				"not reserved for orders, are returned.",
+			Query: nearestHubsQuery{}, Response: []*inventory.NearbyHub{}},
+		{Method: http.MethodGet, Path: "/api/v1/hubs/utilisation", Tag: "hubs", Summary: "Capacity utilisation of hubs",
+			Query: hubScopeQuery{}, Response: []*inventory.HubUtilisation{}},
+		{Method: http.MethodGet, Path: "/api/v1/hubs/:id", Tag: "hubs", Summary: "Get a hub",
+			Description: "The ETag header carries the hub's version for If-Match.",
+			Response:    handlers.HubResponse{}},
+		{Method: http.MethodPut, Path: "/api/v1/hubs/:id", Tag: "hubs", Summary: "Update a hub",
+			Description: "status is rejected; change it with PUT /api/v1/hubs/:id/status. The ETag header carries the new version.",
+			Body:        inventory.Hub{}, Headers: []openapi.Header{ifMatch}, Response: handlers.HubResponse{}},
+		{Method: http.MethodPut, Path: "/api/v1/hubs/:id/status", Tag: "hubs", Summary: "Change a hub's status",
+			Body: handlers.UpdateHubStatusRequest{}, Headers: []openapi.Header{ifMatch}, Response: handlers.HubStatusResponse{}},
+		{Method: http.MethodDelete, Path: "/api/v1/hubs/:id", Tag: "hubs", Summary: "Delete a hub",
+			Headers: []openapi.Header{ifMatch}, Status: http.StatusNoContent},
+
+		{Method: http.MethodPut, Path: "/api/v1/postcodes/", Tag: "hubs", Summary: "Load the tenant's postcode centroids for nearest hub lookups",
+			Body: handlers.UpsertPostcodesRequest{}},
+
+		{Method: http.MethodPost, Path: "/api/v1/skus/", Tag: "skus", Summary: "Create a SKU",
+			Body: inventory.SKU{}, Status: http.StatusCreated, Response: inventory.SKU{}},
+		{Method: http.MethodGet, Path: "/api/v1/skus/", Tag: "skus", Summary: "List SKUs",
+			Description: "Sorts by id, sku_code, name, created_at or updated_at. sku_code takes a comma-separated list.",
+			Query:       handlers.ListSKUsRequest{}, Response: []*inventory.SKU{}, Meta: inventory.PageMeta{}},
+		{Method: http.MethodGet, Path: "/api/v1/skus/:id", Tag: "skus", Summary: "Get a SKU",
+			Description: "The ETag header carries the SKU's version for If-Match.",
+			Response:    inventory.SKU{}},
+		{Method: http.MethodPut, Path: "/api/v1/skus/:id", Tag: "skus", Summary: "Update a SKU",
+			Body: inventory.SKU{}, Headers: []openapi.Header{ifMatch}, Response: inventory.SKU{}},
+		{Method: http.MethodDelete, Path: "/api/v1/skus/:id", Tag: "skus", Summary: "Delete a SKU",
+			Headers: []openapi.Header{ifMatch}, Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/api/v1/skus/validate", Tag: "skus", Summary: "Check which SKUs exist",
+			Body: handlers.CheckSKUsExistenceRequest{}, Response: handlers.CheckSKUsExistenceResponse{}},
+
+		{Method: http.MethodGet, Path: "/api/v1/audit", Tag: "audit", Summary: "List audit log entries",
+			Query: handlers.ListAuditLogsRequest{}, Response: []*audit.Entry{}, Meta: keyset.Meta{}},
+
+		{Method: http.MethodPost, Path: "/api/v1/webhooks/", Tag: "webhooks", Summary: "Subscribe to events",
+			Body: webhook.Subscription{}, Status: http.StatusCreated, Response: webhook.Subscription{}},
+		{Method: http.MethodGet, Path: "/api/v1/webhooks/", Tag: "webhooks", Summary: "List webhook subscriptions",
+			Query: handlers.ListWebhooksRequest{}, Response: []*webhook.Subscription{}, Meta: keyset.Meta{}},
+		{Method: http.MethodGet, Path: "/api/v1/webhooks/:id", Tag: "webhooks", Summary: "Get a webhook subscription",
+			Response: webhook.Subscription{}},
+		{Method: http.MethodPut, Path: "/api/v1/webhooks/:id", Tag: "webhooks", Summary: "Update a webhook subscription",
+			Body: webhook.Subscription{}, Response: webhook.Subscription{}},
+		{Method: http.MethodDelete, Path: "/api/v1/webhooks/:id", Tag: "webhooks", Summary: "Delete a webhook subscription",
+			Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/api/v1/webhooks/:id/deliveries", Tag: "webhooks", Summary: "List a subscription's deliveries",
+			Query: handlers.ListWebhookDeliveriesRequest{}, Response: []*webhook.Delivery{}, Meta: keyset.Meta{}},
+		{Method: http.MethodPost, Path: "/api/v1/webhooks/deliveries/:id/replay", Tag: "webhooks", Summary: "Deliver an event again",
+			Status: http.StatusAccepted, Response: webhook.Delivery{}},
+
+		{Method: http.MethodGet, Path: "/api/v1/admin/dead-letters/", Tag: "admin", Summary: "List dead-lettered messages",
+			Query: handlers.ListDeadLettersRequest{}, Response: []*consumer.DeadLetter{}, Meta: keyset.Meta{}},
+		{Method: http.MethodPost, Path: "/api/v1/admin/dead-letters/:id/replay", Tag: "admin", Summary: "Queue a dead-lettered message for replay",
+			Status: http.StatusAccepted, Response: consumer.DeadLetter{}},
+
+		{Method: http.MethodPost, Path: "/api/v1/inventory/upsert", Tag: "inventory", Summary: "Add to or take from a SKU's stock at a hub",
+			Body: handlers.UpsertInventoryRequest{}, Response: inventory.UpsertResult{}},
+		{Method: http.MethodPost, Path: "/api/v1/inventory/set", Tag: "inventory", Summary: "Set a SKU's stock at a hub",
+			Body: handlers.SetInventoryRequest{}, Response: inventory.UpsertResult{}},
+		{Method: http.MethodPost, Path: "/api/v1/inventory/view", Tag: "inventory", Summary: "Stock of SKUs at a hub",
+			Description: "Without sku_ids, pages through all of the hub's stock, sorted by sku_id, quantity or updated_at.",
+			Body:        handlers.ViewInventoryRequest{}, Response: []*inventory.Inventory{}, Meta: inventory.PageMeta{}},
+		{Method: http.MethodGet, Path: "/api/v1/inventory/stream", Tag: "inventory", Summary: "Stream stock changes at a hub",
+			Description: "Server-sent events: a stock event, whose data is shown here, for each change of a SKU's stock, " +
+				"and a reset event when changes after the given last event ID are no longer kept, after which the " +
+				"client should read the stock again. A missing hub, or one of another tenant, is a 404.",
+			Query: handlers.StreamInventoryRequest{}, Headers: []openapi.Header{lastEventID},
+			Response: stockstream.Change{}, Plain: true, ContentType: "text/event-stream"},
+		{Method: http.MethodPost, Path: "/api/v1/inventory/counts", Tag: "inventory", Summary: "Submit a cycle count",
+			Body: handlers.SubmitCountRequest{}, Status: http.StatusCreated, Response: inventory.InventoryCount{}},
+		{Method: http.MethodGet, Path: "/api/v1/inventory/counts", Tag: "inventory", Summary: "List cycle counts",
+			Query: handlers.ListCountsRequest{}, Response: []*inventory.InventoryCount{}, Meta: keyset.Meta{}},
+		{Method: http.MethodPost, Path: "/api/v1/inventory/counts/:id/approve", Tag: "inventory", Summary: "Approve a count and apply its variance",
+			Response: handlers.ResolveCountResponse{}},
+		{Method: http.MethodPost, Path: "/api/v1/inventory/counts/:id/reject", Tag: "inventory", Summary: "Reject a count",
+			Response: handlers.ResolveCountResponse{}},
+
+		{Method: http.MethodPost, Path: "/api/v1/graphql", Tag: "graphql", Summary: "Read hubs, SKUs and stock with GraphQL",
+			Description: "Queries Hub, SKU and InventoryBalance objects and their relations; introspect the schema for its " +
+				"fields. Queries nested deeper or costing more than the configured limits are refused. Answers 200 with " +
+				"{data, errors}, errors carrying the error code in extensions.code, unless the body is not a request.",
+			Body: gqlapi.Request{}, Response: gqlapi.Response{}, Plain: true},
+	}
+
+	// Every mutating API route can be retried safely with an Idempotency-Key.
+	for i, r := range routes {
+		if strings.HasPrefix(r.Path, "/api/v1/") && r.Method != http.MethodGet {
+			routes[i].Headers = append(r.Headers, openapi.Header{
+				Name:        idempotency.HeaderKey,
+				Description: "Replays the stored response of an earlier request with the same key.",
+			})
+		}
+	}
+
+	return openapi.New("IMS API", "1.0.0", apiDescription).Add(routes...)
+}
@@ -0,0 +1,52 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omniful/ims_rohit/internal/access_control"
+	"github.com/omniful/ims_rohit/internal/gqlapi"
+	"github.com/omniful/ims_rohit/internal/openapi"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+// specPath turns gin's :id and *path segments into the {id} and {path} of the document.
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestSpecDescribesRegisteredRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec := apiSpec()
+	r := newRouter(spec, access_control.NewAccessControl(nil, nil), inventory.NewMemoryRepository(), routerConfig{
+		graphQLLimits: gqlapi.Limits{MaxDepth: 10, MaxComplexity: 100},
+	})
+	paths := spec.Document().Paths
+
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		if route.Path == openapi.SpecPath || route.Path == openapi.ViewerPath {
+			continue
+		}
+		path, method := specPath(route.Path), strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+		if _, ok := paths[path][method]; !ok {
+			t.Errorf("%s %s is registered but missing from the spec", route.Method, route.Path)
+		}
+	}
+
+	for path, item := range paths {
+		for method := range item {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is in the spec but not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
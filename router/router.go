@@ -1,44 +1,184 @@
 package router
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/omniful/api-gateway/pkg/redis"
+	"github.com/omniful/api-gateway/pkg/serializer"
+	"github.com/omniful/go_commons/config"
+	"github.com/omniful/go_commons/env"
+	"github.com/omniful/go_commons/jwt/private"
+	pkgcache "github.com/omniful/go_commons/redis_cache"
 	"github.com/omniful/ims_rohit/handlers"
+	"github.com/omniful/ims_rohit/http"
+	"github.com/omniful/ims_rohit/internal/access_control"
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/internal/balance"
+	"github.com/omniful/ims_rohit/internal/gqlapi"
+	"github.com/omniful/ims_rohit/internal/hub"
+	"github.com/omniful/ims_rohit/internal/idempotency"
+	"github.com/omniful/ims_rohit/internal/openapi"
+	"github.com/omniful/ims_rohit/internal/permission"
+	"github.com/omniful/ims_rohit/internal/seller"
+	"github.com/omniful/ims_rohit/inventory"
+	"github.com/omniful/ims_rohit/pkg/response"
 )
 
 // SetupRouter configures all routes for the application
-func SetupRouter() *gin.Engine {
+func SetupRouter(ctx context.Context) *gin.Engine {
+	redisClient := pkgcache.NewRedisCacheClient(redis.GetClient().Client, serializer.NewMsgpackSerializer(), config.GetString(ctx, "service.name"))
+	hubCache, cacheErr := hub.NewCache(ctx, redisClient)
+	if cacheErr != nil {
+		panic(cacheErr)
+	}
+	sellerCache, cacheErr := seller.NewCache(ctx, redisClient)
+	if cacheErr != nil {
+		panic(cacheErr)
+	}
+
+	spec := apiSpec()
+	r := newRouter(spec, access_control.NewAccessControl(hubCache, sellerCache), inventory.NewPostgresRepository(), routerConfig{
+		idempotencyTTL:         config.GetDuration(ctx, "idempotency.ttl"),
+		idempotencyLockTimeout: config.GetDuration(ctx, "idempotency.lock_timeout"),
+		graphQLLimits: gqlapi.Limits{
+			MaxDepth:      config.GetInt(ctx, "graphql.max_depth"),
+			MaxComplexity: config.GetInt(ctx, "graphql.max_complexity"),
+		},
+	})
+	if err := spec.Check(r.Routes()); err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// repository stores hubs, SKUs and their stock.
+type repository interface {
+	inventory.HubRepository
+	inventory.SKURepository
+	inventory.InventoryRepository
+}
+
+// routerConfig holds the configured settings of the routes.
+type routerConfig struct {
+	idempotencyTTL         time.Duration
+	idempotencyLockTimeout time.Duration
+	graphQLLimits          gqlapi.Limits
+}
+
+// newRouter registers the routes described by spec on a new engine. It reads no
+// configuration and opens no connection, so tests can build it.
+func newRouter(spec *openapi.Spec, accessControl *access_control.AccessControl, repo repository, cfg routerConfig) *gin.Engine {
 	r := gin.Default()
+	// Handlers pass the gin context down to the inventory package, which reads the tenant
+	// from it; fall back to the request context for cancellation and deadlines.
+	r.ContextWithFallback = true
+	// The engine replaces the server's default one, so re-add request IDs and the request
+	// logger they feed; audit entries record the request ID.
+	r.Use(env.RequestID(), http.LoggerContextMiddleware())
+
+	// OpenAPI document and its viewer. Requests to the API are validated against it.
+	spec.Register(r)
+
+	// Inventory balance cache hit/miss counters
+	r.GET("/metrics/inventory-cache", balance.MetricsHandler)
 
-	// API v1 group
-	v1 := r.Group("/api/v1")
+	h := handlers.New(repo, repo, repo, response.NewResponseHandler(accessControl))
+
+	// Hub and seller scope checks. Routes addressing one hub take it from the path or body;
+	// list routes store the caller's permitted hubs/sellers in the context for filtering.
+	var (
+		hubFromPath     = accessControl.RequireHubAccess(access_control.FromParam("id"))
+		hubFromBody     = accessControl.RequireHubAccess(access_control.FromJSONBody("hub_id"))
+		hubsFromQuery   = accessControl.RequireHubAccess(access_control.FromQuery("hub_ids"))
+		hubFromQuery    = accessControl.RequireHubAccess(access_control.FromQuery("hub_id"))
+		sellerFromBody  = accessControl.RequireSellerAccess(access_control.FromJSONBody("seller_id"))
+		sellerFromQuery = accessControl.RequireSellerAccess(access_control.FromQuery("seller_id"))
+		sellerScope     = accessControl.RequireSellerAccess(access_control.CallerScope)
+	)
+
+	// API v1 group. Requests must match their description in apiSpec. Write routes also
+	// check the caller's role grants the action, see internal/permission for the role
+	// mapping. POST, PUT and DELETE requests may carry an Idempotency-Key header to be
+	// safely retried.
+	v1 := r.Group("/api/v1", private.AuthenticateJWT(), spec.ValidateRequests(), audit.Middleware(),
+		idempotency.Middleware(cfg.idempotencyTTL, cfg.idempotencyLockTimeout))
 	{
 		// Hub routes
 		hubRoutes := v1.Group("/hubs")
 		{
-			hubRoutes.POST("/", handlers.CreateHubHandler)
-			hubRoutes.GET("/", handlers.ListHubsHandler)
-			hubRoutes.GET("/:id", handlers.GetHubHandler)
-			hubRoutes.PUT("/:id", handlers.UpdateHubHandler)
-			hubRoutes.DELETE("/:id", handlers.DeleteHubHandler)
+			hubRoutes.POST("/", permission.Require(permission.HubWrite), h.CreateHubHandler)
+			hubRoutes.GET("/", hubsFromQuery, h.ListHubsHandler)
+			hubRoutes.GET("/nearest", hubsFromQuery, handlers.NearestHubsHandler)
+			hubRoutes.GET("/utilisation", hubsFromQuery, handlers.HubUtilisationHandler)
+			hubRoutes.GET("/:id", hubFromPath, h.GetHubHandler)
+			hubRoutes.PUT("/:id", permission.Require(permission.HubWrite), hubFromPath, h.UpdateHubHandler)
+			hubRoutes.PUT("/:id/status", permission.Require(permission.HubWrite), hubFromPath, h.UpdateHubStatusHandler)
+			hubRoutes.DELETE("/:id", permission.Require(permission.HubAdmin), hubFromPath, h.DeleteHubHandler)
 		}
 
-		// SKU routes
+		// Postcode lookup routes
+		postcodeRoutes := v1.Group("/postcodes")
+		{
+			postcodeRoutes.PUT("/", permission.Require(permission.HubAdmin), handlers.UpsertPostcodesHandler)
+		}
+
+		// SKU routes. Routes by SKU id check the SKU's seller in the handler once it is loaded.
 		skuRoutes := v1.Group("/skus")
 		{
-			skuRoutes.POST("/", handlers.CreateSKUHandler)
-			skuRoutes.GET("/", handlers.ListSKUsHandler)
-			skuRoutes.GET("/:id", handlers.GetSKUHandler)
-			skuRoutes.PUT("/:id", handlers.UpdateSKUHandler)
-			skuRoutes.DELETE("/:id", handlers.DeleteSKUHandler)
-			skuRoutes.POST("/validate", handlers.CheckSKUsExistenceHandler)
+			skuRoutes.POST("/", permission.Require(permission.SKUWrite), sellerFromBody, h.CreateSKUHandler)
+			skuRoutes.GET("/", sellerFromQuery, h.ListSKUsHandler)
+			skuRoutes.GET("/:id", h.GetSKUHandler)
+			skuRoutes.PUT("/:id", permission.Require(permission.SKUWrite), h.UpdateSKUHandler)
+			skuRoutes.DELETE("/:id", permission.Require(permission.SKUWrite), h.DeleteSKUHandler)
+			skuRoutes.POST("/validate", sellerScope, h.CheckSKUsExistenceHandler)
+		}
+
+		// Audit trail
+		v1.GET("/audit", permission.Require(permission.AuditRead), handlers.ListAuditLogsHandler)
+
+		// Webhook subscriptions and their delivery log
+		webhookRoutes := v1.Group("/webhooks", permission.Require(permission.WebhookAdmin))
+		{
+			webhookRoutes.POST("/", handlers.CreateWebhookHandler)
+			webhookRoutes.GET("/", handlers.ListWebhooksHandler)
+			webhookRoutes.GET("/:id", handlers.GetWebhookHandler)
+			webhookRoutes.PUT("/:id", handlers.UpdateWebhookHandler)
+			webhookRoutes.DELETE("/:id", handlers.DeleteWebhookHandler)
+			webhookRoutes.GET("/:id/deliveries", handlers.ListWebhookDeliveriesHandler)
+			webhookRoutes.POST("/deliveries/:id/replay", handlers.ReplayWebhookDeliveryHandler)
 		}
 
-		// Inventory routes
+		// Dead-lettered Kafka messages. Replays run in the worker that owns the handler.
+		deadLetterRoutes := v1.Group("/admin/dead-letters", permission.Require(permission.ConsumerAdmin))
+		{
+			deadLetterRoutes.GET("/", handlers.ListDeadLettersHandler)
+			deadLetterRoutes.POST("/:id/replay", handlers.ReplayDeadLetterHandler)
+		}
+
+		// Inventory routes. Stock of SKUs of sellers outside the caller's seller scope is
+		// treated as missing.
 		inventoryRoutes := v1.Group("/inventory")
 		{
-			inventoryRoutes.POST("/upsert", handlers.UpsertInventoryHandler)
-			inventoryRoutes.POST("/view", handlers.ViewInventoryHandler)
+			inventoryRoutes.POST("/upsert", permission.Require(permission.InventoryDecrement, permission.InventoryAdjust), hubFromBody, sellerScope, h.UpsertInventoryHandler)
+			inventoryRoutes.POST("/set", permission.Require(permission.InventorySet), hubFromBody, sellerScope, h.SetInventoryHandler)
+			inventoryRoutes.POST("/view", hubFromBody, sellerScope, h.ViewInventoryHandler)
+			inventoryRoutes.GET("/stream", hubFromQuery, sellerScope, handlers.StreamInventoryHandler)
+			inventoryRoutes.POST("/counts", permission.Require(permission.InventoryCount), hubFromBody, sellerScope, handlers.SubmitCountHandler)
+			inventoryRoutes.GET("/counts", hubFromQuery, handlers.ListCountsHandler)
+			inventoryRoutes.POST("/counts/:id/approve", permission.Require(permission.InventoryApproveVariance), h.ApproveCountHandler)
+			inventoryRoutes.POST("/counts/:id/reject", permission.Require(permission.InventoryApproveVariance), h.RejectCountHandler)
+		}
+
+		// GraphQL reads of hubs, SKUs and stock. Access is checked per field, like the
+		// handlers check responses.
+		graphQL, err := gqlapi.New(repo, repo, repo, accessControl, cfg.graphQLLimits)
+		if err != nil {
+			panic(err)
 		}
+		v1.POST("/graphql", graphQL.Handler)
 	}
 
 	return r
@@ -3,18 +3,23 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
 
 	"github.com/omniful/api-gateway/pkg/redis"
 	"github.com/omniful/api-gateway/pkg/serializer"
 	"github.com/omniful/go_commons/config"
-	"github.com/omniful/go_commons/log"
 	"github.com/omniful/go_commons/pubsub"
 	pkgcache "github.com/omniful/go_commons/redis_cache"
+	"github.com/omniful/ims_rohit/internal/consumer"
 	"github.com/omniful/ims_rohit/internal/hub"
+	"github.com/omniful/ims_rohit/inventory"
 )
 
 const (
+	HubCreate = "hubs.create.event"
 	HubUpdate = "hubs.update.event"
+	HubDelete = "hubs.delete.event"
 )
 
 type Hub struct {
@@ -38,27 +43,47 @@ func NewInvalidateHubCacheHandler(ctx context.Context) *InvalidateHubCache {
 	}
 }
 
-func isHubEventValid(event string) bool {
-	return event == HubUpdate
+type hubEventStep func(c *InvalidateHubCache, ctx context.Context, h *Hub) error
+
+// hubEventSteps lists, per hub lifecycle event, what to do with it. Events not listed are
+// ignored; to handle a new one, add its steps here.
+var hubEventSteps = map[string][]hubEventStep{
+	HubCreate: {(*InvalidateHubCache).invalidateTenantHubs},
+	HubUpdate: {(*InvalidateHubCache).invalidateTenantHubs},
+	HubDelete: {(*InvalidateHubCache).decommissionHub, (*InvalidateHubCache).invalidateTenantHubs},
+}
+
+func (c *InvalidateHubCache) invalidateTenantHubs(ctx context.Context, h *Hub) error {
+	return c.hubCache.InvalidateTenantHubIDs(ctx, h.TenantID)
+}
+
+// decommissionHub stops stock being written to a hub deleted upstream.
+func (c *InvalidateHubCache) decommissionHub(ctx context.Context, h *Hub) error {
+	tenantID, err := strconv.ParseInt(h.TenantID, 10, 64)
+	if err != nil {
+		return consumer.Permanent(fmt.Errorf("invalid tenant id %q in hub event", h.TenantID))
+	}
+	return inventory.DecommissionHub(ctx, tenantID, int64(h.ID))
 }
 
 func (c *InvalidateHubCache) Process(ctx context.Context, message *pubsub.Message) error {
 	event := message.Headers["event"]
 	s := Hub{}
 
-	if !isHubEventValid(event) {
+	steps, ok := hubEventSteps[event]
+	if !ok {
 		return nil
 	}
 
 	err := json.Unmarshal(message.Value, &s)
 	if err != nil {
-		log.Errorf("Error in unmarshalling hub update request: %s", err.Error())
-		return nil
+		return consumer.Permanent(fmt.Errorf("error in unmarshalling hub update request: %w", err))
 	}
 
-	err = c.hubCache.InvalidateTenantHubIDs(ctx, s.TenantID)
-	if err != nil {
-		return err
+	for _, step := range steps {
+		if err = step(c, ctx, &s); err != nil {
+			return err
+		}
 	}
 
 	return nil
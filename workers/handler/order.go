@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/omniful/go_commons/log"
+	"github.com/omniful/go_commons/pubsub"
+	"github.com/omniful/ims_rohit/internal/consumer"
+	"github.com/omniful/ims_rohit/inventory"
+)
+
+const (
+	OrderCreate = "order.create.event"
+	OrderCancel = "order.cancel.event"
+	OrderShip   = "order.ship.event"
+)
+
+// OrderStock reserves, releases and deducts stock as orders move through their lifecycle.
+// Every step is idempotent by order ID, so redelivered messages are safe.
+type OrderStock struct{}
+
+func NewOrderStockHandler(ctx context.Context) *OrderStock {
+	return &OrderStock{}
+}
+
+// Process returns an error for messages it could not apply so they are retried, and
+// dead-lettered once retries run out. Malformed messages are dead-lettered straight away.
+func (h *OrderStock) Process(ctx context.Context, message *pubsub.Message) error {
+	event := message.Headers["event"]
+
+	var apply func(context.Context, *inventory.Order) (inventory.ReservationStatus, error)
+	switch event {
+	case OrderCreate:
+		apply = inventory.ReserveOrder
+	case OrderCancel:
+		apply = inventory.ReleaseOrder
+	case OrderShip:
+		apply = inventory.ShipOrder
+	default:
+		return nil
+	}
+
+	order := &inventory.Order{}
+	if err := json.Unmarshal(message.Value, order); err != nil {
+		return consumer.Permanent(fmt.Errorf("error in unmarshalling %s: %w", event, err))
+	}
+	if err := order.Validate(); err != nil {
+		return consumer.Permanent(fmt.Errorf("invalid %s: %w", event, err))
+	}
+
+	status, err := apply(ctx, order)
+	if err != nil {
+		log.Errorf("Error processing %s for order %s: %s", event, order.ID, err.Error())
+		return err
+	}
+	log.Infof("Processed %s for order %s, reservation is %s", event, order.ID, status)
+	return nil
+}
@@ -3,16 +3,17 @@ package handler
 import (
 	"context"
 	"encoding/json"
-
+	"fmt"
 	"strconv"
 
 	"github.com/omniful/api-gateway/pkg/redis"
 	"github.com/omniful/api-gateway/pkg/serializer"
 	"github.com/omniful/go_commons/config"
-	"github.com/omniful/go_commons/log"
 	"github.com/omniful/go_commons/pubsub"
 	pkgcache "github.com/omniful/go_commons/redis_cache"
+	"github.com/omniful/ims_rohit/internal/consumer"
 	"github.com/omniful/ims_rohit/internal/seller"
+	"github.com/omniful/ims_rohit/inventory"
 )
 
 const (
@@ -24,6 +25,7 @@ const (
 type Seller struct {
 	ID       uint64 `json:"id"`
 	TenantID uint64 `json:"tenant_id"`
+	Status   string `json:"status"`
 }
 
 type InvalidateSellerCache struct {
@@ -42,31 +44,54 @@ func NewInvalidateSellerCacheHandler(ctx context.Context) *InvalidateSellerCache
 	}
 }
 
-func isEventValid(event string) bool {
-	if event == SellerCreate {
-		return true
-	}
+type sellerEventStep func(c *InvalidateSellerCache, ctx context.Context, s *Seller) error
+
+// sellerEventSteps lists, per seller lifecycle event, what to do with it. Events not
+// listed are ignored; to handle a new one, add its steps here.
+var sellerEventSteps = map[string][]sellerEventStep{
+	SellerCreate:   {(*InvalidateSellerCache).invalidateTenantSellers},
+	SellerUpdate:   {(*InvalidateSellerCache).syncStatus, (*InvalidateSellerCache).invalidateTenantSellers},
+	SellerInactive: {(*InvalidateSellerCache).inactivate, (*InvalidateSellerCache).invalidateTenantSellers},
+}
+
+func (c *InvalidateSellerCache) invalidateTenantSellers(ctx context.Context, s *Seller) error {
+	return c.sellerCache.InvalidateSellerIDs(ctx, strconv.FormatUint(s.TenantID, 10))
+}
 
-	return false
+// inactivate blocks stock writes for the seller's SKUs.
+func (c *InvalidateSellerCache) inactivate(ctx context.Context, s *Seller) error {
+	return inventory.SetSellerStatus(ctx, int64(s.TenantID), int64(s.ID), inventory.SellerStatusInactive)
+}
+
+// syncStatus applies the status carried by update events, which is how an inactive seller
+// is reactivated. Updates without a status leave it unchanged.
+func (c *InvalidateSellerCache) syncStatus(ctx context.Context, s *Seller) error {
+	switch inventory.SellerStatus(s.Status) {
+	case inventory.SellerStatusActive, inventory.SellerStatusInactive:
+		return inventory.SetSellerStatus(ctx, int64(s.TenantID), int64(s.ID), inventory.SellerStatus(s.Status))
+	default:
+		return nil
+	}
 }
 
 func (c *InvalidateSellerCache) Process(ctx context.Context, message *pubsub.Message) error {
 	event := message.Headers["event"]
 	s := Seller{}
 
-	if !isEventValid(event) {
+	steps, ok := sellerEventSteps[event]
+	if !ok {
 		return nil
 	}
 
 	err := json.Unmarshal(message.Value, &s)
 	if err != nil {
-		log.Errorf("Error in unmarshalling seller update request: %s", err.Error())
-		return nil
+		return consumer.Permanent(fmt.Errorf("error in unmarshalling seller update request: %w", err))
 	}
 
-	err = c.sellerCache.InvalidateSellerIDs(ctx, strconv.FormatUint(s.TenantID, 10))
-	if err != nil {
-		return err
+	for _, step := range steps {
+		if err = step(c, ctx, &s); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -8,11 +8,21 @@ import (
 	"github.com/omniful/go_commons/pubsub"
 	"github.com/omniful/go_commons/worker/configs"
 	"github.com/omniful/go_commons/worker/registry"
+	"github.com/omniful/ims_rohit/internal/consumer"
 	"github.com/omniful/ims_rohit/workers/handler"
 )
 
 func registerDefaultListener(ctx context.Context, httpServer *http.Server, registry *registry.Registry) {
 	registry.RegisterHTTPListenerConfig(httpServer, config.GetString(ctx, "service.name"))
+	httpServer.GET("/metrics/consumers", consumer.MetricsHandler)
+}
+
+// consumerConfig is the retry policy every Kafka listener runs with before dead-lettering.
+func consumerConfig(ctx context.Context) consumer.Config {
+	return consumer.Config{
+		MaxAttempts:  config.GetInt(ctx, "consumers.max_attempts"),
+		RetryBackoff: config.GetDuration(ctx, "consumers.retry_backoff"),
+	}
 }
 
 func registerKafkaListeners(ctx context.Context, registry *registry.Registry) {
@@ -20,7 +30,7 @@ func registerKafkaListeners(ctx context.Context, registry *registry.Registry) {
 		ctx,
 		"sellerUpdate",
 		func(handlerCtx context.Context, config configs.KafkaConsumerConfig) pubsub.IPubSubMessageHandler {
-			return handler.NewInvalidateSellerCacheHandler(ctx)
+			return consumer.Wrap("sellerUpdate", handler.NewInvalidateSellerCacheHandler(ctx), consumerConfig(ctx))
 		},
 	)
 
@@ -28,7 +38,15 @@ func registerKafkaListeners(ctx context.Context, registry *registry.Registry) {
 		ctx,
 		"hubUpdate",
 		func(handlerCtx context.Context, config configs.KafkaConsumerConfig) pubsub.IPubSubMessageHandler {
-			return handler.NewInvalidateHubCacheHandler(ctx)
+			return consumer.Wrap("hubUpdate", handler.NewInvalidateHubCacheHandler(ctx), consumerConfig(ctx))
+		},
+	)
+
+	registry.RegisterKafkaListenerConfig(
+		ctx,
+		"orderUpdate",
+		func(handlerCtx context.Context, config configs.KafkaConsumerConfig) pubsub.IPubSubMessageHandler {
+			return consumer.Wrap("orderUpdate", handler.NewOrderStockHandler(ctx), consumerConfig(ctx))
 		},
 	)
 }
@@ -0,0 +1,31 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/omniful/go_commons/config"
+	"github.com/omniful/go_commons/kafka"
+	"github.com/omniful/ims_rohit/internal/outbox"
+)
+
+// startOutboxRelay publishes outbox events to Kafka in the background. Relays claim the
+// events of a key one at a time, so running several workers is safe.
+func startOutboxRelay(ctx context.Context) {
+	if !config.GetBool(ctx, "outbox.enabled") {
+		return
+	}
+
+	producer := kafka.NewProducer(
+		kafka.WithBrokers(config.GetStringSlice(ctx, "kafka.brokers")),
+		kafka.WithClientID(config.GetString(ctx, "kafka.client_id")),
+		kafka.WithKafkaVersion(config.GetString(ctx, "kafka.version")),
+	)
+
+	relay := outbox.NewRelay(producer, outbox.Config{
+		BatchSize:    config.GetInt(ctx, "outbox.batch_size"),
+		PollInterval: config.GetDuration(ctx, "outbox.poll_interval"),
+		MaxAttempts:  config.GetInt(ctx, "outbox.max_attempts"),
+		ClaimTimeout: config.GetDuration(ctx, "outbox.claim_timeout"),
+	})
+	go relay.Run(ctx)
+}
@@ -0,0 +1,27 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/omniful/go_commons/config"
+	"github.com/omniful/ims_rohit/internal/webhook"
+)
+
+// startWebhookDispatcher delivers webhooks in the background. Workers share the work, so
+// several can run at once.
+func startWebhookDispatcher(ctx context.Context) {
+	if !config.GetBool(ctx, "webhooks.enabled") {
+		return
+	}
+
+	dispatcher := webhook.NewDispatcher(webhook.Config{
+		BatchSize:      config.GetInt(ctx, "webhooks.batch_size"),
+		PollInterval:   config.GetDuration(ctx, "webhooks.poll_interval"),
+		RequestTimeout: config.GetDuration(ctx, "webhooks.request_timeout"),
+		MaxAttempts:    config.GetInt(ctx, "webhooks.max_attempts"),
+		BaseBackoff:    config.GetDuration(ctx, "webhooks.base_backoff"),
+		MaxBackoff:     config.GetDuration(ctx, "webhooks.max_backoff"),
+		DisableAfter:   config.GetInt(ctx, "webhooks.disable_after_failures"),
+	})
+	go dispatcher.Run(ctx)
+}
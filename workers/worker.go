@@ -3,10 +3,14 @@ package workers
 import (
 	"context"
 
+	"github.com/omniful/go_commons/config"
 	"github.com/omniful/go_commons/http"
 	"github.com/omniful/go_commons/worker"
 	"github.com/omniful/go_commons/worker/configs"
 	"github.com/omniful/go_commons/worker/registry"
+	"github.com/omniful/ims_rohit/internal/audit"
+	"github.com/omniful/ims_rohit/internal/consumer"
+	"github.com/omniful/ims_rohit/internal/idempotency"
 )
 
 func Run(
@@ -14,10 +18,17 @@ func Run(
 	httpServer *http.Server,
 	serverConfig configs.ServerConfig,
 ) {
+	// Background work changing stock, such as the outbox relay and webhook dispatcher, is
+	// audited as the worker's; consumers set their own actor per message.
+	ctx = audit.WithMetadata(ctx, audit.System("worker"))
 	listenerRegistry := registry.NewRegistry()
 
 	registerDefaultListener(ctx, httpServer, listenerRegistry)
 	registerKafkaListeners(ctx, listenerRegistry)
+	startOutboxRelay(ctx)
+	startWebhookDispatcher(ctx)
+	go consumer.RunReplays(ctx, config.GetDuration(ctx, "consumers.replay_poll_interval"), config.GetInt(ctx, "consumers.replay_batch_size"))
+	go idempotency.RunPurge(ctx, config.GetDuration(ctx, "idempotency.purge_interval"))
 
 	server := worker.NewServerFromRegistry(listenerRegistry)
 	server.RunFromConfig(ctx, serverConfig)